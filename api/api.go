@@ -2,35 +2,46 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
 	"github.com/goware/cors"
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
 	"github.com/murdinc/terminal"
 	"github.com/skratchdot/open-golang/open"
 )
 
-// StartAPI Starts the API listener on port 8081
+// StartAPI Starts the API listener on the port of the current environment (8081 by default)
 func StartAPI(withDashboard bool) error {
+	env := settings.Current()
+	port := strconv.Itoa(env.APIPort)
+
 	r := chi.NewRouter()
 
 	cors := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "X-Awsm-Admin-Token"},
 		AllowCredentials: true,
 	})
 
+	r.Use(RequestLogger)
 	r.Use(cors.Handler)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.StripSlashes)
 	r.Use(middleware.URLFormat)
-	if !withDashboard {
-		r.Use(middleware.Logger)
-	}
 
 	r.Route("/api", func(r chi.Router) {
+		r.Get("/openapi", getOpenAPI) // served as /api/openapi.json, see middleware.URLFormat
 		r.Route("/dashboard", func(r chi.Router) {
 			r.Route("/widgets", func(r chi.Router) {
 				r.Get("/", getWidgets)
@@ -64,7 +75,7 @@ func StartAPI(withDashboard bool) error {
 	})
 
 	if withDashboard {
-		src := "/usr/local/awsmDashboard"
+		src := env.DashboardPath
 
 		_, err := os.Stat(src)
 		if os.IsNotExist(err) {
@@ -84,23 +95,111 @@ func StartAPI(withDashboard bool) error {
 				path = "/index.html"
 			}
 
-			terminal.Information("Serving file: " + src + path)
+			logger.Debug("Serving dashboard file", "file", src+path)
 
 			// return file
 			http.ServeFile(w, r, src+path)
 
 		})
 
-		open.Start("http://localhost:8081")
+		open.Start("http://localhost:" + port)
 	}
 
-	return http.ListenAndServe(":8081", r)
+	logger.Info("Starting the API server", "port", port, "dashboard", withDashboard)
+
+	return http.ListenAndServe(":"+port, r)
+}
+
+// statusWriter keeps the status code of a response for the request log
+type statusWriter struct {
+	http.ResponseWriter
+	status int
 }
 
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// RequestLogger logs every request, server errors at the warn level
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		log := logger.Info
+		if sw.status >= http.StatusInternalServerError {
+			log = logger.Warn
+		}
+		log("API request", "method", r.Method, "path", r.URL.Path, "status", sw.status, "duration", time.Since(start), "remote", r.RemoteAddr)
+	})
+}
+
+// ClassCtx checks the class type of a request and adds it to the request context
 func ClassCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		classType := chi.URLParam(r, "classType")
+		if _, ok := classModels[classType]; !ok {
+			respondError(w, r, http.StatusBadRequest, map[string]interface{}{"classType": classType}, errors.New("Unknown class type ["+classType+"]!"))
+			return
+		}
 		ctx := context.WithValue(r.Context(), "classType", classType)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// requestError is an error in the request itself, like a missing body
+type requestError string
+
+func (e requestError) Error() string {
+	return string(e)
+}
+
+// errorStatus returns the HTTP status code of an error
+func errorStatus(err error) int {
+	switch err.(type) {
+	case requestError, *json.SyntaxError, *json.UnmarshalTypeError:
+		return http.StatusBadRequest
+	case config.NotFoundError:
+		return http.StatusNotFound
+	case config.ConflictError:
+		return http.StatusConflict
+	}
+
+	if err == errNotAuthorized {
+		return http.StatusForbidden
+	}
+
+	return http.StatusInternalServerError
+}
+
+// respond renders a successful response envelope. Every response has `success` and `errors` along with the fields of
+// its endpoint, see /api/openapi.json
+func respond(w http.ResponseWriter, r *http.Request, fields map[string]interface{}) {
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	fields["success"] = true
+	fields["errors"] = []string{}
+	render.JSON(w, r, fields)
+}
+
+// respondError renders a failed response envelope with a status code, and any fields that are still useful (like the
+// assets of the regions that did respond)
+func respondError(w http.ResponseWriter, r *http.Request, status int, fields map[string]interface{}, errs ...error) {
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+
+	errStrs := make([]string, len(errs))
+	for i, e := range errs {
+		errStrs[i] = e.Error()
+	}
+
+	fields["success"] = false
+	fields["errors"] = errStrs
+	render.Status(r, status)
+	render.JSON(w, r, fields)
+}
@@ -1,12 +1,13 @@
 package api
 
 import (
-	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/render"
 	"github.com/murdinc/awsm/aws"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/models"
 )
 
 func getAssets(w http.ResponseWriter, r *http.Request) {
@@ -85,7 +86,8 @@ func getAssets(w http.ResponseWriter, r *http.Request) {
 		*/
 
 	default:
-		err = errors.New("Unknown list type")
+		respondError(w, r, http.StatusBadRequest, map[string]interface{}{"assetType": assetType}, requestError("Unknown asset type ["+assetType+"]!"))
+		return
 	}
 
 	// Combine errors
@@ -93,16 +95,38 @@ func getAssets(w http.ResponseWriter, r *http.Request) {
 		errs = append(errs, err)
 	}
 
-	if len(errs) == 0 {
-		render.JSON(w, r, map[string]interface{}{"assetType": assetType, "assets": resp, "success": true})
-	} else {
+	// Filter by a comma separated list of accounts
+	if accounts := r.URL.Query().Get("account"); accounts != "" && resp != nil {
+		resp = models.FilterByAccount(resp, strings.Split(accounts, ","))
+	}
 
-		errStrs := make([]string, len(errs))
+	// Group by account
+	if r.URL.Query().Get("groupBy") == "account" && resp != nil {
+		resp = models.GroupByAccount(resp)
+	}
 
-		for i, e := range errs {
-			errStrs[i] = e.Error()
+	// Count the regions that AWS throttled
+	var throttled int
+	for _, e := range errs {
+		if gatherErr, ok := e.(aws.GatherError); ok {
+			e = gatherErr.Err
 		}
+		if sessions.IsThrottle(e) {
+			throttled++
+		}
+	}
 
-		render.JSON(w, r, map[string]interface{}{"assetType": assetType, "assets": resp, "success": false, "errors": errStrs})
+	fields := map[string]interface{}{"assetType": assetType, "assets": resp, "throttled": throttled}
+
+	if len(errs) > 0 {
+		// AWS errors, the assets of the regions that did respond are still returned
+		status := http.StatusBadGateway
+		if throttled == len(errs) {
+			status = http.StatusTooManyRequests
+		}
+		respondError(w, r, status, fields, errs...)
+		return
 	}
+
+	respond(w, r, fields)
 }
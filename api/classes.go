@@ -1,36 +1,49 @@
 package api
 
 import (
+	"crypto/subtle"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"os"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/render"
 	"github.com/murdinc/awsm/config"
 )
 
+// errNotAuthorized is returned when secrets are asked for without the admin token
+var errNotAuthorized = errors.New("Not authorized to view secrets!")
+
 func exportClasses(w http.ResponseWriter, r *http.Request) {
 
-	resp, err := config.Export()
+	export, err := config.Export()
+
+	var resp interface{}
+	if err == nil {
+		resp, err = secureClasses(r, export)
+	}
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), nil, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classes": resp, "success": true})
+	respond(w, r, map[string]interface{}{"classes": resp})
 }
 
 func getClasses(w http.ResponseWriter, r *http.Request) {
 	classType := r.Context().Value("classType").(string)
 	resp, err := config.LoadAllClasses(classType)
+	if err == nil {
+		resp, err = secureClasses(r, resp)
+	}
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"classType": classType}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classType": classType, "classes": resp, "success": true})
+	respond(w, r, map[string]interface{}{"classType": classType, "classes": resp})
 }
 
 func getClassOptions(w http.ResponseWriter, r *http.Request) {
@@ -38,11 +51,11 @@ func getClassOptions(w http.ResponseWriter, r *http.Request) {
 	resp, err := config.LoadAllClassOptions(classType)
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"classType": classType}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classType": classType, "classOptions": resp, "success": true})
+	respond(w, r, map[string]interface{}{"classType": classType, "classOptions": resp})
 }
 
 func getClassNames(w http.ResponseWriter, r *http.Request) {
@@ -50,11 +63,11 @@ func getClassNames(w http.ResponseWriter, r *http.Request) {
 	resp, err := config.LoadAllClassNames(classType)
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"classType": classType}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classType": classType, "classNames": resp, "success": true})
+	respond(w, r, map[string]interface{}{"classType": classType, "classNames": resp})
 }
 
 func getClassByName(w http.ResponseWriter, r *http.Request) {
@@ -62,13 +75,16 @@ func getClassByName(w http.ResponseWriter, r *http.Request) {
 	className := chi.URLParam(r, "className")
 
 	resp, err := config.LoadClassByName(classType, className)
+	if err == nil {
+		resp, err = secureClasses(r, resp)
+	}
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"classType": classType, "className": className}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classType": classType, "className": className, "class": resp, "success": true})
+	respond(w, r, map[string]interface{}{"classType": classType, "className": className, "class": resp})
 }
 
 func deleteClass(w http.ResponseWriter, r *http.Request) {
@@ -77,78 +93,62 @@ func deleteClass(w http.ResponseWriter, r *http.Request) {
 
 	err := config.DeleteClass(classType, className)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"classType": classType, "className": className}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"success": true})
+	respond(w, r, map[string]interface{}{"classType": classType, "className": className})
 }
 
 func putClass(w http.ResponseWriter, r *http.Request) {
 	classType := r.Context().Value("classType").(string)
 	className := chi.URLParam(r, "className")
+	fields := map[string]interface{}{"classType": classType, "className": className}
 
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"Error Reading Body!", err.Error()}})
+		respondError(w, r, http.StatusBadRequest, fields, requestError("Error Reading Body!"), err)
 		return
 	}
 
 	// check for empty body?
 	if len(data) == 0 {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"No class object was passed!"}})
+		respondError(w, r, http.StatusBadRequest, fields, requestError("No class object was passed!"))
 		return
 	}
 
-	var class interface{}
-
-	switch classType {
-
-	case "vpcs":
-		class, err = config.SaveVpcClass(className, data)
-
-	case "subnets":
-		class, err = config.SaveSubnetClass(className, data)
-
-	case "instances":
-		class, err = config.SaveInstanceClass(className, data)
-
-	case "volumes":
-		class, err = config.SaveVolumeClass(className, data)
-
-	case "snapshots":
-		class, err = config.SaveSnapshotClass(className, data)
-
-	case "images":
-		class, err = config.SaveImageClass(className, data)
+	class, err := config.SaveClass(classType, className, data)
 
-	case "autoscalegroups":
-		class, err = config.SaveAutoscalingGroupClass(className, data)
-
-	case "launchconfigurations":
-		class, err = config.SaveLaunchConfigurationClass(className, data)
-
-	case "loadbalancers":
-		class, err = config.SaveLoadBalancerClass(className, data)
-
-	case "scalingpolicies":
-		class, err = config.SaveScalingPolicyClass(className, data)
-
-	case "alarms":
-		class, err = config.SaveAlarmClass(className, data)
+	if conflict, ok := err.(config.ConflictError); ok {
+		// Return the current version of the class so it can be merged and resubmitted
+		current, _ := config.LoadClassByName(classType, className)
+		fields["class"] = config.RedactSecrets(current)
+		fields["revision"] = conflict.Revision
+		respondError(w, r, http.StatusConflict, fields, conflict)
+		return
+	}
 
-	case "securitygroups":
-		class, err = config.SaveSecurityGroupClass(className, data)
+	if err != nil {
+		respondError(w, r, errorStatus(err), fields, errors.New("Error saving Class!"), err)
+		return
+	}
 
-	case "keypairs":
-		class, err = config.SaveKeyPairClass(className, data)
+	fields["class"] = config.RedactSecrets(class)
+	respond(w, r, fields)
+}
 
+// secureClasses redacts the secret fields of classes, or decrypts them if the request asked for them with
+// `?secrets=true` and passed the admin token (AWSM_ADMIN_TOKEN) in the X-Awsm-Admin-Token header
+func secureClasses(r *http.Request, classes interface{}) (interface{}, error) {
+	if r.URL.Query().Get("secrets") != "true" {
+		return config.RedactSecrets(classes), nil
 	}
 
-	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"Error saving Class!", err.Error()}})
-		return
+	adminToken := os.Getenv("AWSM_ADMIN_TOKEN")
+	requestToken := r.Header.Get("X-Awsm-Admin-Token")
+	if adminToken == "" || subtle.ConstantTimeCompare([]byte(adminToken), []byte(requestToken)) != 1 {
+		return nil, errNotAuthorized
 	}
 
-	render.JSON(w, r, map[string]interface{}{"classType": classType, "class": class, "success": true})
+	return config.OpenSecrets(classes)
 }
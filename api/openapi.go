@@ -0,0 +1,542 @@
+package api
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/models"
+)
+
+// Version is the awsm version reported in the OpenAPI document
+var Version = "dev"
+
+// assetModels are the models of each {assetType} of /api/assets/{assetType}
+var assetModels = map[string]interface{}{
+	"addresses":            models.Address{},
+	"alarms":               models.Alarm{},
+	"autoscalegroups":      models.AutoScaleGroup{},
+	"buckets":              models.Bucket{},
+	"iaminstanceprofiles":  models.IAMInstanceProfile{},
+	"iamroles":             models.IAMRole{},
+	"iamusers":             models.IAMUser{},
+	"images":               models.Image{},
+	"instances":            models.Instance{},
+	"instances-running":    models.Instance{},
+	"keypairs":             models.KeyPair{},
+	"launchconfigurations": models.LaunchConfig{},
+	"loadbalancers":        models.LoadBalancer{},
+	"scalingpolicies":      models.ScalingPolicy{},
+	"securitygroups":       models.SecurityGroup{},
+	"simpledbdomains":      models.SimpleDBDomain{},
+	"snapshots":            models.Snapshot{},
+	"subnets":              models.Subnet{},
+	"volumes":              models.Volume{},
+	"vpcs":                 models.Vpc{},
+}
+
+// classModels are the config types of each {classType} of /api/classes/{classType}
+var classModels = map[string]interface{}{
+	"vpcs":                 config.VpcClass{},
+	"subnets":              config.SubnetClass{},
+	"instances":            config.InstanceClass{},
+	"volumes":              config.VolumeClass{},
+	"snapshots":            config.SnapshotClass{},
+	"images":               config.ImageClass{},
+	"autoscalegroups":      config.AutoscaleGroupClass{},
+	"launchconfigurations": config.LaunchConfigurationClass{},
+	"loadbalancers":        config.LoadBalancerClass{},
+	"scalingpolicies":      config.ScalingPolicyClass{},
+	"alarms":               config.AlarmClass{},
+	"securitygroups":       config.SecurityGroupClass{},
+	"keypairs":             config.KeyPairClass{},
+}
+
+// operation documents a route of the API
+type operation struct {
+	id      string
+	summary string
+	tag     string
+	query   map[string]string // query parameters and their descriptions
+	body    func(kind string) interface{}
+	fields  func(kind string) map[string]interface{} // the fields of the response envelope, by a value of their type
+	raw     bool                                     // the response is not wrapped in an envelope
+	ext     string                                   // the extension the route is served with, see middleware.URLFormat
+}
+
+// properties is an object schema with these properties, by a value of their type
+type properties map[string]interface{}
+
+// oneOf is a schema of any one of these values' types
+type oneOf []interface{}
+
+// static returns the envelope fields of an operation that does not depend on the asset or class type
+func static(fields map[string]interface{}) func(string) map[string]interface{} {
+	return func(string) map[string]interface{} {
+		return fields
+	}
+}
+
+// assetList is a list of assets, or a map of lists by account with `?groupBy=account`
+func assetList(assetType string) interface{} {
+	list := reflect.SliceOf(reflect.TypeOf(assetModels[assetType]))
+	return oneOf{list, reflect.MapOf(reflect.TypeOf(""), list)}
+}
+
+// classOf is a single class of a type
+func classOf(classType string) interface{} {
+	return reflect.TypeOf(classModels[classType])
+}
+
+// classesOf is a map of classes of a type, by name
+func classesOf(classType string) interface{} {
+	return reflect.MapOf(reflect.TypeOf(""), reflect.TypeOf(classModels[classType]))
+}
+
+var secretsQuery = map[string]string{
+	"secrets": "Set to `true` to decrypt secret fields, requires the admin token (AWSM_ADMIN_TOKEN) in the X-Awsm-Admin-Token header",
+}
+
+// operations documents the routes of the API, by method and route pattern
+var operations = map[string]operation{
+	"GET /api/openapi": {
+		id:      "getOpenAPI",
+		summary: "The OpenAPI document of this API",
+		tag:     "api",
+		raw:     true,
+		ext:     ".json",
+	},
+	"GET /api/dashboard/widgets": {
+		id:      "getWidgets",
+		summary: "All dashboard widgets",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"widgets": config.Widgets{}}),
+	},
+	"GET /api/dashboard/widgets/events": {
+		id:      "getEvents",
+		summary: "The current AWS service health events",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"events": []models.Event{}}),
+	},
+	"GET /api/dashboard/widgets/feed/{feedName}": {
+		id:      "getFeed",
+		summary: "The items of the RSS feed of a widget",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"feedName": "", "feed": config.FeedItems{}}),
+	},
+	"GET /api/dashboard/widgets/options": {
+		id:      "getWidgetOptions",
+		summary: "The options for dashboard widgets",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"options": map[string][]string{}}),
+	},
+	"GET /api/dashboard/widgets/names": {
+		id:      "getWidgetNames",
+		summary: "The names of all dashboard widgets",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"widgetNames": []string{}}),
+	},
+	"GET /api/dashboard/widgets/name/{widgetName}": {
+		id:      "getWidget",
+		summary: "A single dashboard widget",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"widgetName": "", "widget": config.Widget{}}),
+	},
+	"PUT /api/dashboard/widgets/name/{widgetName}": {
+		id:      "putWidget",
+		summary: "Save a dashboard widget",
+		tag:     "widgets",
+		body:    func(string) interface{} { return config.Widget{} },
+		fields:  static(map[string]interface{}{"widgetName": "", "widget": config.Widget{}}),
+	},
+	"DELETE /api/dashboard/widgets/name/{widgetName}": {
+		id:      "deleteWidget",
+		summary: "Delete a dashboard widget",
+		tag:     "widgets",
+		fields:  static(map[string]interface{}{"widgetName": ""}),
+	},
+	"GET /api/assets/{assetType}": {
+		id:      "getAssets",
+		summary: "List the assets of a type across all selected regions and accounts. Responds with a 502 and the assets of the regions that did respond when some of them failed, or a 429 when all of the failures were AWS throttling. `throttled` counts the regions that were throttled.",
+		tag:     "assets",
+		query: map[string]string{
+			"account": "A comma separated list of accounts to only list the assets of",
+			"groupBy": "Set to `account` to group the assets by account",
+		},
+		fields: func(assetType string) map[string]interface{} {
+			return map[string]interface{}{"assetType": "", "assets": assetList(assetType), "throttled": 0}
+		},
+	},
+	"GET /api/classes/export": {
+		id:      "exportClasses",
+		summary: "Export all classes and widgets",
+		tag:     "classes",
+		query:   secretsQuery,
+		fields: static(map[string]interface{}{"classes": func() properties {
+			export := properties{"widgets": config.Widgets{}}
+			for classType := range classModels {
+				export[classType] = classesOf(classType)
+			}
+			return export
+		}()}),
+	},
+	"GET /api/classes/{classType}": {
+		id:      "getClasses",
+		summary: "All classes of a type",
+		tag:     "classes",
+		query:   secretsQuery,
+		fields: func(classType string) map[string]interface{} {
+			return map[string]interface{}{"classType": "", "classes": classesOf(classType)}
+		},
+	},
+	"GET /api/classes/{classType}/options": {
+		id:      "getClassOptions",
+		summary: "The options for the fields of a class type",
+		tag:     "classes",
+		fields:  static(map[string]interface{}{"classType": "", "classOptions": map[string][]string{}}),
+	},
+	"GET /api/classes/{classType}/names": {
+		id:      "getClassNames",
+		summary: "The names of all classes of a type",
+		tag:     "classes",
+		fields:  static(map[string]interface{}{"classType": "", "classNames": []string{}}),
+	},
+	"GET /api/classes/{classType}/name/{className}": {
+		id:      "getClass",
+		summary: "A single class",
+		tag:     "classes",
+		query:   secretsQuery,
+		fields: func(classType string) map[string]interface{} {
+			return map[string]interface{}{"classType": "", "className": "", "class": classOf(classType)}
+		},
+	},
+	"PUT /api/classes/{classType}/name/{className}": {
+		id:      "putClass",
+		summary: "Save a class. Responds with a 409, the current class and its revision if the class has been changed since the revision it was based on.",
+		tag:     "classes",
+		body:    classOf,
+		fields: func(classType string) map[string]interface{} {
+			return map[string]interface{}{"classType": "", "className": "", "class": classOf(classType)}
+		},
+	},
+	"DELETE /api/classes/{classType}/name/{className}": {
+		id:      "deleteClass",
+		summary: "Delete a class",
+		tag:     "classes",
+		fields:  static(map[string]interface{}{"classType": "", "className": ""}),
+	},
+}
+
+func getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, openAPISpec(chi.RouteContext(r.Context()).Routes))
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// openAPISpec builds the OpenAPI document of the /api routes of a router
+func openAPISpec(routes chi.Routes) map[string]interface{} {
+	s := make(schemas)
+	paths := make(map[string]interface{})
+
+	walkRoutes(routes, "", func(method, pattern string) {
+		if !strings.HasPrefix(pattern, "/api/") {
+			return
+		}
+
+		op, ok := operations[method+" "+pattern]
+		if !ok {
+			op = operation{id: strings.ToLower(method) + camel(pattern), fields: static(nil)}
+		}
+
+		// Document each asset and class type as its own path, since their schemas differ
+		kinds := []string{""}
+		param := ""
+		if strings.Contains(pattern, "{assetType}") {
+			kinds, param = sortedKeys(assetModels), "{assetType}"
+		} else if strings.Contains(pattern, "{classType}") {
+			kinds, param = sortedKeys(classModels), "{classType}"
+		}
+
+		for _, kind := range kinds {
+			p := pattern + op.ext
+			if param != "" {
+				p = strings.Replace(p, param, kind, -1)
+			}
+
+			item, _ := paths[p].(map[string]interface{})
+			if item == nil {
+				item = make(map[string]interface{})
+				paths[p] = item
+			}
+			item[strings.ToLower(method)] = s.operation(op, p, kind)
+		}
+	})
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "awsm API",
+			"description": "Every response, other than this document, is a JSON envelope with `success` and `errors` along with the fields of its endpoint. Failed responses have a 4xx or 5xx status code.",
+			"version":     Version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": s,
+		},
+	}
+}
+
+// walkRoutes calls fn with the method and full pattern of every route of a router, including its subrouters
+func walkRoutes(routes chi.Routes, prefix string, fn func(method, pattern string)) {
+	for _, route := range routes.Routes() {
+		if route.SubRoutes != nil {
+			walkRoutes(route.SubRoutes, prefix+strings.TrimSuffix(route.Pattern, "/*"), fn)
+			continue
+		}
+
+		pattern := prefix + route.Pattern
+		if pattern != "/" {
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+
+		for method := range route.Handlers {
+			fn(method, pattern)
+		}
+	}
+}
+
+// operation builds the OpenAPI operation of a route, for an asset or class type if it has one
+func (s schemas) operation(op operation, p, kind string) map[string]interface{} {
+	var parameters []interface{}
+	for _, match := range pathParam.FindAllStringSubmatch(p, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, name := range sortedKeys(op.query) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        name,
+			"in":          "query",
+			"description": op.query[name],
+			"schema":      map[string]interface{}{"type": "string"},
+		})
+	}
+	if _, ok := op.query["secrets"]; ok {
+		parameters = append(parameters, map[string]interface{}{
+			"name":   "X-Awsm-Admin-Token",
+			"in":     "header",
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+
+	var response map[string]interface{}
+	if op.raw {
+		response = map[string]interface{}{"type": "object"}
+	} else {
+		response = s.envelope(op.fields(kind))
+	}
+
+	operation := map[string]interface{}{
+		"operationId": op.id + camel(kind),
+		"summary":     op.summary,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "Success",
+				"content":     jsonContent(response),
+			},
+			"default": map[string]interface{}{
+				"description": "Failure, with the errors and any fields that are still useful",
+				"content":     jsonContent(s.envelope(nil)),
+			},
+		},
+	}
+	if op.tag != "" {
+		operation["tags"] = []string{op.tag}
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if op.body != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(s.of(op.body(kind))),
+		}
+	}
+
+	return operation
+}
+
+// envelope is the schema of a response envelope with the fields of an endpoint
+func (s schemas) envelope(fields map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{
+		"success": map[string]interface{}{"type": "boolean"},
+		"errors":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	}
+	for name, field := range fields {
+		props[name] = s.of(field)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"required":   []string{"success", "errors"},
+		"properties": props,
+	}
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// schemas builds the JSON schemas of Go types, and keeps the named structs as components
+type schemas map[string]interface{}
+
+// of returns the schema of a value's type, a reflect.Type, or a properties or oneOf
+func (s schemas) of(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case properties:
+		props := make(map[string]interface{})
+		for name, field := range v {
+			props[name] = s.of(field)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+
+	case oneOf:
+		options := make([]interface{}, len(v))
+		for i, option := range v {
+			options[i] = s.of(option)
+		}
+		return map[string]interface{}{"oneOf": options}
+
+	case reflect.Type:
+		return s.ofType(v)
+	}
+
+	return s.ofType(reflect.TypeOf(v))
+}
+
+func (s schemas) ofType(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": s.ofType(t.Elem())}
+
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.ofType(t.Elem())}
+
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := s[name]; !ok {
+			s[name] = map[string]interface{}{} // placeholder for recursive types
+			s[name] = s.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+
+	// interface{}
+	return map[string]interface{}{}
+}
+
+// structSchema is the object schema of a struct, following the encoding/json rules for field names
+func (s schemas) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	s.addFields(t, props)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (s schemas) addFields(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+
+		// Embedded structs have their fields promoted
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(ft, props)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.ofType(field.Type)
+		for _, opt := range opts[1:] {
+			if opt == "string" {
+				schema = map[string]interface{}{"type": "string"}
+			}
+		}
+		props[name] = schema
+	}
+}
+
+// camel turns a path or asset type (eg: instances-running) into an operationId suffix (eg: InstancesRunning)
+func camel(str string) string {
+	words := strings.FieldsFunc(str, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, "")
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,12 +1,12 @@
 package api
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/SlyMarbo/rss"
 	"github.com/go-chi/chi"
-	"github.com/go-chi/render"
 	"github.com/murdinc/awsm/aws"
 	"github.com/murdinc/awsm/config"
 )
@@ -15,22 +15,22 @@ func getWidgets(w http.ResponseWriter, r *http.Request) {
 	resp, err := config.LoadAllWidgets()
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), nil, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"widgets": resp, "success": true})
+	respond(w, r, map[string]interface{}{"widgets": resp})
 }
 
 func getWidgetNames(w http.ResponseWriter, r *http.Request) {
 	resp, err := config.LoadAllWidgetNames()
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), nil, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"widgetNames": resp, "success": true})
+	respond(w, r, map[string]interface{}{"widgetNames": resp})
 }
 
 func getWidgetByName(w http.ResponseWriter, r *http.Request) {
@@ -38,35 +38,36 @@ func getWidgetByName(w http.ResponseWriter, r *http.Request) {
 	resp, err := config.LoadWidget(widgetName)
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"widgetName": widgetName}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"widgetName": widgetName, "widget": resp, "success": true})
+	respond(w, r, map[string]interface{}{"widgetName": widgetName, "widget": resp})
 }
 
 func getWidgetOptions(w http.ResponseWriter, r *http.Request) {
 	resp := make(map[string][]string)
 	resp["availableWidgets"] = []string{"events", "alarms", "rss"}
-	render.JSON(w, r, map[string]interface{}{"options": resp, "success": true})
+	respond(w, r, map[string]interface{}{"options": resp})
 }
 
 func getEvents(w http.ResponseWriter, r *http.Request) {
 	events, err := aws.GetEvents()
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, http.StatusBadGateway, nil, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"events": events, "success": true})
+	respond(w, r, map[string]interface{}{"events": events})
 }
 
 func getFeed(w http.ResponseWriter, r *http.Request) {
 	feedName := chi.URLParam(r, "feedName")
+	fields := map[string]interface{}{"feedName": feedName}
 
 	feedSettings, err := config.LoadWidget(feedName)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), fields, err)
 		return
 	}
 
@@ -74,7 +75,7 @@ func getFeed(w http.ResponseWriter, r *http.Request) {
 
 	feed, err := rss.Fetch(feedSettings.RssURL)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, http.StatusBadGateway, fields, err)
 		return
 	}
 
@@ -84,11 +85,12 @@ func getFeed(w http.ResponseWriter, r *http.Request) {
 
 	items, err = config.SaveFeed(feedName, items, feedSettings.Count)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), fields, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"feed": items, "success": true})
+	fields["feed"] = items
+	respond(w, r, fields)
 }
 
 func deleteWidget(w http.ResponseWriter, r *http.Request) {
@@ -96,34 +98,36 @@ func deleteWidget(w http.ResponseWriter, r *http.Request) {
 
 	err := config.DeleteWidget(widgetName)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{err.Error()}})
+		respondError(w, r, errorStatus(err), map[string]interface{}{"widgetName": widgetName}, err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"success": true})
+	respond(w, r, map[string]interface{}{"widgetName": widgetName})
 }
 
 func putWidget(w http.ResponseWriter, r *http.Request) {
 	widgetName := chi.URLParam(r, "widgetName")
+	fields := map[string]interface{}{"widgetName": widgetName}
 
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"Error Reading Body!", err.Error()}})
+		respondError(w, r, http.StatusBadRequest, fields, requestError("Error Reading Body!"), err)
 		return
 	}
 
 	// check for empty body?
 	if len(data) == 0 {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"No widget object was passed!"}})
+		respondError(w, r, http.StatusBadRequest, fields, requestError("No widget object was passed!"))
 		return
 	}
 
 	widget, err := config.SaveWidget(widgetName, data)
 
 	if err != nil {
-		render.JSON(w, r, map[string]interface{}{"success": false, "errors": []string{"Error saving Class!", err.Error()}})
+		respondError(w, r, errorStatus(err), fields, errors.New("Error saving Widget!"), err)
 		return
 	}
 
-	render.JSON(w, r, map[string]interface{}{"widget": widget, "success": true})
+	fields["widget"] = widget
+	respond(w, r, fields)
 }
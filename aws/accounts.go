@@ -0,0 +1,191 @@
+package aws
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
+	"github.com/murdinc/terminal"
+)
+
+// Account represents an AWS account that assets are collected from, named after its profile in ~/.aws/credentials
+type Account struct {
+	Name  string
+	creds *credentials.Credentials // nil for the current credentials
+}
+
+// CurrentAccount returns the account of the current credentials
+func CurrentAccount() Account {
+	return Account{}
+}
+
+// Session returns a new session for a region of the account
+func (a Account) Session(region string) *session.Session {
+	return sessions.New(&aws.Config{Region: aws.String(region), Credentials: a.creds})
+}
+
+// newSession returns a new session for a region of the current account
+func newSession(region string) *session.Session {
+	return CurrentAccount().Session(region)
+}
+
+// label returns a description of the account for messages, empty for the current account
+func (a Account) label() string {
+	if a.Name == "" {
+		return ""
+	}
+	return " of account [" + a.Name + "]"
+}
+
+// GetAccounts returns the accounts of the current environment, or just the current account if there are none
+func GetAccounts() ([]Account, error) {
+	names := settings.Current().Accounts
+	if len(names) == 0 {
+		return []Account{CurrentAccount()}, nil
+	}
+
+	cfg, err := readCreds()
+	if err != nil {
+		return nil, errors.New("Unable to read the AWS Credentials config file: " + err.Error())
+	}
+
+	accounts := make([]Account, len(names))
+
+	// Resolved one at a time, as assuming a role may prompt for an MFA code
+	for i, name := range names {
+		profile, ok := cfg.getProfile(name)
+		if !ok {
+			return nil, errors.New("Unable to find the [" + name + "] profile in the AWS Credentials config file!")
+		}
+
+		accounts[i].Name = name
+
+		if profile.RoleArn == "" {
+			accounts[i].creds = credentials.NewSharedCredentials("", name)
+			continue
+		}
+
+		creds, err := cfg.getRoleCreds(profile)
+		if err != nil {
+			return nil, err
+		}
+		accounts[i].creds = credentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
+	}
+
+	return accounts, nil
+}
+
+// gatheringError returns the title of an error gathering assets, keeping throttling apart from other failures
+func gatheringError(err error) string {
+	if sessions.IsThrottle(err) {
+		return "Throttled while gathering"
+	}
+	return "Error gathering"
+}
+
+// GatherError is an error gathering an asset list in a region of an account
+type GatherError struct {
+	Asset   string
+	Region  string // empty for assets that are not regional
+	Account Account
+	Err     error
+}
+
+// Title describes what could not be gathered, keeping throttling apart from other failures
+func (e GatherError) Title() string {
+	title := gatheringError(e.Err) + " " + e.Asset + " list"
+	if e.Region != "" {
+		title += " for region [" + e.Region + "]"
+	}
+	return title + e.Account.label()
+}
+
+func (e GatherError) Error() string {
+	return e.Title() + ": " + e.Err.Error()
+}
+
+// ShowErrors shows the errors of gathering asset lists in the terminal
+func ShowErrors(errs []error) {
+	for _, err := range errs {
+		if gatherErr, ok := err.(GatherError); ok {
+			terminal.ShowErrorMessage(gatherErr.Title(), gatherErr.Err.Error())
+			continue
+		}
+		terminal.ShowErrorMessage("Error", err.Error())
+	}
+}
+
+// collectAccountRegions calls collect in parallel for every region of every account, returning any errors as GatherErrors
+func collectAccountRegions(assetName string, collect func(account Account, region string) error) []error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	accounts, err := GetAccounts()
+	if err != nil {
+		return []error{err}
+	}
+
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return []error{err}
+	}
+
+	for _, account := range accounts {
+		for _, region := range regions {
+			wg.Add(1)
+
+			go func(account Account, region string) {
+				defer wg.Done()
+				start := time.Now()
+				err := collect(account, region)
+				logger.Debug("Collected "+assetName+" list", "account", account.Name, "region", region, "duration", time.Since(start), "error", err)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, GatherError{Asset: assetName, Region: region, Account: account, Err: err})
+					mu.Unlock()
+				}
+			}(account, *region.RegionName)
+		}
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// collectAccounts calls collect in parallel for every account, for assets that are not regional, returning any errors as GatherErrors
+func collectAccounts(assetName string, collect func(account Account) error) []error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	accounts, err := GetAccounts()
+	if err != nil {
+		return []error{err}
+	}
+
+	for _, account := range accounts {
+		wg.Add(1)
+
+		go func(account Account) {
+			defer wg.Done()
+			start := time.Now()
+			err := collect(account)
+			logger.Debug("Collected "+assetName+" list", "account", account.Name, "duration", time.Since(start), "error", err)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, GatherError{Asset: assetName, Account: account, Err: err})
+				mu.Unlock()
+			}
+		}(account)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -2,18 +2,16 @@ package aws
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"reflect"
 	"regexp"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 
 	"github.com/olekukonko/tablewriter"
@@ -27,25 +25,11 @@ type Address models.Address
 
 // GetAddresses returns a slice of Elastic IP Addresses based on the given search term and optional available flag
 func GetAddresses(search string, available bool) (*Addresses, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	ipList := new(Addresses)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionAddresses(*region.RegionName, ipList, search, available)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering address list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("address", func(account Account, region string) error {
+		return getAccountRegionAddresses(account, region, ipList, search, available)
+	})
 
 	return ipList, errs
 }
@@ -75,8 +59,13 @@ func (a *Address) Marshal(address *ec2.Address, region string, instList *Instanc
 
 // GetRegionAddresses returns a list of Elastic IP Addresses for a given region into the provided Addresses slice
 func GetRegionAddresses(region string, adrList *Addresses, search string, available bool) error {
+	return getAccountRegionAddresses(CurrentAccount(), region, adrList, search, available)
+}
+
+// getAccountRegionAddresses returns a list of Elastic IP Addresses for a given region of an account into the provided Addresses slice
+func getAccountRegionAddresses(account Account, region string, adrList *Addresses, search string, available bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeAddresses(&ec2.DescribeAddressesInput{})
@@ -86,11 +75,12 @@ func GetRegionAddresses(region string, adrList *Addresses, search string, availa
 	}
 
 	instList := new(Instances)
-	GetRegionInstances(region, instList, "", false)
+	getAccountRegionInstances(account, region, instList, "", false)
 
 	adr := make(Addresses, len(result.Addresses))
 	for i, address := range result.Addresses {
 		adr[i].Marshal(address, region, instList)
+		adr[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -141,7 +131,7 @@ func CreateAddress(region, domain string, dryRun bool) (string, error) {
 		return "", errors.New("Domain should be either [vpc] or [classic].")
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Create the address
@@ -179,7 +169,9 @@ func DeleteAddresses(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionAddresses(region, addrList, search, false)
 	} else {
-		addrList, _ = GetAddresses(search, false)
+		var errs []error
+		addrList, errs = GetAddresses(search, false)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -194,7 +186,7 @@ func DeleteAddresses(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Addresses?") {
+	if !prompt.Confirm("Are you sure you want to delete these Addresses?") {
 		return errors.New("Aborting!")
 	}
 
@@ -216,7 +208,7 @@ func DeleteAddresses(search, region string, dryRun bool) (err error) {
 func deleteAddresses(addrList *Addresses, dryRun bool) (err error) {
 	for _, addr := range *addrList {
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(addr.Region)}))
+		sess := newSession(addr.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.ReleaseAddressInput{
@@ -7,13 +7,10 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
@@ -29,32 +26,23 @@ type Alarm models.Alarm
 
 // GetAlarms returns a slice of CloudWatch Alarms based on the given search term
 func GetAlarms(search string) (*Alarms, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	alList := new(Alarms)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionAlarms(*region.RegionName, alList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering alarm list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("alarm", func(account Account, region string) error {
+		return getAccountRegionAlarms(account, region, alList, search)
+	})
 
 	return alList, errs
 }
 
 // GetRegionAlarms returns a list of CloudWatch Alarms for the given region into the provided Alarms slice
 func GetRegionAlarms(region string, alList *Alarms, search string) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	return getAccountRegionAlarms(CurrentAccount(), region, alList, search)
+}
+
+// getAccountRegionAlarms returns a list of CloudWatch Alarms for the given region of an account into the provided Alarms slice
+func getAccountRegionAlarms(account Account, region string, alList *Alarms, search string) error {
+	sess := account.Session(region)
 	svc := cloudwatch.New(sess)
 
 	result, err := svc.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{})
@@ -65,6 +53,7 @@ func GetRegionAlarms(region string, alList *Alarms, search string) error {
 	al := make(Alarms, len(result.MetricAlarms))
 	for i, alarm := range result.MetricAlarms {
 		al[i].Marshal(alarm, region)
+		al[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -167,7 +156,7 @@ func CreateAlarm(class string, region string, dryRun bool) error {
 // private function with no terminal prompts
 func createAlarm(name string, cfg config.AlarmClass, region string, dryRun bool) (err error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := cloudwatch.New(sess)
 
 	// Create the alarm
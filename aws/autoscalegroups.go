@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,17 +9,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/cli"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
@@ -38,33 +37,24 @@ type ScalingActivity models.ScalingActivity
 
 // GetAutoScaleGroups returns a slice of AutoScale Groups based on the given search term
 func GetAutoScaleGroups(search string) (*AutoScaleGroups, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	asgList := new(AutoScaleGroups)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionAutoScaleGroups(*region.RegionName, asgList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering autoscale group list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("autoscale group", func(account Account, region string) error {
+		return getAccountRegionAutoScaleGroups(account, region, asgList, search)
+	})
 
 	return asgList, errs
 }
 
 // GetRegionAutoScaleGroups returns a list of AutoScale Groups for a given region into the provided AutoScaleGroups slice
 func GetRegionAutoScaleGroups(region string, asgList *AutoScaleGroups, search string) error {
+	return getAccountRegionAutoScaleGroups(CurrentAccount(), region, asgList, search)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionAutoScaleGroups returns a list of AutoScale Groups for a given region of an account into the provided AutoScaleGroups slice
+func getAccountRegionAutoScaleGroups(account Account, region string, asgList *AutoScaleGroups, search string) error {
+
+	sess := account.Session(region)
 	svc := autoscaling.New(sess)
 
 	result, err := svc.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{})
@@ -73,11 +63,12 @@ func GetRegionAutoScaleGroups(region string, asgList *AutoScaleGroups, search st
 	}
 
 	subList := new(Subnets)
-	GetRegionSubnets(region, subList, "")
+	getAccountRegionSubnets(account, region, subList, "")
 
 	asg := make(AutoScaleGroups, len(result.AutoScalingGroups))
 	for i, autoscalegroup := range result.AutoScalingGroups {
 		asg[i].Marshal(autoscalegroup, region, subList)
+		asg[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -107,6 +98,7 @@ func GetScalingActivities(search string, latest bool) (ScalingActivities, error)
 
 	asgList, errs := GetAutoScaleGroups(search)
 	if errs != nil {
+		ShowErrors(errs)
 		return nil, errors.New("Error while retrieving the list of AutoScale Groups!")
 	}
 
@@ -134,7 +126,7 @@ func getScalingActivities(asgList *AutoScaleGroups, latest bool) (activities Sca
 
 		terminal.Delta("Gathering Scaling Activities for AutoScale Group [" + asg.Name + "] in [" + asg.Region + "]")
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := autoscaling.New(sess)
 
 		// Gather the activities
@@ -212,6 +204,24 @@ func (a *AutoScaleGroups) LockedLaunchConfigurations() map[string]bool {
 	return names
 }
 
+// getInstanceAutoScaleGroupName returns the name of the AutoScale Group an Instance is in, or an empty string if it is in none
+func getInstanceAutoScaleGroupName(ctx context.Context, region, instanceID string) (string, error) {
+	svc := autoscaling.New(newSession(region))
+
+	resp, err := svc.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.AutoScalingInstances) > 0 {
+		return aws.StringValue(resp.AutoScalingInstances[0].AutoScalingGroupName), nil
+	}
+
+	return "", nil
+}
+
 // CreateAutoScaleGroups creates a new AutoScale Group of the given class
 func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 
@@ -234,6 +244,12 @@ func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 	}
 	terminal.Information("Found Launch Configuration class configuration for [" + cfg.LaunchConfigurationClass + "]")
 
+	// The placement group of the instance class goes on the asg, launch configurations don't have one
+	instanceCfg, err := config.LoadInstanceClass(launchConfigurationCfg.InstanceClass)
+	if err != nil {
+		return err
+	}
+
 	// Get the AZs
 	azs, errs := regions.GetAZs()
 	if errs != nil {
@@ -244,6 +260,11 @@ func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 
 	for region, regionAZs := range azs.GetRegionMap(cfg.AvailabilityZones) {
 
+		if !regions.Selected(region) {
+			terminal.Notice("Region [" + region + "] is not selected, skipping!")
+			continue
+		}
+
 		*asgList = append(*asgList, AutoScaleGroup{
 			Name:   class,
 			Region: region,
@@ -256,7 +277,7 @@ func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 		}
 		terminal.Information(fmt.Sprintf("Found latest Launch Configuration [%s] version [%d] in [%s]", cfg.LaunchConfigurationClass, launchConfigurationCfg.Version, region))
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+		sess := newSession(region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.CreateAutoScalingGroupInput{
@@ -272,25 +293,10 @@ func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 			// TODO ?
 			// InstanceId:                       aws.String("XmlStringMaxLen19"),
 			// NewInstancesProtectedFromScaleIn: aws.Bool(true),
-			// PlacementGroup:                   aws.String("XmlStringMaxLen255"),
-			Tags: []*autoscaling.Tag{
-				{
-					// Name
-					Key:               aws.String("Name"),
-					PropagateAtLaunch: aws.Bool(true),
-					ResourceId:        aws.String(class),
-					ResourceType:      aws.String("auto-scaling-group"),
-					Value:             aws.String(lcName),
-				},
-				{
-					// Class
-					Key:               aws.String("Class"),
-					PropagateAtLaunch: aws.Bool(true),
-					ResourceId:        aws.String(class),
-					ResourceType:      aws.String("auto-scaling-group"),
-					Value:             aws.String(cfg.LaunchConfigurationClass),
-				},
-			},
+			Tags: autoscalingTags(class, mergeTags(instanceCfg.Tags, launchConfigurationCfg.Tags, cfg.Tags, map[string]string{
+				"Name":  lcName,
+				"Class": cfg.LaunchConfigurationClass,
+			})),
 		}
 
 		subList := new(Subnets)
@@ -323,6 +329,12 @@ func CreateAutoScaleGroups(class string, dryRun bool) (err error) {
 		// Set the VPCZoneIdentifier (SubnetIds seperated by comma)
 		params.VPCZoneIdentifier = aws.String(strings.Join(vpcZones, ", "))
 
+		// Set the Placement Group
+		if instanceCfg.PlacementGroup != "" {
+			terminal.Information("Using Placement Group [" + instanceCfg.PlacementGroup + "]")
+			params.PlacementGroup = aws.String(instanceCfg.PlacementGroup)
+		}
+
 		// Set the Load Balancers
 		for _, elb := range cfg.LoadBalancerNames {
 			params.LoadBalancerNames = append(params.LoadBalancerNames, aws.String(elb))
@@ -393,6 +405,7 @@ func CreateAutoScaleAlarms(class string, asgSearch string, dryRun bool) error {
 
 	asgList, errs := GetAutoScaleGroups(asgSearch)
 	if errs != nil {
+		ShowErrors(errs)
 		return errors.New("Error while retrieving the list of AutoScale Groups!")
 	}
 
@@ -404,7 +417,7 @@ func CreateAutoScaleAlarms(class string, asgSearch string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to create this alarm in these AutoScaling Groups?") {
+	if !prompt.Confirm("Are you sure you want to create this alarm in these AutoScaling Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -425,7 +438,7 @@ func createAutoScaleAlarms(name string, cfg config.AlarmClass, asgList *AutoScal
 
 		terminal.Delta("Adding Alarm [" + name + "] to AutoScale Group [" + asg.Name + "] in [" + asg.Region + "]")
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := cloudwatch.New(sess)
 
 		// Create the alarm
@@ -512,7 +525,8 @@ func UpdateAutoScaleGroups(name, version string, double, forceYes, dryRun bool)
 		terminal.Information("--double flag is set, doubling desired and max counts!")
 	}
 
-	asgList, _ := GetAutoScaleGroups(name)
+	asgList, errs := GetAutoScaleGroups(name)
+	ShowErrors(errs)
 
 	if len(*asgList) > 0 {
 		// Print the table
@@ -522,7 +536,7 @@ func UpdateAutoScaleGroups(name, version string, double, forceYes, dryRun bool)
 	}
 
 	// Confirm
-	if !forceYes && !terminal.PromptBool("Are you sure you want to update these AutoScaling Groups?") {
+	if !forceYes && !prompt.Confirm("Are you sure you want to update these AutoScaling Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -572,6 +586,12 @@ func updateAutoScaleGroups(asgList *AutoScaleGroups, version string, double, dry
 
 		terminal.Information("Found Launch Configuration class configuration for [" + cfg.LaunchConfigurationClass + "]")
 
+		// The custom tags of the instance class are propagated along with the asg ones
+		instanceCfg, err := config.LoadInstanceClass(launchConfigurationCfg.InstanceClass)
+		if err != nil {
+			return err
+		}
+
 		// Get the AZs
 		azs, errs := regions.GetAZs()
 		if errs != nil {
@@ -580,6 +600,11 @@ func updateAutoScaleGroups(asgList *AutoScaleGroups, version string, double, dry
 
 		for region, regionAZs := range azs.GetRegionMap(cfg.AvailabilityZones) {
 
+			if !regions.Selected(region) {
+				terminal.Notice("Region [" + region + "] is not selected, skipping!")
+				continue
+			}
+
 			// TODO check if exists yet ?
 
 			// Verify that the latest Launch Configuration is available in this region
@@ -589,7 +614,7 @@ func updateAutoScaleGroups(asgList *AutoScaleGroups, version string, double, dry
 			}
 			terminal.Information(fmt.Sprintf("Found Launch Configuration [%s] version [%d] in [%s]", cfg.LaunchConfigurationClass, launchConfigurationCfg.Version, asg.Region))
 
-			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+			sess := newSession(region)
 			svc := autoscaling.New(sess)
 
 			params := &autoscaling.UpdateAutoScalingGroupInput{
@@ -652,24 +677,10 @@ func updateAutoScaleGroups(asgList *AutoScaleGroups, version string, double, dry
 
 				// Update Tags
 				tagParams := &autoscaling.CreateOrUpdateTagsInput{
-					Tags: []*autoscaling.Tag{
-						{
-							// Name
-							Key:               aws.String("Name"),
-							PropagateAtLaunch: aws.Bool(true),
-							ResourceId:        aws.String(asg.Name),
-							ResourceType:      aws.String("auto-scaling-group"),
-							Value:             aws.String(lcName),
-						},
-						{
-							// Class
-							Key:               aws.String("Class"),
-							PropagateAtLaunch: aws.Bool(true),
-							ResourceId:        aws.String(asg.Name),
-							ResourceType:      aws.String("auto-scaling-group"),
-							Value:             aws.String(cfg.LaunchConfigurationClass),
-						},
-					},
+					Tags: autoscalingTags(asg.Name, mergeTags(instanceCfg.Tags, launchConfigurationCfg.Tags, cfg.Tags, map[string]string{
+						"Name":  lcName,
+						"Class": cfg.LaunchConfigurationClass,
+					})),
 				}
 
 				_, err = svc.CreateOrUpdateTags(tagParams)
@@ -732,7 +743,9 @@ func DeleteAutoScaleGroups(name, region string, force, dryRun bool) (err error)
 	if region != "" {
 		err = GetRegionAutoScaleGroups(region, asgList, name)
 	} else {
-		asgList, _ = GetAutoScaleGroups(name)
+		var errs []error
+		asgList, errs = GetAutoScaleGroups(name)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -747,7 +760,7 @@ func DeleteAutoScaleGroups(name, region string, force, dryRun bool) (err error)
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these AutoScaling Groups?") {
+	if !prompt.Confirm("Are you sure you want to delete these AutoScaling Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -766,7 +779,7 @@ func DeleteAutoScaleGroups(name, region string, force, dryRun bool) (err error)
 // Private function without the confirmation terminal prompts
 func deleteAutoScaleGroups(asgList *AutoScaleGroups, force, dryRun bool) (err error) {
 	for _, asg := range *asgList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.DeleteAutoScalingGroupInput{
@@ -810,7 +823,9 @@ func SuspendProcesses(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionAutoScaleGroups(region, asgList, search)
 	} else {
-		asgList, _ = GetAutoScaleGroups(search)
+		var errs []error
+		asgList, errs = GetAutoScaleGroups(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -825,7 +840,7 @@ func SuspendProcesses(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to suspend these Autoscale Groups?") {
+	if !prompt.Confirm("Are you sure you want to suspend these Autoscale Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -847,7 +862,7 @@ func SuspendProcesses(search, region string, dryRun bool) (err error) {
 
 func suspendProcesses(asgList *AutoScaleGroups) error {
 	for _, asg := range *asgList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.ScalingProcessQuery{
@@ -879,7 +894,9 @@ func ResumeProcesses(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionAutoScaleGroups(region, asgList, search)
 	} else {
-		asgList, _ = GetAutoScaleGroups(search)
+		var errs []error
+		asgList, errs = GetAutoScaleGroups(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -894,7 +911,7 @@ func ResumeProcesses(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to resume these Autoscale Groups?") {
+	if !prompt.Confirm("Are you sure you want to resume these Autoscale Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -916,7 +933,7 @@ func ResumeProcesses(search, region string, dryRun bool) (err error) {
 
 func resumeProcesses(asgList *AutoScaleGroups) error {
 	for _, asg := range *asgList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.ScalingProcessQuery{
@@ -0,0 +1,360 @@
+package aws
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/terminal"
+)
+
+// bootstrapTag is set on an Instance by awsm bootstrap, to running while it works and then complete or failed
+const bootstrapTag = "awsm:bootstrap"
+
+// BootstrapOptions are the options for BootstrapWithContext
+type BootstrapOptions struct {
+	DryRun   bool
+	Observer Observer
+}
+
+// BootstrapResult is the result of BootstrapWithContext
+type BootstrapResult struct {
+	InstanceID string
+	Region     string
+	Class      string
+	Sequence   string
+	VolumeIDs  []string // of the Volumes of the Instance class, in order
+	DNSName    string
+	Warnings   []string
+}
+
+// Bootstrap assembles the Instance it is run on from its class, for use in UserData
+func Bootstrap(dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	_, err := BootstrapWithContext(context.Background(), BootstrapOptions{
+		DryRun:   dryRun,
+		Observer: TerminalObserver,
+	})
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// BootstrapWithContext finds the Instance it is run on and its class from the ec2 meta-data, then attaches its EBS
+// Volumes (an available Volume named after the Instance and Volume class, or a new one), runs the Attach Command of each
+// Volume class on this machine, and registers its DNS Name, without any terminal output or prompts. The progress is kept
+// in the awsm:bootstrap tag of the Instance, which ends up as complete or failed. It can safely be run again.
+func BootstrapWithContext(ctx context.Context, opts BootstrapOptions) (result *BootstrapResult, err error) {
+	p := newProgress(opts.Observer)
+
+	// Instance Identity
+	metadata := ec2metadata.New(sessions.New(aws.NewConfig()))
+	if !metadata.AvailableWithContext(ctx) {
+		return nil, errors.New("Unable to reach the ec2 meta-data, awsm bootstrap must be run on an EC2 Instance!")
+	}
+
+	identity, err := metadata.GetInstanceIdentityDocumentWithContext(ctx)
+	if err != nil {
+		return nil, errors.New("Unable to get the Instance identity from the ec2 meta-data: " + err.Error())
+	}
+
+	result = &BootstrapResult{
+		InstanceID: identity.InstanceID,
+		Region:     identity.Region,
+	}
+
+	svc := ec2.New(newSession(identity.Region))
+
+	resp, err := svc.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(identity.InstanceID)},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return nil, errors.New(awsErr.Message())
+		}
+		return nil, err
+	}
+	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
+		return nil, errors.New("Instance [" + identity.InstanceID + "] not found in [" + identity.Region + "]!")
+	}
+
+	instance := resp.Reservations[0].Instances[0]
+	name := GetTagValue("Name", instance.Tags)
+	result.Class = GetTagValue("Class", instance.Tags)
+	result.Sequence = GetTagValue("Sequence", instance.Tags)
+
+	if result.Class == "" {
+		return nil, errors.New("Instance [" + identity.InstanceID + "] does not have a Class tag, Aborting!")
+	}
+
+	p.info("Found Instance [" + identity.InstanceID + "] named [" + name + "] with class [" + result.Class + "] in [" + identity.AvailabilityZone + "]!")
+
+	instanceCfg, err := config.LoadInstanceClass(result.Class)
+	if err != nil {
+		return nil, err
+	}
+
+	p.info("Found Instance class configuration for [" + result.Class + "]!")
+
+	// Keep the progress in a tag, so that launches can tell when the Instance has assembled itself
+	setStatus := func(status string) {
+		if opts.DryRun {
+			return
+		}
+		err := setEc2Tags(instance.InstanceId, map[string]string{bootstrapTag: status}, identity.Region)
+		if err != nil {
+			p.warn("Unable to set the " + bootstrapTag + " tag of Instance [" + identity.InstanceID + "] to [" + status + "]: " + err.Error())
+		}
+	}
+
+	setStatus("running")
+	defer func() {
+		if err != nil {
+			setStatus("failed")
+		} else {
+			setStatus("complete")
+		}
+		if result != nil {
+			result.Warnings = p.warnings
+		}
+	}()
+
+	// EBS Volumes
+	for _, ebsClass := range instanceCfg.EBSVolumes {
+		volCfg, err := config.LoadVolumeClass(ebsClass)
+		if err != nil {
+			return result, err
+		}
+
+		p.info("Found Volume Class Configuration for [" + ebsClass + "]!")
+
+		volumeID, err := bootstrapVolume(ctx, p, svc, instance, identity.AvailabilityZone, name, ebsClass, volCfg, instanceCfg.Tags, opts.DryRun)
+		if err != nil {
+			return result, errors.New("Unable to attach a Volume of class [" + ebsClass + "]: " + err.Error())
+		}
+		if volumeID != "" {
+			result.VolumeIDs = append(result.VolumeIDs, volumeID)
+		}
+
+		if volCfg.AttachCommand == "" {
+			continue
+		}
+
+		if volCfg.MountPoint != "" && mounted(volCfg.MountPoint) {
+			p.info("Mount Point [" + volCfg.MountPoint + "] of Volume class [" + ebsClass + "] is already mounted, skipping its Attach Command.")
+			continue
+		}
+
+		if opts.DryRun {
+			p.notice("Would run the Attach Command of Volume class [" + ebsClass + "]: " + volCfg.AttachCommand)
+			continue
+		}
+
+		p.notice("Running the Attach Command of Volume class [" + ebsClass + "]...")
+
+		output, err := exec.CommandContext(ctx, "/bin/sh", "-c", volCfg.AttachCommand).CombinedOutput()
+		if len(output) > 0 {
+			p.info(strings.TrimSpace(string(output)))
+		}
+		if err != nil {
+			return result, errors.New("The Attach Command of Volume class [" + ebsClass + "] failed: " + err.Error())
+		}
+
+		p.change("Ran the Attach Command of Volume class [" + ebsClass + "]!")
+	}
+
+	// DNS
+	if instanceCfg.DNSName != "" {
+		dnsName, err := evalClassTemplate(instanceCfg.DNSName, result.Class, result.Sequence, identity.Region)
+		if err != nil {
+			return result, err
+		}
+
+		// The IP address is looked up in the ec2 meta-data
+		record, err := CreateResourceRecordWithContext(ctx, CreateResourceRecordOptions{
+			Name:     dnsName,
+			Upsert:   true,
+			Private:  instanceCfg.DNSPrivate,
+			DryRun:   opts.DryRun,
+			Observer: ObserverFunc(p.forward),
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.DNSName = record.Change.Name
+	}
+
+	return result, nil
+}
+
+// bootstrapVolume makes sure that a Volume of a class is attached to an Instance at the Device Name of the class, attaching
+// the available Volume named after the Instance and class or creating a new one, and returns its id
+func bootstrapVolume(ctx context.Context, p *progress, svc *ec2.EC2, instance *ec2.Instance, az, name, ebsClass string, volCfg config.VolumeClass, instanceTags map[string]string, dryRun bool) (string, error) {
+	region := aws.StringValue(svc.Config.Region)
+	volumeName := name + "-" + ebsClass
+
+	// Already attached, by the launch or an earlier run
+	for _, mapping := range instance.BlockDeviceMappings {
+		if aws.StringValue(mapping.DeviceName) == volCfg.DeviceName && mapping.Ebs != nil {
+			volumeID := aws.StringValue(mapping.Ebs.VolumeId)
+			p.info("Volume [" + volumeID + "] is already attached at [" + volCfg.DeviceName + "]!")
+			return volumeID, nil
+		}
+	}
+
+	resp, err := svc.DescribeVolumesWithContext(ctx, &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:Name"),
+				Values: []*string{aws.String(volumeName)},
+			},
+			{
+				Name:   aws.String("tag:Class"),
+				Values: []*string{aws.String(ebsClass)},
+			},
+			{
+				Name:   aws.String("availability-zone"),
+				Values: []*string{aws.String(az)},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String("available")},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var volumeID string
+	if len(resp.Volumes) > 0 {
+		volumeID = aws.StringValue(resp.Volumes[0].VolumeId)
+		p.info("Found available Volume [" + volumeID + "] named [" + volumeName + "]!")
+
+	} else {
+		params := &ec2.CreateVolumeInput{
+			AvailabilityZone: aws.String(az),
+			Size:             aws.Int64(int64(volCfg.VolumeSize)),
+			VolumeType:       aws.String(volCfg.VolumeType),
+			Encrypted:        aws.Bool(volCfg.Encrypted),
+		}
+
+		if volCfg.VolumeType == "io1" {
+			params.Iops = aws.Int64(int64(volCfg.Iops))
+		}
+
+		if volCfg.Snapshot != "" {
+			latestSnapshot, err := GetLatestSnapshotByTag(region, "Class", volCfg.Snapshot)
+			if err != nil {
+				return "", err
+			}
+
+			p.info("Found Snapshot [" + latestSnapshot.SnapshotID + "] with class [" + latestSnapshot.Class + "] created [" + humanize.Time(latestSnapshot.StartTime) + "]!")
+			params.SnapshotId = aws.String(latestSnapshot.SnapshotID)
+			params.Encrypted = nil // The Volume is encrypted if the Snapshot is
+		}
+
+		if dryRun {
+			p.notice("Would create and attach a Volume named [" + volumeName + "] at [" + volCfg.DeviceName + "]")
+			return "", nil
+		}
+
+		createResp, err := svc.CreateVolumeWithContext(ctx, params)
+		if err != nil {
+			return "", err
+		}
+		volumeID = aws.StringValue(createResp.VolumeId)
+
+		p.change("Created Volume [" + volumeID + "] named [" + volumeName + "] in [" + az + "]!")
+
+		err = svc.WaitUntilVolumeAvailableWithContext(ctx, &ec2.DescribeVolumesInput{VolumeIds: []*string{createResp.VolumeId}})
+		if err != nil {
+			return "", err
+		}
+
+		err = SetEc2ClassTags(createResp.VolumeId, volumeName, ebsClass, mergeTags(instanceTags, volCfg.Tags), region)
+		if err != nil {
+			p.warn("Unable to tag Volume [" + volumeID + "]: " + err.Error())
+		}
+	}
+
+	if dryRun {
+		p.notice("Would attach Volume [" + volumeID + "] at [" + volCfg.DeviceName + "]")
+		return volumeID, nil
+	}
+
+	_, err = svc.AttachVolumeWithContext(ctx, &ec2.AttachVolumeInput{
+		Device:     aws.String(volCfg.DeviceName),
+		InstanceId: instance.InstanceId,
+		VolumeId:   aws.String(volumeID),
+	})
+	if err != nil {
+		return volumeID, err
+	}
+
+	err = svc.WaitUntilVolumeInUseWithContext(ctx, &ec2.DescribeVolumesInput{VolumeIds: []*string{aws.String(volumeID)}})
+	if err != nil {
+		return volumeID, err
+	}
+
+	p.change("Attached Volume [" + volumeID + "] at [" + volCfg.DeviceName + "]!")
+
+	if volCfg.DeleteOnTermination {
+		_, err = svc.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+			InstanceId: instance.InstanceId,
+			BlockDeviceMappings: []*ec2.InstanceBlockDeviceMappingSpecification{
+				{
+					DeviceName: aws.String(volCfg.DeviceName),
+					Ebs: &ec2.EbsInstanceBlockDeviceSpecification{
+						DeleteOnTermination: aws.Bool(true),
+						VolumeId:            aws.String(volumeID),
+					},
+				},
+			},
+		})
+		if err != nil {
+			p.warn("Unable to set Volume [" + volumeID + "] to be deleted on termination: " + err.Error())
+		}
+	}
+
+	return volumeID, nil
+}
+
+// mounted returns true if something is mounted at a mount point on this machine
+func mounted(mountPoint string) bool {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[1] == strings.TrimSuffix(mountPoint, "/") {
+			return true
+		}
+	}
+
+	return false
+}
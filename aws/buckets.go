@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/murdinc/awsm/models"
 	"github.com/murdinc/terminal"
@@ -25,20 +24,33 @@ type Bucket models.Bucket
 func GetBuckets(search string) (*Buckets, error) {
 
 	bucketList := new(Buckets)
-	regions := GetRegionListWithoutIgnored()
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return bucketList, err
+	}
 
 	rand.Seed(time.Now().UnixNano())
 	region := regions[rand.Intn(len(regions))] // pick a random region
 
-	err := GetRegionBuckets(*region.RegionName, bucketList, search)
+	errs := collectAccounts("bucket", func(account Account) error {
+		return getAccountRegionBuckets(account, *region.RegionName, bucketList, search)
+	})
+	if len(errs) > 0 {
+		return bucketList, errs[0]
+	}
 
-	return bucketList, err
+	return bucketList, nil
 }
 
 // GetRegionBuckets returns a list of Buckets for a given region into the provided Buckets slice
 func GetRegionBuckets(region string, bucketList *Buckets, search string) error {
+	return getAccountRegionBuckets(CurrentAccount(), region, bucketList, search)
+}
+
+// getAccountRegionBuckets returns a list of Buckets for a given region of an account into the provided Buckets slice
+func getAccountRegionBuckets(account Account, region string, bucketList *Buckets, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := s3.New(sess)
 
 	result, err := svc.ListBuckets(&s3.ListBucketsInput{})
@@ -49,6 +61,7 @@ func GetRegionBuckets(region string, bucketList *Buckets, search string) error {
 	bucket := make(Buckets, len(result.Buckets))
 	for i, b := range result.Buckets {
 		bucket[i].Marshal(b)
+		bucket[i].Account = account.Name
 	}
 
 	if search != "" {
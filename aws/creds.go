@@ -5,11 +5,14 @@ import (
 	"os"
 	"os/user"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
 	"github.com/murdinc/terminal"
 	"gopkg.in/ini.v1"
 )
@@ -18,12 +21,17 @@ type awsmCreds struct {
 	Profiles []Profile
 }
 
-// Profile represents a single AWS profile (an access key id and secret access key)
+// Profile represents a single AWS profile (an access key id and secret access key, or a role to assume)
 type Profile struct {
 	Name            string   `ini:"-"` // considered Sections in config file
-	AccessKeyID     string   `ini:"aws_access_key_id"`
-	SecretAccessKey string   `ini:"aws_secret_access_key"`
-	IgnoreRegions   []string `ini:"ignore_regions"`
+	AccessKeyID     string   `ini:"aws_access_key_id,omitempty"`
+	SecretAccessKey string   `ini:"aws_secret_access_key,omitempty"`
+	SessionToken    string   `ini:"aws_session_token,omitempty"`
+	RoleArn         string   `ini:"role_arn,omitempty"`
+	SourceProfile   string   `ini:"source_profile,omitempty"`
+	ExternalID      string   `ini:"external_id,omitempty"`
+	MFASerial       string   `ini:"mfa_serial,omitempty"`
+	IgnoreRegions   []string `ini:"ignore_regions,omitempty"`
 }
 
 // CheckCreds Runs before everything, verifying we have proper authentication or asking us to set some up
@@ -31,14 +39,23 @@ func CheckCreds() (bool, string) {
 	id, err := testCreds()
 
 	if err != nil || len(id) == 0 {
+
+		// Only the standard SDK credential chain is used in non-interactive mode
+		if settings.Current().NonInteractive {
+			if err != nil {
+				terminal.ErrorLine("Unable to authenticate with the AWS credential chain: " + err.Error())
+			}
+			return false, ""
+		}
+
 		create := false
 
 		// Try to read the config file
 		cfg, err := readCreds()
 		if err != nil || len(cfg.Profiles) == 0 {
-			create = terminal.BoxPromptBool("The AWS Credentials config file is empty or missing!", "Do you want to add one now?")
+			create = prompt.BoxConfirm("The AWS Credentials config file is empty or missing!", "Do you want to add one now?")
 		} else {
-			create = terminal.BoxPromptBool("The AWS Credentials in your config file aren't working!", "Do you want to update it now?")
+			create = prompt.BoxConfirm("The AWS Credentials in your config file aren't working!", "Do you want to update it now?")
 		}
 
 		if !create {
@@ -64,8 +81,8 @@ func CheckCreds() (bool, string) {
 func (a *awsmCreds) addCredsDialog() string {
 	// TODO prompt for default, or named alternatives
 
-	accessKey := terminal.PromptString("What is your AWS Access Key Id?")
-	secretKey := terminal.PromptString("What is your AWS Secret Access Key?")
+	accessKey := prompt.String("What is your AWS Access Key Id?")
+	secretKey := prompt.String("What is your AWS Secret Access Key?")
 
 	// Add Credentials to the ~/.aws/credentials file
 	profile := Profile{Name: "default", AccessKeyID: accessKey, SecretAccessKey: secretKey}
@@ -89,6 +106,12 @@ func (a *awsmCreds) addCredsDialog() string {
 
 // testCreds verifies our credentials work and returns the current account ID
 func testCreds() (string, error) {
+	// Try to get the account id from STS, this works for users and assumed roles alike
+	identity, err := GetCallerIdentity()
+	if err == nil {
+		return identity.Account, nil
+	}
+
 	// Try to get the account id from our current users IAM creds
 	iamUser, err := GetIAMUser("")
 	if err == nil {
@@ -102,7 +125,7 @@ func testCreds() (string, error) {
 	}
 
 	// Try to get the account if from the ec2metadata
-	sess := session.Must(session.NewSession())
+	sess := sessions.New(aws.NewConfig())
 	svc := ec2metadata.New(sess)
 
 	instanceDocument, err := svc.GetInstanceIdentityDocument()
@@ -154,30 +177,41 @@ func readCreds() (*awsmCreds, error) {
 	return config, err
 }
 
-func GetRegionListWithoutIgnored() []*ec2.Region {
+// GetRegionListWithoutIgnored returns the selected regions, without any regions ignored in the AWS Credentials config file
+func GetRegionListWithoutIgnored() ([]*ec2.Region, error) {
 
 	var ignoredRegions []string
 
-	// Try to read the config file
+	// Ignored regions are only read from the config file if there is one, credentials can come from anywhere in the chain
 	cfg, err := readCreds()
-	if err != nil || len(cfg.Profiles) == 0 {
-		return []*ec2.Region{}
+	if err == nil {
+		for _, profile := range cfg.Profiles {
+			ignoredRegions = append(profile.IgnoreRegions, ignoredRegions...)
+		}
 	}
 
-	for _, profile := range cfg.Profiles {
-		ignoredRegions = append(profile.IgnoreRegions, ignoredRegions...)
+	regionList, err := regions.GetRegionList()
+	if err != nil {
+		return nil, err
 	}
 
-	regions := regions.GetRegionList()
+	// Limit to the selected regions, if any
+	var selectedRegions []*ec2.Region
+	for _, region := range regionList {
+		if regions.Selected(*region.RegionName) {
+			selectedRegions = append(selectedRegions, region)
+		}
+	}
+	regionList = selectedRegions
 
 	if len(ignoredRegions) == 0 {
-		return regions
+		return regionList, nil
 	}
 
 	var adjustedRegions []*ec2.Region
 
 Loop:
-	for _, region := range regions {
+	for _, region := range regionList {
 		for _, ignoredRegion := range ignoredRegions {
 			if ignoredRegion == *region.RegionName {
 				/*terminal.Information("Region [" + *region.RegionName + "] is being ignored...")*/
@@ -187,7 +221,7 @@ Loop:
 		adjustedRegions = append(adjustedRegions, region)
 	}
 
-	return adjustedRegions
+	return adjustedRegions, nil
 }
 
 // SaveCreds Saves our list of profiles into the config file
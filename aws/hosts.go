@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -15,9 +16,10 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/murdinc/awsm/aws/sessions"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -61,7 +63,7 @@ func DeleteResourceRecords(search string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Resource Records?") {
+	if !prompt.Confirm("Are you sure you want to delete these Resource Records?") {
 		return errors.New("Aborting!")
 	}
 
@@ -79,17 +81,28 @@ func DeleteResourceRecords(search string, dryRun bool) error {
 		)
 	}
 
-	return changeResourceRecord(changeSet, dryRun)
+	err = changeResourceRecord(context.Background(), changeSet, dryRun, newProgress(TerminalObserver))
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
 }
 
 func (h *HostedZone) GetResourceRecords(search string) (*ResourceRecords, error) {
-	regions := GetRegionListWithoutIgnored()
+	resourceRecordList := new(ResourceRecords)
+
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return resourceRecordList, err
+	}
+
 	rand.Seed(time.Now().UnixNano())
 	region := regions[rand.Intn(len(regions))] // pick a random region
 
-	resourceRecordList := new(ResourceRecords)
-
-	err := GetRegionResourceRecords(h.Id, *region.RegionName, resourceRecordList, search)
+	err = GetRegionResourceRecords(h.Id, *region.RegionName, resourceRecordList, search)
 	if err != nil {
 		return resourceRecordList, err
 	}
@@ -101,121 +114,188 @@ func (h *HostedZone) GetResourceRecords(search string) (*ResourceRecords, error)
 	return resourceRecordList, nil
 }
 
+// CreateResourceRecordOptions are the options for CreateResourceRecordWithContext
+type CreateResourceRecordOptions struct {
+	Name    string
+	Value   string // the IP address of this instance is looked up in the ec2 meta-data if empty
+	TTL     int64  // 300 if not set
+	Upsert  bool   // update the Resource Record if it already exists
+	Private bool   // look up the private IP address instead of the public one
+	DryRun  bool
+
+	// ConfirmUpsert is called when the Resource Record already exists and Upsert is not set, it is updated instead if it returns true
+	ConfirmUpsert func(err error) bool
+
+	Observer Observer
+}
+
+// CreateResourceRecordResult is the result of CreateResourceRecordWithContext
+type CreateResourceRecordResult struct {
+	HostedZone HostedZone
+	Change     ResourceRecordChange
+	Warnings   []string
+}
+
 // CreateResourceRecord creates an AWS Route53 Resource Record
 func CreateResourceRecord(name, value string, ttl string, force, private, dryRun bool) error {
 
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	// --force flag
+	if force {
+		terminal.Information("--force flag is set, using UPSERT Action!")
+	}
+
+	var ttlInt int64
+	if ttl == "" {
+		ttlInt = 300
+		terminal.Information("Using default TTL of [300]")
+	} else {
+		var err error
+		ttlInt, err = strconv.ParseInt(ttl, 10, 64)
+		if err != nil {
+			return errors.New("Unable to use TTL value [" + ttl + "]")
+		}
+	}
+
+	opts := CreateResourceRecordOptions{
+		Name:    name,
+		Value:   value,
+		TTL:     ttlInt,
+		Upsert:  force,
+		Private: private,
+		DryRun:  dryRun,
+		ConfirmUpsert: func(err error) bool {
+			terminal.Information(err.Error())
+			return prompt.Confirm("Do you want to update (UPSERT) it instead?")
+		},
+		Observer: TerminalObserver,
+	}
+
+	_, err := CreateResourceRecordWithContext(context.Background(), opts)
+	if err == ErrAborted {
+		terminal.ErrorLine("Aborting!")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// CreateResourceRecordWithContext creates an AWS Route53 Resource Record in the Hosted Zone matching its name, without any terminal output or prompts
+func CreateResourceRecordWithContext(ctx context.Context, opts CreateResourceRecordOptions) (*CreateResourceRecordResult, error) {
+	p := newProgress(opts.Observer)
+	name, value := opts.Name, opts.Value
+
 	// If we were not passed a value, try to get it from the ec2metadata instead
 	if value == "" {
-		terminal.Notice("No value given, attempting to get value from ec2 meta-data...")
-		sess := session.Must(session.NewSession())
+		p.notice("No value given, attempting to get value from ec2 meta-data...")
+		sess := sessions.New(aws.NewConfig())
 		svc := ec2metadata.New(sess)
 
-		if private {
+		if opts.Private {
 			// Forced private ip
-			localIp, err := svc.GetMetadata("local-ipv4")
+			localIp, err := svc.GetMetadataWithContext(ctx, "local-ipv4")
 			if localIp == "" || err != nil {
-				terminal.Notice("Unable to find a Local IPv4 Address, please provide a value instead. Aborting!")
-				return err
+				return nil, errors.New("Unable to find a Local IPv4 Address, please provide a value instead. Aborting!")
 			}
 			value = localIp
 		} else {
 			// prefer public ip
-			terminal.Information("Trying to fine a Public IP Address...")
-			publicIp, err := svc.GetMetadata("public-ipv4")
+			p.info("Trying to fine a Public IP Address...")
+			publicIp, err := svc.GetMetadataWithContext(ctx, "public-ipv4")
 			if publicIp == "" || err != nil {
-				terminal.Notice("Unable to find a Public IPv4 Address, looking for a Local IPv4 Address...")
-				localIp, err := svc.GetMetadata("local-ipv4")
+				p.notice("Unable to find a Public IPv4 Address, looking for a Local IPv4 Address...")
+				localIp, err := svc.GetMetadataWithContext(ctx, "local-ipv4")
 				if localIp == "" || err != nil {
-					terminal.Notice("Unable to find a Local IPv4 Address, please provide a value instead. Aborting!")
-					return err
+					return nil, errors.New("Unable to find a Local IPv4 Address, please provide a value instead. Aborting!")
 				}
 				value = localIp
 			} else {
 				value = publicIp
 			}
-			terminal.Delta("Using IP Address: " + value)
+			p.change("Using IP Address: " + value)
 		}
 	}
 
-	// --dry-run flag
-	if dryRun {
-		terminal.Information("--dry-run flag is set, not making any actual changes!")
-	}
-
 	action := "CREATE"
-	// --force flag
-	if force {
-		terminal.Information("--force flag is set, using UPSERT Action!")
+	if opts.Upsert {
 		action = "UPSERT"
 	}
 
-	var ttlInt int64
-	if ttl == "" {
-		ttlInt = 300
-		terminal.Information("Using default TTL of [300]")
-	} else {
-		var err error
-		ttlInt, err = strconv.ParseInt(ttl, 10, 64)
-		if err != nil {
-			return errors.New("Unable to use TTL value [" + ttl + "]")
-		}
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = 300
 	}
 
 	if validRecord(name) {
-		terminal.Information("Name [" + name + "] appears to be a valid DNS Record.")
+		p.info("Name [" + name + "] appears to be a valid DNS Record.")
 	} else {
-		return errors.New("Name [" + name + "] appears to be invalid!")
+		return nil, errors.New("Name [" + name + "] appears to be invalid!")
 	}
 
 	recordType := "CNAME"
 
 	switch {
 	default:
-		return errors.New("Value [" + value + "] is of an unknown type!")
+		return nil, errors.New("Value [" + value + "] is of an unknown type!")
 
 	case govalidator.IsIPv4(value):
-		terminal.Information("Value [" + value + "] appears to be a valid IPv4 Address.")
+		p.info("Value [" + value + "] appears to be a valid IPv4 Address.")
 		recordType = "A"
 
 	case govalidator.IsIPv6(value):
-		terminal.Information("Value [" + value + "] appears to be a valid IPv6 Address.")
+		p.info("Value [" + value + "] appears to be a valid IPv6 Address.")
 		recordType = "AAAA"
 
 	}
 
 	hostedZone, err := findHostedZone(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	terminal.Information(fmt.Sprintf("Found Hosted Zone [%s - %s] with [%d] existing records.", hostedZone.Id, hostedZone.Name, hostedZone.ResourceRecordSetCount))
+	p.info(fmt.Sprintf("Found Hosted Zone [%s - %s] with [%d] existing records.", hostedZone.Id, hostedZone.Name, hostedZone.ResourceRecordSetCount))
 
-	changeSet := make(map[string][]ResourceRecordChange)
-
-	changeSet[hostedZone.Id] = append(changeSet[hostedZone.Id],
-		ResourceRecordChange{
+	result := &CreateResourceRecordResult{
+		HostedZone: hostedZone,
+		Change: ResourceRecordChange{
 			Action: action,
 			Name:   name,
 			Values: []string{value},
 			Type:   recordType,
-			TTL:    int(ttlInt),
+			TTL:    int(ttl),
 		},
-	)
-
-	err = changeResourceRecord(changeSet, dryRun)
-	if !force && err != nil && strings.Contains(err.Error(), "already exists") {
-		terminal.Information(err.Error())
-		update := terminal.PromptBool("Do you want to update (UPSERT) it instead?")
-		if update {
-			changeSet[hostedZone.Id][0].Action = "UPSERT"
-			return changeResourceRecord(changeSet, dryRun)
+	}
+
+	changeSet := map[string][]ResourceRecordChange{
+		hostedZone.Id: {result.Change},
+	}
+
+	err = changeResourceRecord(ctx, changeSet, opts.DryRun, p)
+	if !opts.Upsert && err != nil && strings.Contains(err.Error(), "already exists") {
+		if opts.ConfirmUpsert == nil || !opts.ConfirmUpsert(err) {
+			return nil, ErrAborted
 		}
 
-		terminal.ErrorLine("Aborting!")
-		return nil
+		result.Change.Action = "UPSERT"
+		changeSet[hostedZone.Id][0].Action = "UPSERT"
+		err = changeResourceRecord(ctx, changeSet, opts.DryRun, p)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Warnings = p.warnings
 
-	return err
+	return result, nil
 }
 
 func findHostedZone(name string) (HostedZone, error) {
@@ -247,7 +327,7 @@ func findHostedZone(name string) (HostedZone, error) {
 }
 
 // private function without terminal prompts
-func changeResourceRecord(changeSet map[string][]ResourceRecordChange, dryRun bool) error {
+func changeResourceRecord(ctx context.Context, changeSet map[string][]ResourceRecordChange, dryRun bool, p *progress) error {
 
 	for id, changes := range changeSet {
 
@@ -292,7 +372,7 @@ func changeResourceRecord(changeSet map[string][]ResourceRecordChange, dryRun bo
 				resourceRecords[j].SetValue(value)
 			}
 
-			terminal.Delta("[" + change.Action + "] - Resource Record [" + change.Name + "] : [" + strings.Join(change.Values, ", ") + "]")
+			p.change("[" + change.Action + "] - Resource Record [" + change.Name + "] : [" + strings.Join(change.Values, ", ") + "]")
 
 			recordChanges[i].ResourceRecordSet.SetResourceRecords(resourceRecords)
 
@@ -303,15 +383,18 @@ func changeResourceRecord(changeSet map[string][]ResourceRecordChange, dryRun bo
 		params.SetChangeBatch(changeBatch)
 
 		if !dryRun {
-			regions := GetRegionListWithoutIgnored()
+			regions, err := GetRegionListWithoutIgnored()
+			if err != nil {
+				return err
+			}
 
 			rand.Seed(time.Now().UnixNano())
 			region := regions[rand.Intn(len(regions))] // pick a random region
 
-			sess := session.Must(session.NewSession(&aws.Config{Region: region.RegionName}))
+			sess := newSession(aws.StringValue(region.RegionName))
 			svc := route53.New(sess)
 
-			_, err := svc.ChangeResourceRecordSets(params)
+			_, err = svc.ChangeResourceRecordSetsWithContext(ctx, params)
 			if err != nil {
 				if awsErr, ok := err.(awserr.Error); ok {
 					return errors.New(awsErr.Message())
@@ -321,8 +404,6 @@ func changeResourceRecord(changeSet map[string][]ResourceRecordChange, dryRun bo
 		}
 	}
 
-	terminal.Information("Done!")
-
 	return nil
 }
 
@@ -336,7 +417,10 @@ func GetResourceRecords(search string) (*ResourceRecords, error) {
 		return resourceRecordList, err
 	}
 
-	regions := GetRegionListWithoutIgnored()
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return resourceRecordList, err
+	}
 
 	rand.Seed(time.Now().UnixNano())
 	region := regions[rand.Intn(len(regions))] // pick a random region
@@ -359,7 +443,7 @@ func GetRegionResourceRecords(hostedZoneId, region string, resourceRecordList *R
 		HostedZoneId: aws.String(hostedZoneId),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := route53.New(sess)
 
 	var resourceRecordSetsResult []*route53.ResourceRecordSet
@@ -412,12 +496,15 @@ ListLoop:
 func GetHostedZones(search string) (*HostedZones, error) {
 
 	hostedZoneList := new(HostedZones)
-	regions := GetRegionListWithoutIgnored()
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return hostedZoneList, err
+	}
 
 	rand.Seed(time.Now().UnixNano())
 	region := regions[rand.Intn(len(regions))] // pick a random region
 
-	err := GetRegionHostedZones(*region.RegionName, hostedZoneList, search)
+	err = GetRegionHostedZones(*region.RegionName, hostedZoneList, search)
 
 	return hostedZoneList, err
 }
@@ -425,7 +512,7 @@ func GetHostedZones(search string) (*HostedZones, error) {
 // GetRegionHostedZones returns a list of HostedZones for a given region into the provided HostedZones slice
 func GetRegionHostedZones(region string, hostedZoneList *HostedZones, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := route53.New(sess)
 
 	params := &route53.ListHostedZonesInput{}
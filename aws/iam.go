@@ -9,9 +9,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -54,7 +55,7 @@ func GetIAMUser(username string) (IAMUser, error) {
 		params.SetUserName(username)
 	}
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	resp, err := svc.GetUser(params)
@@ -71,25 +72,37 @@ func GetIAMUser(username string) (IAMUser, error) {
 }
 
 // GetIAMUsers returns a list of IAM Users that match the provided search term
-func GetIAMUsers(search string) (iamList *IAMUsers, err error) {
+func GetIAMUsers(search string) (*IAMUsers, error) {
+	iamList := new(IAMUsers)
 
-	sess := session.Must(session.NewSession())
+	errs := collectAccounts("IAM Users", func(account Account) error {
+		return getAccountIAMUsers(account, iamList, search)
+	})
+	if len(errs) > 0 {
+		return iamList, errs[0]
+	}
+
+	return iamList, nil
+}
+
+// getAccountIAMUsers returns a list of the IAM Users of an account that match the provided search term into the provided IAMUsers slice
+func getAccountIAMUsers(account Account, iamList *IAMUsers, search string) error {
+
+	sess := account.Session(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	result, err := svc.ListUsers(&iam.ListUsersInput{}) // TODO truncated?
 
 	if err != nil {
-		terminal.ShowErrorMessage("Error gathering IAM Users list", err.Error())
-		return &IAMUsers{}, err
+		return err
 	}
 
 	iam := make(IAMUsers, len(result.Users))
 	for i, user := range result.Users {
 		iam[i].Marshal(user)
+		iam[i].Account = account.Name
 	}
 
-	iamList = new(IAMUsers)
-
 	if search != "" {
 		term := regexp.MustCompile(search)
 	Loop:
@@ -109,13 +122,13 @@ func GetIAMUsers(search string) (iamList *IAMUsers, err error) {
 		*iamList = append(*iamList, iam[:]...)
 	}
 
-	return iamList, nil
+	return nil
 }
 
 // GetIAMRole returns a single IAM Role that matches the provided name
 func GetIAMRole(name string) (IAMRole, error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.GetRoleInput{
@@ -136,7 +149,7 @@ func GetIAMRole(name string) (IAMRole, error) {
 // GetIAMRolePolicyNames returns the names of IAM Role Policies that are embedded in the provided IAM Role
 func GetIAMRolePolicyNames(roleName string) ([]string, error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.ListRolePoliciesInput{
@@ -154,7 +167,7 @@ func GetIAMRolePolicyNames(roleName string) ([]string, error) {
 // GetIAMAttachedRolePolicyNames returns the names of IAM Role Policies that are attached to the provided IAM Role
 func GetIAMAttachedRolePolicyARNs(roleName string) ([]string, error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.ListAttachedRolePoliciesInput{
@@ -176,25 +189,37 @@ func GetIAMAttachedRolePolicyARNs(roleName string) ([]string, error) {
 }
 
 // GetIAMRoles returns a list of IAM Roles that matches the provided name
-func GetIAMRoles(search string) (iamRoleList *IAMRoles, err error) {
+func GetIAMRoles(search string) (*IAMRoles, error) {
+	iamRoleList := new(IAMRoles)
+
+	errs := collectAccounts("IAM Roles", func(account Account) error {
+		return getAccountIAMRoles(account, iamRoleList, search)
+	})
+	if len(errs) > 0 {
+		return iamRoleList, errs[0]
+	}
+
+	return iamRoleList, nil
+}
 
-	sess := session.Must(session.NewSession())
+// getAccountIAMRoles returns a list of the IAM Roles of an account that match the provided search term into the provided IAMRoles slice
+func getAccountIAMRoles(account Account, iamRoleList *IAMRoles, search string) error {
+
+	sess := account.Session(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	result, err := svc.ListRoles(&iam.ListRolesInput{})
 
 	if err != nil {
-		terminal.ShowErrorMessage("Error gathering IAM Roles list", err.Error())
-		return &IAMRoles{}, err
+		return err
 	}
 
 	iam := make(IAMRoles, len(result.Roles))
 	for i, role := range result.Roles {
 		iam[i].Marshal(role)
+		iam[i].Account = account.Name
 	}
 
-	iamRoleList = new(IAMRoles)
-
 	if search != "" {
 		term := regexp.MustCompile(search)
 	Loop:
@@ -214,7 +239,7 @@ func GetIAMRoles(search string) (iamRoleList *IAMRoles, err error) {
 		*iamRoleList = append(*iamRoleList, iam[:]...)
 	}
 
-	return iamRoleList, nil
+	return nil
 }
 
 // GetIAMRoleByName returns an IAM Role that matches the provided name
@@ -291,7 +316,7 @@ func GetIAMPolicyDocument(search string, version string) (IAMPolicyDocument, err
 		version = policy.DefaultVersionId
 	}
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.GetPolicyVersionInput{
@@ -313,7 +338,7 @@ func GetIAMPolicyDocument(search string, version string) (IAMPolicyDocument, err
 // GetIAMPolicyByARN returns a single IAM Policy that matches the provided ARN
 func GetIAMPolicyByARN(policyARN string) (iamPolicy *IAMPolicy, err error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.GetPolicyInput{
@@ -336,7 +361,7 @@ func GetIAMPolicyByARN(policyARN string) (iamPolicy *IAMPolicy, err error) {
 // GetIAMPolicies returns a list of IAM Policies that matches the provided name
 func GetIAMPolicies(search string) (iamPolicyList *IAMPolicies, err error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	result, err := svc.ListPolicies(&iam.ListPoliciesInput{})
@@ -378,7 +403,7 @@ func GetIAMPolicies(search string) (iamPolicyList *IAMPolicies, err error) {
 // GetIAMProfile returns a single IAM Profile that matches the provided name
 func GetIAMInstanceProfile(name string) (IAMInstanceProfile, error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.GetInstanceProfileInput{
@@ -397,25 +422,37 @@ func GetIAMInstanceProfile(name string) (IAMInstanceProfile, error) {
 }
 
 // GetIAMInstanceProfiles returns a list of IAM Profiles that matches the provided name
-func GetIAMInstanceProfiles(search string) (iamProfileList *IAMInstanceProfiles, err error) {
+func GetIAMInstanceProfiles(search string) (*IAMInstanceProfiles, error) {
+	iamProfileList := new(IAMInstanceProfiles)
+
+	errs := collectAccounts("IAM Instance Profile", func(account Account) error {
+		return getAccountIAMInstanceProfiles(account, iamProfileList, search)
+	})
+	if len(errs) > 0 {
+		return iamProfileList, errs[0]
+	}
 
-	sess := session.Must(session.NewSession())
+	return iamProfileList, nil
+}
+
+// getAccountIAMInstanceProfiles returns a list of the IAM Instance Profiles of an account that match the provided search term into the provided IAMInstanceProfiles slice
+func getAccountIAMInstanceProfiles(account Account, iamProfileList *IAMInstanceProfiles, search string) error {
+
+	sess := account.Session(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	result, err := svc.ListInstanceProfiles(&iam.ListInstanceProfilesInput{})
 
 	if err != nil {
-		terminal.ShowErrorMessage("Error gathering IAM Instance Profile list", err.Error())
-		return &IAMInstanceProfiles{}, err
+		return err
 	}
 
 	iam := make(IAMInstanceProfiles, len(result.InstanceProfiles))
 	for i, profile := range result.InstanceProfiles {
 		iam[i].Marshal(profile)
+		iam[i].Account = account.Name
 	}
 
-	iamProfileList = new(IAMInstanceProfiles)
-
 	if search != "" {
 		term := regexp.MustCompile(search)
 	Loop:
@@ -435,13 +472,13 @@ func GetIAMInstanceProfiles(search string) (iamProfileList *IAMInstanceProfiles,
 		*iamProfileList = append(*iamProfileList, iam[:]...)
 	}
 
-	return iamProfileList, nil
+	return nil
 }
 
 // GetIAMInstanceProfiles returns a list of IAM Profiles that matches the provided name
 func GetIAMInstanceProfilesForRole(roleName string) (iamInstanceProfileList IAMInstanceProfiles, err error) {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.ListInstanceProfilesForRoleInput{
@@ -466,7 +503,7 @@ func GetIAMInstanceProfilesForRole(roleName string) (iamInstanceProfileList IAMI
 // RemoveIAMRoleFromInstanceProfile removes an IAM Role from an Instance Profile
 func RemoveIAMRoleFromInstanceProfile(roleName, instanceProfileName string) error {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.RemoveRoleFromInstanceProfileInput{
@@ -488,7 +525,7 @@ func RemoveIAMRoleFromInstanceProfile(roleName, instanceProfileName string) erro
 // DetachIAMRolePolicy detaches an IAM Role from a policy
 func DetachIAMRolePolicy(roleName, policyArn string) error {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.DetachRolePolicyInput{
@@ -543,7 +580,7 @@ func AttachIAMRolePolicy(roleName, policy string, dryRun bool) error {
 	policies.PrintTable()
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to attach these policies to this IAM Role?") {
+	if !prompt.Confirm("Are you sure you want to attach these policies to this IAM Role?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -578,7 +615,7 @@ func AttachIAMRolePolicyByARN(roleName, policyARN string, dryRun bool) error {
 	}
 
 	if !dryRun {
-		sess := session.Must(session.NewSession())
+		sess := newSession(settings.Current().DefaultRegion)
 		svc := iam.New(sess)
 
 		params := &iam.AttachRolePolicyInput{
@@ -619,7 +656,7 @@ func AddIAMRoleToInstanceProfile(roleName, instanceProfileName string, dryRun bo
 	}
 
 	if !dryRun {
-		sess := session.Must(session.NewSession())
+		sess := newSession(settings.Current().DefaultRegion)
 		svc := iam.New(sess)
 
 		params := &iam.AddRoleToInstanceProfileInput{
@@ -773,7 +810,7 @@ func (i *IAMInstanceProfiles) PrintTable() {
 // CreateIAMUser creates a new IAM User with the provided username and path
 func CreateIAMUser(username, path string) error {
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.CreateUserInput{
@@ -811,7 +848,7 @@ func CreateIAMPolicy(policyName, policyDocument, path, description string, dryRu
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.CreatePolicyInput{
@@ -859,7 +896,7 @@ func CreateIAMRole(roleName, rolePolicyDocument, path string, dryRun bool) (stri
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.CreateRoleInput{
@@ -904,7 +941,7 @@ func CreateIAMInstanceProfile(instanceProfileName, path string, dryRun bool) (st
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	sess := session.Must(session.NewSession())
+	sess := newSession(settings.Current().DefaultRegion)
 	svc := iam.New(sess)
 
 	params := &iam.CreateInstanceProfileInput{
@@ -957,7 +994,7 @@ func DeleteIAMUsers(username string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these IAM Users?") {
+	if !prompt.Confirm("Are you sure you want to delete these IAM Users?") {
 		terminal.ErrorLine("Aborting!")
 		return
 	}
@@ -965,7 +1002,7 @@ func DeleteIAMUsers(username string, dryRun bool) (err error) {
 	if !dryRun {
 		// Delete 'Em
 		for _, user := range *userList {
-			sess := session.Must(session.NewSession())
+			sess := newSession(settings.Current().DefaultRegion)
 			svc := iam.New(sess)
 
 			params := &iam.DeleteUserInput{
@@ -1008,7 +1045,7 @@ func DeleteIAMRoles(name string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these IAM Roles?") {
+	if !prompt.Confirm("Are you sure you want to delete these IAM Roles?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -1016,7 +1053,7 @@ func DeleteIAMRoles(name string, dryRun bool) error {
 	if !dryRun {
 		// Delete 'Em
 		for _, role := range *roleList {
-			sess := session.Must(session.NewSession())
+			sess := newSession(settings.Current().DefaultRegion)
 			svc := iam.New(sess)
 
 			// Get the instance profiles for this role
@@ -1079,7 +1116,7 @@ func DeleteIAMInstanceProfiles(search string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these IAM Instance Profiles?") {
+	if !prompt.Confirm("Are you sure you want to delete these IAM Instance Profiles?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -1087,7 +1124,7 @@ func DeleteIAMInstanceProfiles(search string, dryRun bool) error {
 	if !dryRun {
 		// Delete 'Em
 		for _, instProfile := range *instProfileList {
-			sess := session.Must(session.NewSession())
+			sess := newSession(settings.Current().DefaultRegion)
 			svc := iam.New(sess)
 
 			params := &iam.DeleteInstanceProfileInput{
@@ -1130,7 +1167,7 @@ func DeleteIAMPolicies(name string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these IAM Policies?") {
+	if !prompt.Confirm("Are you sure you want to delete these IAM Policies?") {
 		terminal.ErrorLine("Aborting!")
 		return
 	}
@@ -1138,7 +1175,7 @@ func DeleteIAMPolicies(name string, dryRun bool) (err error) {
 	if !dryRun {
 		// Delete 'Em
 		for _, policy := range *policyList {
-			sess := session.Must(session.NewSession())
+			sess := newSession(settings.Current().DefaultRegion)
 			svc := iam.New(sess)
 
 			params := &iam.DeletePolicyInput{
@@ -12,11 +12,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -44,7 +44,7 @@ func GetImagesByTag(region, key, value string, available bool) (Images, error) {
 
 	imgList := new(Images)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeImagesInput{
@@ -82,7 +82,7 @@ func GetImagesByTag(region, key, value string, available bool) (Images, error) {
 // GetImageById returns an Amazon Machine Image via its ID
 func GetImageById(region, id string) (Image, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeImagesInput{
@@ -119,33 +119,24 @@ func GetLatestImageByTag(region, key, value string) (Image, error) {
 
 // GetImages returns a slice of Images based on the provided search term and optional available flag
 func GetImages(search string, available bool) (*Images, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	imgList := new(Images)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionImages(*region.RegionName, imgList, search, available)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering image list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("image", func(account Account, region string) error {
+		return getAccountRegionImages(account, region, imgList, search, available)
+	})
 
 	return imgList, errs
 }
 
 // GetRegionImages returns a slice of AMI's into the passed Image slice based on the provided region and search term, and optional available flag
 func GetRegionImages(region string, imgList *Images, search string, available bool) error {
+	return getAccountRegionImages(CurrentAccount(), region, imgList, search, available)
+}
+
+// getAccountRegionImages returns a slice of AMI's into the passed Image slice based on the provided region of an account and search term, and optional available flag
+func getAccountRegionImages(account Account, region string, imgList *Images, search string, available bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeImages(&ec2.DescribeImagesInput{Owners: []*string{aws.String("self")}})
@@ -157,6 +148,7 @@ func GetRegionImages(region string, imgList *Images, search string, available bo
 	img := make(Images, len(result.Images))
 	for i, image := range result.Images {
 		img[i].Marshal(image, region)
+		img[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -203,7 +195,8 @@ func CopyImage(search, region string, dryRun bool) error {
 	}
 
 	// Get the source image
-	images, _ := GetImages(search, true)
+	images, errs := GetImages(search, true)
+	ShowErrors(errs)
 	imgCount := len(*images)
 	if imgCount == 0 {
 		return errors.New("No available images found for your search terms.")
@@ -223,14 +216,15 @@ func CopyImage(search, region string, dryRun bool) error {
 
 	terminal.Delta("Created Image [" + *copyImageResp.ImageId + "] named [" + image.Name + "] to [" + region + "]!")
 
-	// Add Tags
-	return SetEc2NameAndClassTags(copyImageResp.ImageId, image.Name, image.Class, region)
+	// Add Tags, with the custom tags of the class if it has one
+	imageCfg, _ := config.LoadImageClass(image.Class)
+	return SetEc2ClassTags(copyImageResp.ImageId, image.Name, image.Class, imageCfg.Tags, region)
 }
 
 // private function without prompts
 func copyImage(image Image, region string, dryRun bool) (*ec2.CopyImageOutput, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Copy image to the destination region
@@ -280,7 +274,8 @@ func CreateImage(class, search string, dryRun bool) error {
 	}
 
 	// Locate the Instance
-	instances, _ := GetInstances(sourceInstance, true)
+	instances, errs := GetInstances(sourceInstance, true)
+	ShowErrors(errs)
 	instCount := len(*instances)
 	if instCount == 0 {
 		return errors.New("No running instances found matching [" + sourceInstance + "], Aborting!")
@@ -299,7 +294,7 @@ func CreateImage(class, search string, dryRun bool) error {
 		instTable.PrintTable()
 
 		// Confirm
-		if !terminal.PromptBool("Are you sure you want to create an image from this instance and set it as the default for the " + class + " class?") {
+		if !prompt.Confirm("Are you sure you want to create an image from this instance and set it as the default for the " + class + " class?") {
 			return errors.New("Aborting!")
 		}
 
@@ -325,7 +320,7 @@ func CreateImage(class, search string, dryRun bool) error {
 	terminal.Delta("Created Image [" + *createImageResp.ImageId + "] named [" + name + "] in [" + region + "]!")
 
 	// Add Tags
-	err = SetEc2NameAndClassTags(createImageResp.ImageId, name, class, region)
+	err = SetEc2ClassTags(createImageResp.ImageId, name, class, cfg.Tags, region)
 
 	if err != nil {
 		return err
@@ -350,6 +345,11 @@ func CreateImage(class, search string, dryRun bool) error {
 		// Copy to other regions
 		for _, propRegion := range cfg.PropagateRegions {
 
+			if !regions.Selected(propRegion) {
+				terminal.Notice("Region [" + propRegion + "] is not selected, skipping propagation!")
+				continue
+			}
+
 			if propRegion != region {
 
 				wg.Add(1)
@@ -364,7 +364,7 @@ func CreateImage(class, search string, dryRun bool) error {
 						errs = append(errs, err)
 					} else {
 						// Add Tags
-						err = SetEc2NameAndClassTags(copyImageResp.ImageId, name, class, propRegion)
+						err = SetEc2ClassTags(copyImageResp.ImageId, name, class, cfg.Tags, propRegion)
 						terminal.Delta(fmt.Sprintf("Copied image [%s] to region [%s].", sourceImage.ImageID, propRegion))
 					}
 
@@ -399,11 +399,15 @@ func rotateImages(class string, cfg config.ImageClass, dryRun bool) error {
 
 	launchConfigs, err := GetLaunchConfigurations("")
 	if err != nil {
+		ShowErrors(err)
 		return errors.New("Error while retrieving the list of assets to exclude from rotation!")
 	}
 	lockedImages := launchConfigs.LockedImageIds()
 
-	regions := GetRegionListWithoutIgnored()
+	regions, regionErr := GetRegionListWithoutIgnored()
+	if regionErr != nil {
+		return regionErr
+	}
 
 	for _, region := range regions {
 		wg.Add(1)
@@ -450,7 +454,7 @@ func rotateImages(class string, cfg config.ImageClass, dryRun bool) error {
 // waitForImage waits for an Image to complete being created
 func waitForImage(imageID, region string, dryRun bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Wait for the snapshot to complete.
@@ -472,7 +476,7 @@ func waitForImage(imageID, region string, dryRun bool) error {
 // private function without terminal prompts
 func createImage(instanceID, name, region string, dryRun bool) (*ec2.CreateImageOutput, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Create the Image
@@ -558,7 +562,9 @@ func DeleteImages(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionImages(region, imgList, search, false)
 	} else {
-		imgList, _ = GetImages(search, false)
+		var errs []error
+		imgList, errs = GetImages(search, false)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -573,7 +579,7 @@ func DeleteImages(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Images?") {
+	if !prompt.Confirm("Are you sure you want to delete these Images?") {
 		return errors.New("Aborting!")
 	}
 
@@ -595,7 +601,7 @@ func DeleteImages(search, region string, dryRun bool) (err error) {
 func deleteImages(imgList *Images, dryRun bool) (err error) {
 	for _, image := range *imgList {
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(image.Region)}))
+		sess := newSession(image.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeregisterImageInput{
@@ -0,0 +1,286 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/terminal"
+)
+
+// imdsMode describes the metadata options of an Instance for the IMDS column, eg: v2 only, 1 hop
+func imdsMode(httpTokens string, hopLimit int, endpoint string) string {
+	if endpoint == "disabled" {
+		return "disabled"
+	}
+
+	var mode string
+	switch httpTokens {
+	case "required":
+		mode = "v2 only"
+	case "optional":
+		mode = "v1 + v2"
+	default:
+		return ""
+	}
+
+	if hopLimit == 1 {
+		return mode + ", 1 hop"
+	}
+	return fmt.Sprintf("%s, %d hops", mode, hopLimit)
+}
+
+// validateMetadataOptions returns an error if the metadata options of an Instance class are invalid
+func validateMetadataOptions(instanceCfg config.InstanceClass) error {
+	switch instanceCfg.MetadataHTTPTokens {
+	case "", "optional", "required":
+	default:
+		return errors.New("Invalid metadata HTTP tokens [" + instanceCfg.MetadataHTTPTokens + "], expected optional or required!")
+	}
+
+	if instanceCfg.MetadataHopLimit < 0 || instanceCfg.MetadataHopLimit > 64 {
+		return fmt.Errorf("Invalid metadata hop limit [%d], expected 1 to 64!", instanceCfg.MetadataHopLimit)
+	}
+
+	switch instanceCfg.MetadataEndpoint {
+	case "", "enabled", "disabled":
+	default:
+		return errors.New("Invalid metadata endpoint [" + instanceCfg.MetadataEndpoint + "], expected enabled or disabled!")
+	}
+
+	return nil
+}
+
+// metadataOptions returns the metadata options of an Instance class, or nil if it leaves them at the AWS defaults
+func metadataOptions(instanceCfg config.InstanceClass) *ec2.InstanceMetadataOptionsRequest {
+	if instanceCfg.MetadataHTTPTokens == "" && instanceCfg.MetadataHopLimit == 0 && instanceCfg.MetadataEndpoint == "" {
+		return nil
+	}
+
+	options := new(ec2.InstanceMetadataOptionsRequest)
+	if instanceCfg.MetadataHTTPTokens != "" {
+		options.HttpTokens = aws.String(instanceCfg.MetadataHTTPTokens)
+	}
+	if instanceCfg.MetadataHopLimit > 0 {
+		options.HttpPutResponseHopLimit = aws.Int64(int64(instanceCfg.MetadataHopLimit))
+	}
+	if instanceCfg.MetadataEndpoint != "" {
+		options.HttpEndpoint = aws.String(instanceCfg.MetadataEndpoint)
+	}
+
+	return options
+}
+
+// launchConfigurationMetadataOptions returns the metadata options of an Instance class for a Launch Configuration
+func launchConfigurationMetadataOptions(instanceCfg config.InstanceClass) *autoscaling.InstanceMetadataOptions {
+	options := metadataOptions(instanceCfg)
+	if options == nil {
+		return nil
+	}
+
+	return &autoscaling.InstanceMetadataOptions{
+		HttpTokens:              options.HttpTokens,
+		HttpPutResponseHopLimit: options.HttpPutResponseHopLimit,
+		HttpEndpoint:            options.HttpEndpoint,
+	}
+}
+
+// InstanceMetadataChanges is a slice of changes to the metadata options of Instances
+type InstanceMetadataChanges []InstanceMetadataChange
+
+// InstanceMetadataChange is a change to the metadata options of an Instance, to match its class
+type InstanceMetadataChange struct {
+	Instance   Instance
+	HTTPTokens string // empty if unchanged
+	HopLimit   int    // 0 if unchanged
+	Endpoint   string // empty if unchanged
+}
+
+// UpdateInstanceMetadataOptions are the options for UpdateInstanceMetadataWithContext
+type UpdateInstanceMetadataOptions struct {
+	Search string
+	Region string // optional, all regions are searched if empty
+	DryRun bool
+
+	// Confirm is called with the changes before any are made, nothing is changed if it returns false
+	Confirm func(changes InstanceMetadataChanges) bool
+
+	Observer Observer
+}
+
+// UpdateInstanceMetadataResult is the result of UpdateInstanceMetadataWithContext
+type UpdateInstanceMetadataResult struct {
+	Instances Instances
+	Changes   InstanceMetadataChanges
+	Warnings  []string
+}
+
+// UpdateInstanceMetadata brings the metadata options of Instances that match the provided search term and optional region into compliance with their classes
+func UpdateInstanceMetadata(search, region string, dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	opts := UpdateInstanceMetadataOptions{
+		Search: search,
+		Region: region,
+		DryRun: dryRun,
+		Confirm: func(changes InstanceMetadataChanges) bool {
+			return prompt.Confirm("Are you sure you want to update the metadata options of these Instances?")
+		},
+		Observer: TerminalObserver,
+	}
+
+	result, err := UpdateInstanceMetadataWithContext(context.Background(), opts)
+	if err == ErrAborted {
+		terminal.ErrorLine("Aborting!")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(result.Changes) == 0 {
+		result.Instances.PrintTable()
+		terminal.Information("The metadata options of these Instances already match their classes!")
+		return nil
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// UpdateInstanceMetadataWithContext modifies the metadata options of Instances to match their classes, without any terminal output or prompts.
+// Options that a class leaves empty are not enforced.
+func UpdateInstanceMetadataWithContext(ctx context.Context, opts UpdateInstanceMetadataOptions) (*UpdateInstanceMetadataResult, error) {
+	p := newProgress(opts.Observer)
+
+	instList := new(Instances)
+
+	// Check if we were given a region or not
+	if opts.Region != "" {
+		err := GetRegionInstances(opts.Region, instList, opts.Search, false)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var errs []error
+		instList, errs = GetInstances(opts.Search, false)
+		p.warnErrors(errs)
+	}
+
+	result := new(UpdateInstanceMetadataResult)
+
+	classes := make(map[string]*config.InstanceClass)
+	for _, instance := range *instList {
+		if instance.State != "running" && instance.State != "stopped" {
+			continue
+		}
+		result.Instances = append(result.Instances, instance)
+
+		if instance.Class == "" {
+			continue
+		}
+
+		instanceCfg, ok := classes[instance.Class]
+		if !ok {
+			cfg, err := config.LoadInstanceClass(instance.Class)
+			if err != nil {
+				p.warn("Unable to load Instance class [" + instance.Class + "] of Instance [" + instance.Name + "]: " + err.Error())
+			} else if err = validateMetadataOptions(cfg); err != nil {
+				return nil, err
+			} else {
+				instanceCfg = &cfg
+			}
+			classes[instance.Class] = instanceCfg
+		}
+		if instanceCfg == nil {
+			continue
+		}
+
+		change := InstanceMetadataChange{Instance: instance}
+		var updates []string
+
+		if instanceCfg.MetadataHTTPTokens != "" && instanceCfg.MetadataHTTPTokens != instance.MetadataHTTPTokens {
+			change.HTTPTokens = instanceCfg.MetadataHTTPTokens
+			updates = append(updates, "HTTP Tokens: "+instance.MetadataHTTPTokens+" > "+change.HTTPTokens)
+		}
+		if instanceCfg.MetadataHopLimit > 0 && instanceCfg.MetadataHopLimit != instance.MetadataHopLimit {
+			change.HopLimit = instanceCfg.MetadataHopLimit
+			updates = append(updates, fmt.Sprintf("Hop Limit: %d > %d", instance.MetadataHopLimit, change.HopLimit))
+		}
+		if instanceCfg.MetadataEndpoint != "" && instanceCfg.MetadataEndpoint != instance.MetadataEndpoint {
+			change.Endpoint = instanceCfg.MetadataEndpoint
+			updates = append(updates, "Endpoint: "+instance.MetadataEndpoint+" > "+change.Endpoint)
+		}
+
+		if len(updates) > 0 {
+			p.change(fmt.Sprintf("[%s %s] - Update -	[%s]", instance.Name, instance.Region, strings.Join(updates, ", ")))
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	if len(result.Instances) == 0 {
+		return nil, errors.New("No running or stopped Instances found, Aborting!")
+	}
+
+	if len(result.Changes) == 0 {
+		result.Warnings = p.warnings
+		return result, nil
+	}
+
+	// Confirm
+	if opts.Confirm != nil && !opts.Confirm(result.Changes) {
+		return nil, ErrAborted
+	}
+
+	for _, change := range result.Changes {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		if opts.DryRun {
+			p.notice("Would update the metadata options of Instance [" + change.Instance.Name + "]")
+			continue
+		}
+
+		params := &ec2.ModifyInstanceMetadataOptionsInput{
+			InstanceId: aws.String(change.Instance.InstanceID),
+		}
+		if change.HTTPTokens != "" {
+			params.HttpTokens = aws.String(change.HTTPTokens)
+		}
+		if change.HopLimit > 0 {
+			params.HttpPutResponseHopLimit = aws.Int64(int64(change.HopLimit))
+		}
+		if change.Endpoint != "" {
+			params.HttpEndpoint = aws.String(change.Endpoint)
+		}
+
+		svc := ec2.New(newSession(change.Instance.Region))
+		_, err := svc.ModifyInstanceMetadataOptionsWithContext(ctx, params)
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok {
+				err = errors.New(awsErr.Message())
+			}
+			result.Warnings = p.warnings
+			return result, errors.New("Unable to update the metadata options of Instance [" + change.Instance.Name + "]: " + err.Error())
+		}
+
+		p.change("Updated the metadata options of Instance [" + change.Instance.Name + "] in [" + change.Instance.Region + "]!")
+	}
+
+	result.Warnings = p.warnings
+
+	return result, nil
+}
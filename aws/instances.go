@@ -1,17 +1,21 @@
 package aws
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
-	"fmt"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/hashicorp/hil"
@@ -19,7 +23,8 @@ import (
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
-	"github.com/murdinc/cli"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -32,26 +37,11 @@ type Instance models.Instance
 
 // GetInstances returns a list of EC2 Instances that match the provided search term and optional running flag
 func GetInstances(search string, running bool) (*Instances, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	instList := new(Instances)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionInstances(*region.RegionName, instList, search, running)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering instance list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
 
-	wg.Wait()
+	errs := collectAccountRegions("instance", func(account Account, region string) error {
+		return getAccountRegionInstances(account, region, instList, search, running)
+	})
 
 	return instList, errs
 }
@@ -84,6 +74,7 @@ func (i *Instance) Marshal(instance *ec2.Instance, region string, subList *Subne
 
 	i.Name = GetTagValue("Name", instance.Tags)
 	i.Class = GetTagValue("Class", instance.Tags)
+	i.Sequence = GetTagValue("Sequence", instance.Tags)
 	i.InstanceID = aws.StringValue(instance.InstanceId)
 	i.AvailabilityZone = aws.StringValue(instance.Placement.AvailabilityZone)
 	i.PrivateIP = aws.StringValue(instance.PrivateIpAddress)
@@ -93,6 +84,9 @@ func (i *Instance) Marshal(instance *ec2.Instance, region string, subList *Subne
 	i.Root = aws.StringValue(instance.RootDeviceType)
 	i.Size = aws.StringValue(instance.InstanceType)
 	i.Virtualization = aws.StringValue(instance.VirtualizationType)
+	i.Architecture = aws.StringValue(instance.Architecture)
+	i.EnaSupport = aws.BoolValue(instance.EnaSupport)
+	i.EbsOptimized = aws.BoolValue(instance.EbsOptimized)
 	i.State = aws.StringValue(instance.State.Name)
 	i.KeyPair = aws.StringValue(instance.KeyName)
 	i.VPCID = aws.StringValue(instance.VpcId)
@@ -107,14 +101,26 @@ func (i *Instance) Marshal(instance *ec2.Instance, region string, subList *Subne
 		i.IamInstanceProfileName = iamInstanceProfileName.ProfileName
 	}
 
+	if instance.MetadataOptions != nil {
+		i.MetadataHTTPTokens = aws.StringValue(instance.MetadataOptions.HttpTokens)
+		i.MetadataHopLimit = int(aws.Int64Value(instance.MetadataOptions.HttpPutResponseHopLimit))
+		i.MetadataEndpoint = aws.StringValue(instance.MetadataOptions.HttpEndpoint)
+		i.IMDS = imdsMode(i.MetadataHTTPTokens, i.MetadataHopLimit, i.MetadataEndpoint)
+	}
+
 	// TODO
 	//instance.SecurityGroups
 }
 
 // GetRegionInstances returns a slice of Instances into the passed Instances slice based on the provided region and search term, and optional running flag
 func GetRegionInstances(region string, instList *Instances, search string, running bool) error {
+	return getAccountRegionInstances(CurrentAccount(), region, instList, search, running)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionInstances returns a slice of Instances into the passed Instances slice based on the provided region of an account and search term, and optional running flag
+func getAccountRegionInstances(account Account, region string, instList *Instances, search string, running bool) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeInstances(&ec2.DescribeInstancesInput{})
@@ -125,29 +131,22 @@ func GetRegionInstances(region string, instList *Instances, search string, runni
 	subList := new(Subnets)
 	vpcList := new(Vpcs)
 	imgList := new(Images)
-	GetRegionSubnets(region, subList, "")
-	GetRegionVpcs(region, vpcList, "")
-	GetRegionImages(region, imgList, "", false)
+	getAccountRegionSubnets(account, region, subList, "")
+	getAccountRegionVpcs(account, region, vpcList, "")
+	getAccountRegionImages(account, region, imgList, "", false)
 
 	for _, reservation := range result.Reservations {
 		inst := make(Instances, len(reservation.Instances))
 		for i, instance := range reservation.Instances {
 			inst[i].Marshal(instance, region, subList, vpcList, imgList)
+			inst[i].Account = account.Name
 		}
 
 		if search != "" {
 			term := regexp.MustCompile(search)
-		Loop:
 			for i, in := range inst {
-				rInst := reflect.ValueOf(in)
-
-				for k := 0; k < rInst.NumField(); k++ {
-					sVal := rInst.Field(k).String()
-
-					if term.MatchString(sVal) && ((running && inst[i].State == "running") || !running) {
-						*instList = append(*instList, inst[i])
-						continue Loop
-					}
+				if in.matches(term) && ((running && inst[i].State == "running") || !running) {
+					*instList = append(*instList, inst[i])
 				}
 			}
 		} else {
@@ -165,6 +164,17 @@ func GetRegionInstances(region string, instList *Instances, search string, runni
 	return nil
 }
 
+// matches returns true if any field of the Instance matches the search term
+func (i Instance) matches(term *regexp.Regexp) bool {
+	rInst := reflect.ValueOf(i)
+	for k := 0; k < rInst.NumField(); k++ {
+		if term.MatchString(rInst.Field(k).String()) {
+			return true
+		}
+	}
+	return false
+}
+
 // PrintTable Prints an ascii table of the list of Instances
 func (i *Instances) PrintTable() {
 	if len(*i) == 0 {
@@ -185,77 +195,205 @@ func (i *Instances) PrintTable() {
 	table.Render()
 }
 
-// LaunchInstance Launches a new EC2 Instance
-func LaunchInstance(class, sequence, az string, dryRun bool) error {
+// LaunchInstanceOptions are the options for LaunchInstanceWithContext
+type LaunchInstanceOptions struct {
+	Class    string
+	Sequence string
+	AZ       string // picked by the Availability Zone policy if empty, see selectAZs
+	Region   string // to pick the Availability Zone in, the default region of the environment if empty
+	AMI      string // AMI id to use if the Instance class has no AMI class configured
+	DryRun   bool
+
+	// Wait until the Instance is running, passes its status checks and registers with SSM, then run the post-launch
+	// commands and DNS registration of its class. Gives up after WaitTimeout, 15 minutes if not set.
+	Wait        bool
+	WaitTimeout time.Duration
+
+	Observer Observer
+}
+
+// LaunchInstanceResult is the result of LaunchInstanceWithContext
+type LaunchInstanceResult struct {
+	InstanceID string
+	Region     string
+	Instance   Instance
+	VolumeIDs  []string
+	Warnings   []string
+
+	// With Wait set
+	Ready       bool
+	Checks      ReadyChecks
+	Invocations CommandInvocations // of the post-launch commands
+	DNSName     string
+}
+
+// ReadyChecks represents the checks waited on after launching an Instance, in order
+type ReadyChecks []ReadyCheck
+
+// ReadyCheck represents a single check waited on after launching an Instance
+type ReadyCheck struct {
+	Instance string
+	Name     string
+	Status   string // passed, failed or skipped
+	Duration time.Duration
+	Detail   string
+}
+
+// ErrNoAMI is returned when an Instance class has no AMI class configured and no AMI was provided
+var ErrNoAMI = errors.New("There is no AMI class configured for this Instance class and no AMI was provided!")
+
+// LaunchInstance Launches a new EC2 Instance, optionally waiting for it to be ready. The Availability Zone is picked
+// in the region (or the default region) if not given.
+func LaunchInstance(class, sequence, az, region string, wait bool, waitTimeout time.Duration, dryRun bool) error {
 
 	// --dry-run flag
 	if dryRun {
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	// Instance Class Config
+	opts := LaunchInstanceOptions{
+		Class:       class,
+		Sequence:    sequence,
+		AZ:          az,
+		Region:      region,
+		DryRun:      dryRun,
+		Wait:        wait,
+		WaitTimeout: waitTimeout,
+		Observer:    TerminalObserver,
+	}
+
+	// Ask for an AMI up front if the class doesn't have one
 	instanceCfg, err := config.LoadInstanceClass(class)
 	if err != nil {
 		return err
 	}
+	if instanceCfg.AMI == "" {
+		opts.AMI = prompt.String("There is no AMI class configured for this Instance class, please provide an AMI to use:")
+	}
+
+	result, err := LaunchInstanceWithContext(context.Background(), opts)
+	if result != nil && wait {
+		result.Checks.PrintTable()
+	}
+	if err != nil {
+		return err
+	}
+
+	inst := Instances{result.Instance}
+	inst.PrintTable()
+
+	if wait {
+		terminal.Information("Instance is ready!")
+	}
+
+	terminal.Information("Finished Launching Instance!")
+
+	return nil
+}
+
+// LaunchInstanceWithContext launches a new EC2 Instance of a class, without any terminal output or prompts
+func LaunchInstanceWithContext(ctx context.Context, opts LaunchInstanceOptions) (*LaunchInstanceResult, error) {
+	p := newProgress(opts.Observer)
+	class, sequence, dryRun := opts.Class, opts.Sequence, opts.DryRun
+
+	// Instance Class Config
+	instanceCfg, err := config.LoadInstanceClass(class)
+	if err != nil {
+		return nil, err
+	}
+
+	p.info("Found Instance class configuration for [" + class + "]!")
+
+	err = validateCapacity(instanceCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateMetadataOptions(instanceCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// AZ, picked by the policy if not given
+	if opts.AZ == "" {
+		region, err := launchRegion(opts.Region, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	terminal.Information("Found Instance class configuration for [" + class + "]!")
+		existing, errs := getClassInstances(class)
+		if len(errs) > 0 {
+			return nil, errors.New("Unable to look up the existing Instances of class [" + class + "]: " + errs[0].Error())
+		}
+
+		placements, err := selectAZs(ctx, p, instanceCfg, class, region, nil, existing, []string{class + sequence})
+		if err != nil {
+			return nil, err
+		}
+		opts.AZ = placements[0]
+	}
 
-	// AZ
-	azs, _ := regions.GetAZs()
-	if !azs.ValidAZ(az) {
-		return cli.NewExitError("Availability Zone ["+az+"] is Invalid!", 1)
+	azs, errs := regions.GetAZs()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if !azs.ValidAZ(opts.AZ) {
+		return nil, errors.New("Availability Zone [" + opts.AZ + "] is Invalid!")
 	}
 
-	terminal.Information("Found Availability Zone [" + az + "]!")
+	p.info("Found Availability Zone [" + opts.AZ + "]!")
 
-	region := azs.GetRegion(az)
+	region := azs.GetRegion(opts.AZ)
 
 	// AMI
 	var ami Image
 
 	if instanceCfg.AMI == "" {
-		amiId := terminal.PromptString("There is no AMI class configured for this Instance class, please provide an AMI to use:")
-		ami, err = GetImageById(region, amiId)
+		if opts.AMI == "" {
+			return nil, ErrNoAMI
+		}
+
+		ami, err = GetImageById(region, opts.AMI)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		terminal.Information("Found AMI [" + ami.ImageID + "] with name [" + ami.AmiName + "] created [" + humanize.Time(ami.CreationDate) + "]!")
+		p.info("Found AMI [" + ami.ImageID + "] with name [" + ami.AmiName + "] created [" + humanize.Time(ami.CreationDate) + "]!")
 
 	} else {
 		ami, err = GetLatestImageByTag(region, "Class", instanceCfg.AMI)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		terminal.Information("Found AMI [" + ami.ImageID + "] with class [" + ami.Class + "] created [" + humanize.Time(ami.CreationDate) + "]!")
+		p.info("Found AMI [" + ami.ImageID + "] with class [" + ami.Class + "] created [" + humanize.Time(ami.CreationDate) + "]!")
 	}
 
 	// EBS
 	ebsVolumes := make([]*ec2.BlockDeviceMapping, len(instanceCfg.EBSVolumes))
 	ebsVolumeNames := make(map[string]string)
 	ebsVolumeClasses := make(map[string]string)
+	ebsVolumeTags := make(map[string]map[string]string)
 	for i, ebsClass := range instanceCfg.EBSVolumes {
 		volCfg, err := config.LoadVolumeClass(ebsClass)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		terminal.Information("Found Volume Class Configuration for [" + ebsClass + "]!")
+		p.info("Found Volume Class Configuration for [" + ebsClass + "]!")
 
 		var snapshotId string
 		if volCfg.Snapshot == "" {
-			terminal.Information("No snapshot configured for [" + ebsClass + "]! Creating a fresh volume instead.")
+			p.info("No snapshot configured for [" + ebsClass + "]! Creating a fresh volume instead.")
 
 		} else {
 			latestSnapshot, err := GetLatestSnapshotByTag(region, "Class", volCfg.Snapshot)
 			if err != nil {
-				return err
-			} else {
-				terminal.Information("Found Snapshot [" + latestSnapshot.SnapshotID + "] with class [" + latestSnapshot.Class + "] created [" + humanize.Time(latestSnapshot.StartTime) + "]!")
-				snapshotId = latestSnapshot.SnapshotID
+				return nil, err
 			}
+
+			p.info("Found Snapshot [" + latestSnapshot.SnapshotID + "] with class [" + latestSnapshot.Class + "] created [" + humanize.Time(latestSnapshot.StartTime) + "]!")
+			snapshotId = latestSnapshot.SnapshotID
 		}
 
 		ebsVolumes[i] = &ec2.BlockDeviceMapping{
@@ -281,12 +419,13 @@ func LaunchInstance(class, sequence, az string, dryRun bool) error {
 
 		ebsVolumeNames[volCfg.DeviceName] = class + sequence + "-" + ebsClass
 		ebsVolumeClasses[volCfg.DeviceName] = ebsClass
+		ebsVolumeTags[volCfg.DeviceName] = volCfg.Tags
 
 	}
 
 	// EBS Optimized
 	if instanceCfg.EbsOptimized {
-		terminal.Information("Launching as EBS Optimized")
+		p.info("Launching as EBS Optimized")
 	}
 
 	// IAM Instance Profile
@@ -294,30 +433,19 @@ func LaunchInstance(class, sequence, az string, dryRun bool) error {
 	if len(instanceCfg.IAMInstanceProfile) > 0 {
 		iam, err = GetIAMInstanceProfile(instanceCfg.IAMInstanceProfile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		terminal.Information("Found IAM Instance Profile [" + iam.ProfileName + "]!")
+		p.info("Found IAM Instance Profile [" + iam.ProfileName + "]!")
 	}
 
 	// KeyPair
-	keyPair, err := GetKeyPairByName(region, instanceCfg.KeyName)
+	keyPair, err := ensureKeyPair(p, region, instanceCfg.KeyName, dryRun)
 	if err != nil {
-		// Try to create it?
-		terminal.Information("Unable to find KeyPair [" + instanceCfg.KeyName + "] in [" + region + "], trying to create it...")
-
-		err = CreateKeyPair(instanceCfg.KeyName, region, dryRun)
-		if err != nil {
-			return err
-		}
-
-		keyPair, err = GetKeyPairByName(region, instanceCfg.KeyName)
-		if err != nil {
-			return err
-		}
+		return nil, err
 	}
 
-	terminal.Information("Found KeyPair [" + keyPair.KeyName + "] in [" + keyPair.Region + "]!")
+	p.info("Found KeyPair [" + keyPair.KeyName + "] in [" + keyPair.Region + "]!")
 
 	// Network Interfaces
 
@@ -332,82 +460,65 @@ func LaunchInstance(class, sequence, az string, dryRun bool) error {
 		// VPC
 		vpc, err = GetRegionVpcByTag(region, "Class", instanceCfg.Vpc)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		terminal.Information("Found VPC [" + vpc.VpcID + "] in Region [" + region + "]!")
+		p.info("Found VPC [" + vpc.VpcID + "] in Region [" + region + "]!")
 
-		// Subnet
-		subnet, err = vpc.GetVpcSubnetByTag("Class", instanceCfg.Subnet)
+		// Subnet, the one of the Subnet class in the Availability Zone
+		subnets, err := vpc.GetVpcSubnetsByTag("Class", instanceCfg.Subnet)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		for _, s := range subnets {
+			if s.AvailabilityZone == opts.AZ {
+				subnet = s
+				break
+			}
+		}
+		if subnet.SubnetID == "" {
+			return nil, errors.New("No Subnet with class [" + instanceCfg.Subnet + "] in Availability Zone [" + opts.AZ + "], Aborting!")
 		}
 
 		subnetID = subnet.SubnetID
-		terminal.Information("Found Subnet [" + subnet.SubnetID + "] in VPC [" + subnet.VpcID + "]!")
+		p.info("Found Subnet [" + subnet.SubnetID + "] in VPC [" + subnet.VpcID + "]!")
 
 		// VPC Security Groups
 		secGroups, err := vpc.GetVpcSecurityGroupByTagMulti("Class", instanceCfg.SecurityGroups)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for i, secGroup := range secGroups {
-			terminal.Information("Found VPC Security Group [" + secGroup.GroupID + "] with name [" + secGroup.Name + "]!")
+			p.info("Found VPC Security Group [" + secGroup.GroupID + "] with name [" + secGroup.Name + "]!")
 			secGroupIds[i] = aws.String(secGroup.GroupID)
 		}
 
 	} else {
-		terminal.Information("No VPC and/or Subnet specified for instance Class [" + class + "]!")
+		p.info("No VPC and/or Subnet specified for instance Class [" + class + "]!")
 
 		// EC2-Classic security groups
 		secGroups, err := GetSecurityGroupByTagMulti(region, "Class", instanceCfg.SecurityGroups)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for i, secGroup := range secGroups {
-			terminal.Information("Found Security Group [" + secGroup.GroupID + "] with name [" + secGroup.Name + "]!")
+			p.info("Found Security Group [" + secGroup.GroupID + "] with name [" + secGroup.Name + "]!")
 			secGroupIds[i] = aws.String(secGroup.GroupID)
 		}
 
 	}
 
 	// Parse Userdata
-	tree, err := hil.Parse(instanceCfg.UserData)
-	if err != nil {
-		return err
-	}
-
-	config := &hil.EvalConfig{
-		GlobalScope: &ast.BasicScope{
-			VarMap: map[string]ast.Variable{
-				"var.class": {
-					Type:  ast.TypeString,
-					Value: class,
-				},
-				"var.sequence": {
-					Type:  ast.TypeString,
-					Value: sequence,
-				},
-				"var.locale": {
-					Type:  ast.TypeString,
-					Value: region,
-				},
-			},
-		},
-	}
-
-	result, err := hil.Eval(tree, config)
+	parsedUserData, err := evalClassTemplate(instanceCfg.UserData, class, sequence, region)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	parsedUserData := result.Value.(string)
-
 	if dryRun {
-		terminal.Notice("User Data:")
-		fmt.Println(parsedUserData)
+		p.notice("User Data:\n" + parsedUserData)
 	}
 
 	params := &ec2.RunInstancesInput{
@@ -425,21 +536,31 @@ func LaunchInstance(class, sequence, az string, dryRun bool) error {
 		Monitoring: &ec2.RunInstancesMonitoringEnabled{
 			Enabled: aws.Bool(instanceCfg.Monitoring),
 		},
-		UserData: aws.String(base64.StdEncoding.EncodeToString([]byte(parsedUserData))),
+		UserData:  aws.String(base64.StdEncoding.EncodeToString([]byte(parsedUserData))),
+		Placement: instancePlacement(instanceCfg, opts.AZ),
 		/*
-			Placement: &ec2.Placement{ // havent played around with placements yet, TODO?
-				Affinity:         aws.String("String"),
-				AvailabilityZone: aws.String("String"),
-				GroupName:        aws.String("String"),
-				HostId:           aws.String("String"),
-				Tenancy:          aws.String("Tenancy"),
-			},
 			PrivateIpAddress: aws.String("String"),
 			KernelId:         aws.String("String"),
 			RamdiskId:        aws.String("String"),
 		*/
 	}
 
+	// Spot / Capacity Reservation
+	if marketOptions := instanceMarketOptions(instanceCfg); marketOptions != nil {
+		p.info("Launching as a Spot Instance")
+		params.InstanceMarketOptions = marketOptions
+	}
+	if reservation := capacityReservation(instanceCfg); reservation != nil {
+		p.info("Targeting Capacity Reservation [" + instanceCfg.CapacityReservation + "]")
+		params.CapacityReservationSpecification = reservation
+	}
+
+	// Instance Metadata Service
+	if options := metadataOptions(instanceCfg); options != nil {
+		p.info("Using the Instance Metadata Service options of the Instance class")
+		params.MetadataOptions = options
+	}
+
 	if instanceCfg.PublicIPAddress {
 		params.SetNetworkInterfaces([]*ec2.InstanceNetworkInterfaceSpecification{
 			{
@@ -458,221 +579,1044 @@ func LaunchInstance(class, sequence, az string, dryRun bool) error {
 		params.BlockDeviceMappings = ebsVolumes
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	if dryRun {
-		terminal.Notice("Params:")
-		fmt.Println(params.String())
+		p.notice("Params:\n" + params.String())
 	}
 
-	launchInstanceResp, err := svc.RunInstances(params)
+	launchInstanceResp, err := svc.RunInstancesWithContext(ctx, params)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return errors.New(awsErr.Message())
+			return nil, errors.New(awsErr.Message())
 		}
+		return nil, err
 	}
 
 	instance := launchInstanceResp.Instances[0]
 
-	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok {
-			return errors.New(awsErr.Message())
-		}
-		return err
+	launched := &LaunchInstanceResult{
+		InstanceID: aws.StringValue(instance.InstanceId),
+		Region:     region,
 	}
 
-	terminal.Delta("Launching Instance:")
-
-	inst := make(Instances, 1)
-	inst[0].Marshal(instance, region, &Subnets{subnet}, &Vpcs{vpc}, &Images{ami})
+	launched.Instance.Marshal(instance, region, &Subnets{subnet}, &Vpcs{vpc}, &Images{ami})
+	launched.Instance.Name = class + sequence
+	launched.Instance.Class = class
+	launched.Instance.AMIName = ami.ImageID
 
-	inst[0].Name = class + sequence
-	inst[0].Class = class
-	inst[0].AMIName = ami.ImageID
-
-	inst.PrintTable()
+	p.change("Launching Instance [" + launched.InstanceID + "] named [" + class + sequence + "] in [" + opts.AZ + "]!")
 
-	terminal.Notice("Waiting to tag Instance...")
+	p.notice("Waiting to tag Instance...")
 
 	// Wait to tag it
-	err = svc.WaitUntilInstanceExists(&ec2.DescribeInstancesInput{
+	err = svc.WaitUntilInstanceExistsWithContext(ctx, &ec2.DescribeInstancesInput{
 		DryRun: aws.Bool(dryRun),
 		InstanceIds: []*string{
-			launchInstanceResp.Instances[0].InstanceId,
+			instance.InstanceId,
 		},
 	})
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return errors.New(awsErr.Message())
+			return launched, errors.New(awsErr.Message())
 		}
+		return launched, err
 	}
 
-	terminal.Delta("Adding EC2 Tags...")
+	p.change("Adding EC2 Tags...")
 
 	// Add Instance Tags
 	instanceTagsParams := &ec2.CreateTagsInput{
 		Resources: []*string{instance.InstanceId},
-		Tags: []*ec2.Tag{
-			{
-				Key:   aws.String("Name"),
-				Value: aws.String(class + sequence),
-			},
-			{
-				Key:   aws.String("Sequence"),
-				Value: aws.String(sequence),
-			},
-			{
-				Key:   aws.String("Class"),
-				Value: aws.String(class),
-			},
-		},
+		Tags: ec2Tags(mergeTags(instanceCfg.Tags, map[string]string{
+			"Name":     class + sequence,
+			"Sequence": sequence,
+			"Class":    class,
+		})),
 		DryRun: aws.Bool(dryRun),
 	}
-	_, err = svc.CreateTags(instanceTagsParams)
+	_, err = svc.CreateTagsWithContext(ctx, instanceTagsParams)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return errors.New(awsErr.Message())
+			return launched, errors.New(awsErr.Message())
 		}
-		return err
+		return launched, err
 	}
 
-	if len(ebsVolumes) > 0 {
-		terminal.Notice("Waiting to tag EBS Volumes...")
+	// The root Volume gets the custom tags of the Instance class too
+	if len(ebsVolumes) > 0 || len(instanceCfg.Tags) > 0 {
+		p.notice("Waiting to tag EBS Volumes...")
 
 		// Wait to tag it
-		err = svc.WaitUntilInstanceRunning(&ec2.DescribeInstancesInput{
+		err = svc.WaitUntilInstanceRunningWithContext(ctx, &ec2.DescribeInstancesInput{
 			DryRun: aws.Bool(dryRun),
 			InstanceIds: []*string{
-				launchInstanceResp.Instances[0].InstanceId,
+				instance.InstanceId,
 			},
 		})
 		if err != nil {
 			if awsErr, ok := err.(awserr.Error); ok {
-				return errors.New(awsErr.Message())
+				return launched, errors.New(awsErr.Message())
 			}
+			return launched, err
 		}
 
-		terminal.Delta("Adding EBS Tags...")
+		p.change("Adding EBS Tags...")
 
 		// Add EBS Volume Tags
-		ebsVols, err := GetVolumesByInstanceID(region, aws.StringValue(instance.InstanceId))
+		ebsVols, err := GetVolumesByInstanceID(region, launched.InstanceID)
 		if err != nil {
 			if awsErr, ok := err.(awserr.Error); ok {
-				return errors.New(awsErr.Message())
+				return launched, errors.New(awsErr.Message())
 			}
-			return err
+			return launched, err
 		}
 
 		for _, ebsVol := range ebsVols {
+			launched.VolumeIDs = append(launched.VolumeIDs, ebsVol.VolumeID)
+
+			volumeTags := mergeTags(instanceCfg.Tags, ebsVolumeTags[ebsVol.Device])
 			if ebsVolumeNames[ebsVol.Device] != "" || ebsVolumeClasses[ebsVol.Device] != "" {
+				volumeTags["Name"] = ebsVolumeNames[ebsVol.Device]
+				volumeTags["Class"] = ebsVolumeClasses[ebsVol.Device]
+			}
+
+			if len(volumeTags) > 0 {
 				// Add Tags
-				err = SetEc2NameAndClassTags(aws.String(ebsVol.VolumeID), ebsVolumeNames[ebsVol.Device], ebsVolumeClasses[ebsVol.Device], region)
+				err = setEc2Tags(aws.String(ebsVol.VolumeID), volumeTags, region)
 				if err != nil {
-					if awsErr, ok := err.(awserr.Error); ok {
-						return errors.New(awsErr.Message())
-					}
-					return err
+					p.warn("Unable to tag Volume [" + ebsVol.VolumeID + "]: " + err.Error())
 				}
 			}
 		}
 	}
 
-	terminal.Information("Finished Launching Instance!")
+	if opts.Wait {
+		err = waitUntilReady(ctx, p, launched, instanceCfg, class, sequence, opts.WaitTimeout)
+		launched.Warnings = p.warnings
+		if err != nil {
+			return launched, err
+		}
+	}
 
-	return nil
+	launched.Warnings = p.warnings
+
+	return launched, nil
 }
 
-// TerminateInstances terminates EC2 instances based on the given search term and optional region input
-func TerminateInstances(search, region string, dryRun bool) (err error) {
+// defaultWaitTimeout is how long to wait for a launched Instance to be ready if no timeout is set
+const defaultWaitTimeout = 15 * time.Minute
 
-	// --dry-run flag
-	if dryRun {
-		terminal.Information("--dry-run flag is set, not making any actual changes!")
+// waitUntilReady waits for a launched Instance to be running, pass its status checks and register with SSM, then runs
+// the post-launch commands and DNS registration of its class, adding each check to the result as it finishes
+func waitUntilReady(ctx context.Context, p *progress, launched *LaunchInstanceResult, instanceCfg config.InstanceClass, class, sequence string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
 	}
 
-	instList := new(Instances)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Check if we were given a region or not
-	if region != "" {
-		err = GetRegionInstances(region, instList, search, true)
-	} else {
-		instList, _ = GetInstances(search, true)
-	}
+	region := launched.Region
+	svc := ec2.New(newSession(region))
+	instanceIds := []*string{aws.String(launched.InstanceID)}
 
-	if err != nil {
-		return errors.New("Error gathering Instance list")
+	// Poll until the context is done, instead of giving up after the default number of attempts
+	waiterOpts := []request.WaiterOption{
+		request.WithWaiterDelay(request.ConstantWaiterDelay(15 * time.Second)),
+		request.WithWaiterMaxAttempts(int(timeout/(15*time.Second)) + 1),
 	}
 
-	if len(*instList) > 0 {
-		// Print the table
-		instList.PrintTable()
-	} else {
-		return errors.New("No Instances found!")
+	var ssmInstance SSMInstance
+
+	type check struct {
+		name string
+		run  func() error
 	}
 
-	// Confirm
-	if !terminal.PromptBool("Are you sure you want to terminate these Instances?") {
-		return errors.New("Aborting!")
+	checks := []check{
+		{"Running", func() error {
+			p.notice("Waiting for Instance [" + launched.InstanceID + "] to be running...")
+			err := svc.WaitUntilInstanceRunningWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIds}, waiterOpts...)
+			if err != nil {
+				return err
+			}
+
+			// Pick up the IP addresses
+			resp, err := svc.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIds})
+			if err != nil {
+				return err
+			}
+			if len(resp.Reservations) > 0 && len(resp.Reservations[0].Instances) > 0 {
+				instance := resp.Reservations[0].Instances[0]
+				launched.Instance.State = aws.StringValue(instance.State.Name)
+				launched.Instance.PrivateIP = aws.StringValue(instance.PrivateIpAddress)
+				launched.Instance.PublicIP = aws.StringValue(instance.PublicIpAddress)
+			}
+			return nil
+		}},
+		{"Status Checks", func() error {
+			p.notice("Waiting for Instance [" + launched.InstanceID + "] to pass its system and instance status checks...")
+			return svc.WaitUntilInstanceStatusOkWithContext(ctx, &ec2.DescribeInstanceStatusInput{InstanceIds: instanceIds}, waiterOpts...)
+		}},
+		{"SSM Agent", func() error {
+			p.notice("Waiting for Instance [" + launched.InstanceID + "] to register with SSM...")
+			for {
+				var err error
+				ssmInstance, err = GetSSMInstanceById(region, launched.InstanceID)
+				if err == nil && ssmInstance.PingStatus == "Online" {
+					return nil
+				}
+
+				select {
+				case <-time.After(10 * time.Second):
+				case <-ctx.Done():
+					if err != nil {
+						return errors.New(ctx.Err().Error() + ", last error: " + err.Error())
+					}
+					return errors.New(ctx.Err().Error() + ", last ping status: " + ssmInstance.PingStatus)
+				}
+			}
+		}},
 	}
 
-	// Delete 'Em
-	err = terminateInstances(instList, dryRun)
-	if err != nil {
-		return err
+	if len(instanceCfg.PostLaunchCommands) > 0 {
+		checks = append(checks, check{"Post-Launch Commands", func() error {
+			for _, command := range instanceCfg.PostLaunchCommands {
+				invocations, err := runCommandWithContext(ctx, &SSMInstances{ssmInstance}, command, false, p)
+				if invocations != nil {
+					launched.Invocations = append(launched.Invocations, *invocations...)
+				}
+				if err != nil {
+					return err
+				}
+				if invocations == nil || len(*invocations) == 0 {
+					return errors.New("No response to the command [" + command + "]!")
+				}
+				for _, invocation := range *invocations {
+					if invocation.Status != "Success" {
+						return errors.New("The command [" + command + "] finished with status [" + invocation.Status + "]: " + invocation.StatusDetails)
+					}
+				}
+			}
+			return nil
+		}})
 	}
 
-	terminal.Information("Done!")
+	if instanceCfg.DNSName != "" {
+		checks = append(checks, check{"DNS", func() error {
+			name, err := evalClassTemplate(instanceCfg.DNSName, class, sequence, region)
+			if err != nil {
+				return err
+			}
 
-	return nil
-}
+			value := launched.Instance.PublicIP
+			if instanceCfg.DNSPrivate || value == "" {
+				value = launched.Instance.PrivateIP
+			}
 
-// Private function without the confirmation terminal prompts
-func terminateInstances(instList *Instances, dryRun bool) (err error) {
+			record, err := CreateResourceRecordWithContext(ctx, CreateResourceRecordOptions{
+				Name:     name,
+				Value:    value,
+				Upsert:   true,
+				Observer: ObserverFunc(p.forward),
+			})
+			if err != nil {
+				return err
+			}
 
-	for _, instance := range *instList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(instance.Region)}))
-		svc := ec2.New(sess)
+			launched.DNSName = record.Change.Name
+			return nil
+		}})
+	}
 
-		params := &ec2.TerminateInstancesInput{
-			InstanceIds: []*string{
-				aws.String(instance.InstanceID),
-			},
-			DryRun: aws.Bool(dryRun),
+	for i, c := range checks {
+		start := time.Now()
+		err := c.run()
+
+		readyCheck := ReadyCheck{
+			Instance: launched.Instance.Name,
+			Name:     c.name,
+			Status:   "passed",
+			Duration: time.Since(start) / time.Second * time.Second,
 		}
 
-		_, err := svc.TerminateInstances(params)
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok {
-				return errors.New(awsErr.Message())
+			readyCheck.Status = "failed"
+			readyCheck.Detail = err.Error()
+			if ctx.Err() == context.DeadlineExceeded {
+				readyCheck.Detail = "Timed out after " + timeout.String()
+			} else if awsErr, ok := err.(awserr.Error); ok {
+				readyCheck.Detail = awsErr.Message()
 			}
-			return err
+
+			launched.Checks = append(launched.Checks, readyCheck)
+			for _, skipped := range checks[i+1:] {
+				launched.Checks = append(launched.Checks, ReadyCheck{Instance: launched.Instance.Name, Name: skipped.name, Status: "skipped"})
+			}
+
+			return errors.New("Instance [" + launched.Instance.Name + "] is not ready, the [" + c.name + "] check failed: " + readyCheck.Detail)
 		}
 
-		terminal.Delta("Terminated Instance [" + instance.InstanceID + "] named [" + instance.Name + "] in [" + instance.AvailabilityZone + "]!")
+		launched.Checks = append(launched.Checks, readyCheck)
+		p.info("Instance [" + launched.Instance.Name + "] passed the [" + c.name + "] check!")
 	}
 
+	launched.Ready = true
+
 	return nil
 }
 
-// StopInstances stops an EC2 instances based on the given search term and optional region input. A third option "force" will force stop the instance(s)
-func StopInstances(search, region string, force, dryRun bool) (err error) {
-
-	// --dry-run flag
-	if dryRun {
-		terminal.Information("--dry-run flag is set, not making any actual changes!")
+// PrintTable Prints an ascii table of the list of Ready Checks
+func (r ReadyChecks) PrintTable() {
+	if len(r) == 0 {
+		return
 	}
 
-	instList := new(Instances)
-
-	// Check if we were given a region or not
-	if region != "" {
-		err = GetRegionInstances(region, instList, search, false)
-	} else {
-		instList, _ = GetInstances(search, false)
+	rows := make([][]string, len(r))
+	for i, check := range r {
+		var duration string
+		if check.Status != "skipped" {
+			duration = check.Duration.String()
+		}
+		rows[i] = []string{check.Instance, check.Name, check.Status, duration, check.Detail}
 	}
 
-	if err != nil {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Instance", "Check", "Status", "Duration", "Detail"})
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+// evalClassTemplate evaluates a template of a class, like its user data, with its class, sequence and region (locale)
+func evalClassTemplate(template, class, sequence, region string) (string, error) {
+	tree, err := hil.Parse(template)
+	if err != nil {
+		return "", err
+	}
+
+	config := &hil.EvalConfig{
+		GlobalScope: &ast.BasicScope{
+			VarMap: map[string]ast.Variable{
+				"var.class": {
+					Type:  ast.TypeString,
+					Value: class,
+				},
+				"var.sequence": {
+					Type:  ast.TypeString,
+					Value: sequence,
+				},
+				"var.locale": {
+					Type:  ast.TypeString,
+					Value: region,
+				},
+			},
+		},
+	}
+
+	result, err := hil.Eval(tree, config)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Value.(string), nil
+}
+
+// LaunchInstancesOptions are the options for LaunchInstancesWithContext
+type LaunchInstancesOptions struct {
+	Class  string
+	Count  int
+	Region string   // the default region of the environment if empty, or the region of the AZs
+	AZs    []string // only spread the Instances across these Availability Zones, optional
+	AMI    string   // AMI id to use if the Instance class has no AMI class configured
+	DryRun bool
+
+	// Wait for each Instance to be ready, see LaunchInstanceOptions
+	Wait        bool
+	WaitTimeout time.Duration
+
+	// Observer receives the events of every launch, prefixed with the name of the Instance
+	Observer Observer
+}
+
+// LaunchInstancesResult is the result of LaunchInstancesWithContext
+type LaunchInstancesResult struct {
+	Region    string
+	Instances []LaunchInstanceResult // the launched Instances in sequence order, including those that did not get ready
+	Errors    []error                // the Instances that failed to launch or get ready
+	Warnings  []string
+}
+
+// LaunchInstances launches one or more new EC2 Instances of a class, with the next free sequences, spread across Availability Zones
+func LaunchInstances(class string, count int, region string, azList []string, wait bool, waitTimeout time.Duration, dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	opts := LaunchInstancesOptions{
+		Class:       class,
+		Count:       count,
+		Region:      region,
+		AZs:         azList,
+		DryRun:      dryRun,
+		Wait:        wait,
+		WaitTimeout: waitTimeout,
+		Observer:    TerminalObserver,
+	}
+
+	// Ask for an AMI up front if the class doesn't have one
+	instanceCfg, err := config.LoadInstanceClass(class)
+	if err != nil {
+		return err
+	}
+	if instanceCfg.AMI == "" {
+		opts.AMI = prompt.String("There is no AMI class configured for this Instance class, please provide an AMI to use:")
+	}
+
+	result, err := LaunchInstancesWithContext(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	inst := make(Instances, len(result.Instances))
+	var checks ReadyChecks
+	for i, launched := range result.Instances {
+		inst[i] = launched.Instance
+		checks = append(checks, launched.Checks...)
+	}
+	if len(inst) > 0 {
+		inst.PrintTable()
+	}
+	if wait {
+		checks.PrintTable()
+	}
+
+	for _, err := range result.Errors {
+		terminal.ShowErrorMessage("Error launching Instance", err.Error())
+	}
+
+	if len(result.Errors) > 0 {
+		return errors.New("Failed to launch " + strconv.Itoa(len(result.Errors)) + " of " + strconv.Itoa(count) + " Instances!")
+	}
+
+	terminal.Information("Finished Launching " + strconv.Itoa(count) + " Instances!")
+
+	return nil
+}
+
+// LaunchInstancesWithContext launches one or more new EC2 Instances of a class in parallel, without any terminal output or prompts.
+// The sequences follow the highest one in use by the class, and the Instances go to the Availability Zones of the
+// Subnet class (or the region) with the fewest Instances of the class.
+func LaunchInstancesWithContext(ctx context.Context, opts LaunchInstancesOptions) (*LaunchInstancesResult, error) {
+	p := newProgress(opts.Observer)
+	class := opts.Class
+
+	if opts.Count < 1 {
+		return nil, errors.New("The count must be at least 1!")
+	}
+
+	// Instance Class Config
+	instanceCfg, err := config.LoadInstanceClass(class)
+	if err != nil {
+		return nil, err
+	}
+
+	if instanceCfg.AMI == "" && opts.AMI == "" {
+		return nil, ErrNoAMI
+	}
+
+	err = validateCapacity(instanceCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateMetadataOptions(instanceCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Region
+	region, err := launchRegion(opts.Region, opts.AZs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing Instances of the class, from every region
+	existing, errs := getClassInstances(class)
+	if len(errs) > 0 {
+		return nil, errors.New("Unable to look up the existing Instances of class [" + class + "]: " + errs[0].Error())
+	}
+
+	sequences := nextSequences(existing, opts.Count)
+
+	p.info("Found " + strconv.Itoa(len(existing)) + " existing Instances of class [" + class + "], launching sequences [" + strings.Join(sequences, ", ") + "]!")
+
+	// Availability Zones
+	names := make([]string, len(sequences))
+	for i, sequence := range sequences {
+		names[i] = class + sequence
+	}
+
+	placements, err := selectAZs(ctx, p, instanceCfg, class, region, opts.AZs, existing, names)
+	if err != nil {
+		return nil, err
+	}
+
+	// KeyPair, created once up front instead of by every launch
+	if _, err := ensureKeyPair(p, region, instanceCfg.KeyName, opts.DryRun); err != nil {
+		return nil, err
+	}
+
+	result := &LaunchInstancesResult{Region: region}
+	launched := make([]*LaunchInstanceResult, opts.Count)
+	launchErrs := make([]error, opts.Count)
+
+	var wg sync.WaitGroup
+	for i := range sequences {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			name := class + sequences[i]
+
+			var observer Observer
+			if opts.Observer != nil {
+				observer = ObserverFunc(func(e Event) {
+					e.Message = "[" + name + "] " + e.Message
+					opts.Observer.Event(e)
+				})
+			}
+
+			launched[i], launchErrs[i] = LaunchInstanceWithContext(ctx, LaunchInstanceOptions{
+				Class:       class,
+				Sequence:    sequences[i],
+				AZ:          placements[i],
+				AMI:         opts.AMI,
+				DryRun:      opts.DryRun,
+				Wait:        opts.Wait,
+				WaitTimeout: opts.WaitTimeout,
+				Observer:    observer,
+			})
+			if launchErrs[i] != nil {
+				launchErrs[i] = errors.New("Unable to launch Instance [" + name + "] in [" + placements[i] + "]: " + launchErrs[i].Error())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range sequences {
+		if launched[i] != nil {
+			result.Instances = append(result.Instances, *launched[i])
+			result.Warnings = append(result.Warnings, launched[i].Warnings...)
+		}
+		if launchErrs[i] != nil {
+			result.Errors = append(result.Errors, launchErrs[i])
+		}
+	}
+
+	result.Warnings = append(p.warnings, result.Warnings...)
+
+	return result, nil
+}
+
+// launchRegion returns the region to launch Instances in, from the region or Availability Zones asked for
+func launchRegion(region string, azList []string) (string, error) {
+	azs, errs := regions.GetAZs()
+	if len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	for _, az := range azList {
+		if !azs.ValidAZ(az) {
+			return "", errors.New("Availability Zone [" + az + "] is Invalid!")
+		}
+
+		azRegion := azs.GetRegion(az)
+		if region == "" {
+			region = azRegion
+		}
+		if azRegion != region {
+			return "", errors.New("Availability Zone [" + az + "] is not in region [" + region + "]!")
+		}
+	}
+
+	if region == "" {
+		region = settings.Current().DefaultRegion
+	}
+
+	if !regions.ValidRegion(region) {
+		return "", errors.New("Region [" + region + "] is Invalid!")
+	}
+
+	return region, nil
+}
+
+// launchAZs returns the Availability Zones an Instance class can be launched in within a region, those of its Subnet class
+// if it has one, limited to the Availability Zones asked for
+func launchAZs(instanceCfg config.InstanceClass, region string, only []string) ([]string, error) {
+	var azList []string
+
+	if instanceCfg.Vpc != "" && instanceCfg.Subnet != "" {
+		vpc, err := GetRegionVpcByTag(region, "Class", instanceCfg.Vpc)
+		if err != nil {
+			return nil, err
+		}
+
+		subnets, err := vpc.GetVpcSubnetsByTag("Class", instanceCfg.Subnet)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subnet := range subnets {
+			azList = append(azList, subnet.AvailabilityZone)
+		}
+
+	} else {
+		azs, errs := regions.GetAZs()
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+
+		for _, az := range *azs {
+			if az.Region == region && az.State == "available" {
+				azList = append(azList, az.Name)
+			}
+		}
+	}
+
+	if len(only) > 0 {
+		allowed := make(map[string]bool)
+		for _, az := range azList {
+			allowed[az] = true
+		}
+
+		azList = nil
+		for _, az := range only {
+			if !allowed[az] {
+				return nil, errors.New("Availability Zone [" + az + "] can't be used by this Instance class in [" + region + "]!")
+			}
+			azList = append(azList, az)
+		}
+	}
+
+	if len(azList) == 0 {
+		return nil, errors.New("No Availability Zones found to launch in, in region [" + region + "]!")
+	}
+
+	sort.Strings(azList)
+
+	return azList, nil
+}
+
+// azCounts returns the number of Instances in each Availability Zone
+func azCounts(existing Instances) map[string]int {
+	inAZ := make(map[string]int)
+	for _, inst := range existing {
+		inAZ[inst.AvailabilityZone]++
+	}
+	return inAZ
+}
+
+// spreadAZs picks an Availability Zone for each of the named new resources of a class, always the one with the fewest
+// resources of the class given the counts per Availability Zone. The reasoning is sent to the Observer.
+func spreadAZs(p *progress, azList []string, counts map[string]int, names []string, kind, class string) []string {
+	inAZ := make(map[string]int)
+	for az, count := range counts {
+		inAZ[az] = count
+	}
+
+	placements := make([]string, len(names))
+	for i := range placements {
+		best := azList[0]
+		for _, az := range azList[1:] {
+			if inAZ[az] < inAZ[best] {
+				best = az
+			}
+		}
+		placements[i] = best
+
+		// The count of the Availability Zone before this one was placed in it
+		p.info("Picked Availability Zone [" + best + "] for [" + names[i] + "] out of [" + strings.Join(azList, ", ") + "], it has the fewest " + kind + " of class [" + class + "] (" + strconv.Itoa(inAZ[best]) + ")")
+		inAZ[best]++
+	}
+
+	return placements
+}
+
+// instanceTypeAZs returns the Availability Zones of a region that offer an instance type
+func instanceTypeAZs(ctx context.Context, region, instanceType string) (map[string]bool, error) {
+	svc := ec2.New(newSession(region))
+
+	offered := make(map[string]bool)
+	err := svc.DescribeInstanceTypeOfferingsPagesWithContext(ctx, &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String("availability-zone"),
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: []*string{aws.String(instanceType)},
+			},
+		},
+	}, func(page *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range page.InstanceTypeOfferings {
+			offered[aws.StringValue(offering.Location)] = true
+		}
+		return true
+	})
+
+	return offered, err
+}
+
+// selectAZs is the Availability Zone policy, it picks an Availability Zone for each of the named new Instances of a class in
+// a region. Only the Availability Zones of the Subnet class of the Instance class (or all of the region) that offer its
+// instance type are used, and each Instance goes to the one with the fewest Instances of the class. The reasoning is
+// sent to the Observer.
+func selectAZs(ctx context.Context, p *progress, instanceCfg config.InstanceClass, class, region string, only []string, existing Instances, names []string) ([]string, error) {
+	azList, err := launchAZs(instanceCfg, region, only)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(only) == 0 && instanceCfg.Vpc != "" && instanceCfg.Subnet != "" {
+		p.info("Subnet class [" + instanceCfg.Subnet + "] is in Availability Zones [" + strings.Join(azList, ", ") + "]")
+	}
+
+	if instanceCfg.InstanceType != "" {
+		offered, err := instanceTypeAZs(ctx, region, instanceCfg.InstanceType)
+		if err != nil {
+			p.warn("Unable to look up the Availability Zones that offer instance type [" + instanceCfg.InstanceType + "], not checking them: " + err.Error())
+		} else {
+			var available []string
+			for _, az := range azList {
+				if offered[az] {
+					available = append(available, az)
+				} else {
+					p.info("Skipping Availability Zone [" + az + "], it does not offer instance type [" + instanceCfg.InstanceType + "]")
+				}
+			}
+
+			if len(available) == 0 {
+				return nil, errors.New("None of the Availability Zones [" + strings.Join(azList, ", ") + "] offer instance type [" + instanceCfg.InstanceType + "]!")
+			}
+			azList = available
+		}
+	}
+
+	return spreadAZs(p, azList, azCounts(existing), names, "Instances", class), nil
+}
+
+// sequenceOf returns the sequence of an Instance from its Sequence tag, or false if it isn't a number
+func sequenceOf(seq string) (int, bool) {
+	if seq == "" || strings.Trim(seq, "0123456789") != "" {
+		return 0, false
+	}
+
+	sequence, err := strconv.Atoi(seq)
+	if err != nil {
+		return 0, false
+	}
+
+	return sequence, true
+}
+
+// nextSequences returns count sequences following the highest one in use by the Instances of a class
+func nextSequences(existing Instances, count int) []string {
+	var highest int
+	for _, inst := range existing {
+		if sequence, ok := sequenceOf(inst.Sequence); ok && sequence > highest {
+			highest = sequence
+		}
+	}
+
+	sequences := make([]string, count)
+	for i := range sequences {
+		sequences[i] = strconv.Itoa(highest + i + 1)
+	}
+
+	return sequences
+}
+
+// getClassInstances returns the Instances tagged with a class that have not been terminated, from every region
+func getClassInstances(class string) (Instances, []error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var instList Instances
+	var errs []error
+
+	regionList, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	for _, region := range regionList {
+		wg.Add(1)
+
+		go func(region string) {
+			defer wg.Done()
+
+			svc := ec2.New(newSession(region))
+
+			result, err := svc.DescribeInstances(&ec2.DescribeInstancesInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("tag:Class"),
+						Values: []*string{aws.String(class)},
+					},
+					{
+						Name:   aws.String("instance-state-name"),
+						Values: aws.StringSlice([]string{"pending", "running", "shutting-down", "stopping", "stopped"}),
+					},
+				},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+
+			for _, reservation := range result.Reservations {
+				for _, instance := range reservation.Instances {
+					var inst Instance
+					inst.Marshal(instance, region, new(Subnets), new(Vpcs), new(Images))
+					instList = append(instList, inst)
+				}
+			}
+		}(*region.RegionName)
+	}
+	wg.Wait()
+
+	return instList, errs
+}
+
+// validateCapacity returns an error if the Spot, placement and capacity options of an Instance class don't go together
+func validateCapacity(instanceCfg config.InstanceClass) error {
+	switch instanceCfg.SpotInterruptionBehavior {
+	case "", "terminate":
+		if instanceCfg.SpotPersistent {
+			return errors.New("A persistent Spot request requires the Spot interruption behavior stop or hibernate!")
+		}
+	case "stop", "hibernate":
+		if !instanceCfg.SpotPersistent {
+			return errors.New("The Spot interruption behavior [" + instanceCfg.SpotInterruptionBehavior + "] requires a persistent Spot request!")
+		}
+	default:
+		return errors.New("Invalid Spot interruption behavior [" + instanceCfg.SpotInterruptionBehavior + "], expected terminate, stop or hibernate!")
+	}
+
+	switch instanceCfg.Tenancy {
+	case "", "default", "dedicated", "host":
+	default:
+		return errors.New("Invalid tenancy [" + instanceCfg.Tenancy + "], expected default, dedicated or host!")
+	}
+
+	switch instanceCfg.Affinity {
+	case "", "default":
+	case "host":
+		if instanceCfg.Tenancy != "host" {
+			return errors.New("Host affinity requires the host tenancy!")
+		}
+	default:
+		return errors.New("Invalid host affinity [" + instanceCfg.Affinity + "], expected default or host!")
+	}
+
+	if instanceCfg.Spot {
+		if instanceCfg.Tenancy == "host" {
+			return errors.New("Spot Instances can't be launched on Dedicated Hosts!")
+		}
+		if instanceCfg.CapacityReservation != "" && instanceCfg.CapacityReservation != "none" {
+			return errors.New("Spot Instances can't be launched into a Capacity Reservation!")
+		}
+	}
+
+	return nil
+}
+
+// instanceMarketOptions returns the Spot market options of an Instance class, or nil for On-Demand Instances
+func instanceMarketOptions(instanceCfg config.InstanceClass) *ec2.InstanceMarketOptionsRequest {
+	if !instanceCfg.Spot {
+		return nil
+	}
+
+	spotOptions := &ec2.SpotMarketOptions{
+		SpotInstanceType: aws.String("one-time"),
+	}
+	if instanceCfg.SpotPersistent {
+		spotOptions.SpotInstanceType = aws.String("persistent")
+	}
+	if instanceCfg.SpotMaxPrice != "" {
+		spotOptions.MaxPrice = aws.String(instanceCfg.SpotMaxPrice)
+	}
+	if instanceCfg.SpotInterruptionBehavior != "" {
+		spotOptions.InstanceInterruptionBehavior = aws.String(instanceCfg.SpotInterruptionBehavior)
+	}
+
+	return &ec2.InstanceMarketOptionsRequest{
+		MarketType:  aws.String("spot"),
+		SpotOptions: spotOptions,
+	}
+}
+
+// instancePlacement returns the placement of an Instance class in an Availability Zone
+func instancePlacement(instanceCfg config.InstanceClass, az string) *ec2.Placement {
+	placement := &ec2.Placement{
+		AvailabilityZone: aws.String(az),
+	}
+	if instanceCfg.PlacementGroup != "" {
+		placement.GroupName = aws.String(instanceCfg.PlacementGroup)
+	}
+	if instanceCfg.Tenancy != "" {
+		placement.Tenancy = aws.String(instanceCfg.Tenancy)
+	}
+	if instanceCfg.Affinity != "" {
+		placement.Affinity = aws.String(instanceCfg.Affinity)
+	}
+
+	return placement
+}
+
+// capacityReservation returns the Capacity Reservation targeting of an Instance class, or nil if it has none
+func capacityReservation(instanceCfg config.InstanceClass) *ec2.CapacityReservationSpecification {
+	switch instanceCfg.CapacityReservation {
+	case "":
+		return nil
+	case "open", "none":
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationPreference: aws.String(instanceCfg.CapacityReservation),
+		}
+	}
+
+	return &ec2.CapacityReservationSpecification{
+		CapacityReservationTarget: &ec2.CapacityReservationTarget{
+			CapacityReservationId: aws.String(instanceCfg.CapacityReservation),
+		},
+	}
+}
+
+// ensureKeyPair returns a KeyPair of a region, importing it from its KeyPair class if it doesn't exist yet
+func ensureKeyPair(p *progress, region, keyName string, dryRun bool) (KeyPair, error) {
+	keyPair, err := GetKeyPairByName(region, keyName)
+	if err == nil {
+		return keyPair, nil
+	}
+
+	// Try to create it?
+	p.info("Unable to find KeyPair [" + keyName + "] in [" + region + "], trying to create it...")
+
+	keypairCfg, err := config.LoadKeyPairClass(keyName)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	err = importKeyPair(region, keyName, []byte(keypairCfg.PublicKey), dryRun)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	p.change("Created public key named [" + keyName + "] in [" + region + "]!")
+
+	return GetKeyPairByName(region, keyName)
+}
+
+// TerminateInstances terminates EC2 instances based on the given search term and optional region input
+func TerminateInstances(search, region string, dryRun bool) (err error) {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	instList := new(Instances)
+
+	// Check if we were given a region or not
+	if region != "" {
+		err = GetRegionInstances(region, instList, search, true)
+	} else {
+		var errs []error
+		instList, errs = GetInstances(search, true)
+		ShowErrors(errs)
+	}
+
+	if err != nil {
+		return errors.New("Error gathering Instance list")
+	}
+
+	if len(*instList) > 0 {
+		// Print the table
+		instList.PrintTable()
+	} else {
+		return errors.New("No Instances found!")
+	}
+
+	// Confirm
+	if !prompt.Confirm("Are you sure you want to terminate these Instances?") {
+		return errors.New("Aborting!")
+	}
+
+	// Delete 'Em
+	err = terminateInstances(instList, dryRun)
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// Private function without the confirmation terminal prompts
+func terminateInstances(instList *Instances, dryRun bool) (err error) {
+
+	for _, instance := range *instList {
+		sess := newSession(instance.Region)
+		svc := ec2.New(sess)
+
+		params := &ec2.TerminateInstancesInput{
+			InstanceIds: []*string{
+				aws.String(instance.InstanceID),
+			},
+			DryRun: aws.Bool(dryRun),
+		}
+
+		_, err := svc.TerminateInstances(params)
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok {
+				return errors.New(awsErr.Message())
+			}
+			return err
+		}
+
+		terminal.Delta("Terminated Instance [" + instance.InstanceID + "] named [" + instance.Name + "] in [" + instance.AvailabilityZone + "]!")
+	}
+
+	return nil
+}
+
+// StopInstances stops an EC2 instances based on the given search term and optional region input. A third option "force" will force stop the instance(s)
+func StopInstances(search, region string, force, dryRun bool) (err error) {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	instList := new(Instances)
+
+	// Check if we were given a region or not
+	if region != "" {
+		err = GetRegionInstances(region, instList, search, false)
+	} else {
+		var errs []error
+		instList, errs = GetInstances(search, false)
+		ShowErrors(errs)
+	}
+
+	if err != nil {
 		return errors.New("Error gathering Instance list")
 	}
 
@@ -684,7 +1628,7 @@ func StopInstances(search, region string, force, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to stop these Instances?") {
+	if !prompt.Confirm("Are you sure you want to stop these Instances?") {
 		return errors.New("Aborting!")
 	}
 
@@ -704,7 +1648,7 @@ func stopInstances(instList *Instances, force, dryRun bool) (err error) {
 
 	for _, instance := range *instList {
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(instance.Region)}))
+		sess := newSession(instance.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.StopInstancesInput{
@@ -743,7 +1687,9 @@ func StartInstances(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionInstances(region, instList, search, false)
 	} else {
-		instList, _ = GetInstances(search, false)
+		var errs []error
+		instList, errs = GetInstances(search, false)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -758,7 +1704,7 @@ func StartInstances(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to start these Instances?") {
+	if !prompt.Confirm("Are you sure you want to start these Instances?") {
 		return errors.New("Aborting!")
 	}
 
@@ -778,7 +1724,7 @@ func startInstances(instList *Instances, dryRun bool) (err error) {
 
 	for _, instance := range *instList {
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(instance.Region)}))
+		sess := newSession(instance.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.StartInstancesInput{
@@ -816,7 +1762,9 @@ func RebootInstances(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionInstances(region, instList, search, true)
 	} else {
-		instList, _ = GetInstances(search, true)
+		var errs []error
+		instList, errs = GetInstances(search, true)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -831,7 +1779,7 @@ func RebootInstances(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to reboot these Instances?") {
+	if !prompt.Confirm("Are you sure you want to reboot these Instances?") {
 		return errors.New("Aborting!")
 	}
 
@@ -850,7 +1798,7 @@ func RebootInstances(search, region string, dryRun bool) (err error) {
 func rebootInstances(instList *Instances, dryRun bool) (err error) {
 	for _, instance := range *instList {
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(instance.Region)}))
+		sess := newSession(instance.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.RebootInstancesInput{
@@ -873,3 +1821,347 @@ func rebootInstances(instList *Instances, dryRun bool) (err error) {
 
 	return nil
 }
+
+// ResizeInstancesOptions are the options for ResizeInstancesWithContext
+type ResizeInstancesOptions struct {
+	Search       string
+	Region       string // optional, all regions are searched if empty
+	InstanceType string
+	UpdateClass  bool // set the instance type of the Instance classes of the resized Instances
+	DryRun       bool
+
+	// Confirm is called with the Instances before any of them are stopped, nothing is changed if it returns false
+	Confirm func(instances Instances) bool
+
+	Observer Observer
+}
+
+// ResizeInstancesResult is the result of ResizeInstancesWithContext
+type ResizeInstancesResult struct {
+	Instances Instances // the resized Instances
+	Classes   []string  // the Instance classes that were updated
+	Warnings  []string
+}
+
+// ResizeInstances changes the instance type of one or more Instances based on the given search term and optional region
+func ResizeInstances(search, instanceType, region string, updateClass, dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	opts := ResizeInstancesOptions{
+		Search:       search,
+		Region:       region,
+		InstanceType: instanceType,
+		UpdateClass:  updateClass,
+		DryRun:       dryRun,
+		Confirm: func(instances Instances) bool {
+			instances.PrintTable()
+			return prompt.Confirm("Are you sure you want to stop these Instances and resize them to [" + instanceType + "]?")
+		},
+		Observer: TerminalObserver,
+	}
+
+	result, err := ResizeInstancesWithContext(context.Background(), opts)
+	if err == ErrAborted {
+		terminal.ErrorLine("Aborting!")
+		return nil
+	}
+	if result != nil && len(result.Instances) > 0 {
+		result.Instances.PrintTable()
+	}
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// ResizeInstancesWithContext changes the instance type of Instances one at a time, without any terminal output or prompts.
+// Each Instance is deregistered from its Load Balancers (waiting for connection draining), stopped, modified, started
+// and registered again, waiting for its status checks and Load Balancer health checks to pass before the next one.
+func ResizeInstancesWithContext(ctx context.Context, opts ResizeInstancesOptions) (*ResizeInstancesResult, error) {
+	p := newProgress(opts.Observer)
+
+	instList := new(Instances)
+
+	// Check if we were given a region or not
+	if opts.Region != "" {
+		err := GetRegionInstances(opts.Region, instList, opts.Search, false)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var errs []error
+		instList, errs = GetInstances(opts.Search, false)
+		p.warnErrors(errs)
+	}
+
+	var resize Instances
+	for _, instance := range *instList {
+		switch {
+		case instance.State == "terminated" || instance.State == "shutting-down":
+			continue
+		case instance.Size == opts.InstanceType:
+			p.info("Instance [" + instance.Name + "] is already [" + opts.InstanceType + "], skipping!")
+			continue
+		}
+
+		err := validateInstanceType(ctx, instance, opts.InstanceType)
+		if err != nil {
+			return nil, err
+		}
+
+		// AutoScale Groups replace the Instances that fail their health checks while they are stopped
+		asgName, err := getInstanceAutoScaleGroupName(ctx, instance.Region, instance.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if asgName != "" {
+			p.warn("Instance [" + instance.Name + "] is in AutoScale Group [" + asgName + "], resize it through its Launch Configuration instead, skipping!")
+			continue
+		}
+
+		resize = append(resize, instance)
+	}
+
+	if len(resize) == 0 {
+		return nil, errors.New("No Instances found to resize, Aborting!")
+	}
+
+	if opts.Confirm != nil && !opts.Confirm(resize) {
+		return nil, ErrAborted
+	}
+
+	result := new(ResizeInstancesResult)
+
+	for _, instance := range resize {
+		err := resizeInstance(ctx, p, instance, opts.InstanceType, opts.DryRun)
+		if err != nil {
+			result.Warnings = p.warnings
+			return result, errors.New("Unable to resize Instance [" + instance.Name + "]: " + err.Error())
+		}
+
+		instance.Size = opts.InstanceType
+		result.Instances = append(result.Instances, instance)
+	}
+
+	// Instance Classes
+	if opts.UpdateClass {
+		updated := make(map[string]bool)
+		for _, instance := range result.Instances {
+			if instance.Class == "" || updated[instance.Class] {
+				continue
+			}
+			updated[instance.Class] = true
+
+			instanceCfg, err := config.LoadInstanceClass(instance.Class)
+			if err != nil {
+				p.warn("Unable to load Instance class [" + instance.Class + "]: " + err.Error())
+				continue
+			}
+
+			if opts.DryRun {
+				p.notice("Would set the instance type of Instance class [" + instance.Class + "] to [" + opts.InstanceType + "]")
+				continue
+			}
+
+			err = instanceCfg.SetInstanceType(instance.Class, opts.InstanceType)
+			if err != nil {
+				p.warn("Unable to update Instance class [" + instance.Class + "]: " + err.Error())
+				continue
+			}
+
+			p.change("Set the instance type of Instance class [" + instance.Class + "] to [" + opts.InstanceType + "]")
+			result.Classes = append(result.Classes, instance.Class)
+		}
+	}
+
+	result.Warnings = p.warnings
+
+	return result, nil
+}
+
+// validateInstanceType returns an error if an Instance can't be resized to an instance type
+func validateInstanceType(ctx context.Context, instance Instance, instanceType string) error {
+	if instance.Root != "ebs" {
+		return errors.New("Instance [" + instance.Name + "] has an instance store root device, it can't be stopped to be resized!")
+	}
+
+	svc := ec2.New(newSession(instance.Region))
+
+	resp, err := svc.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return errors.New("Instance type [" + instanceType + "] is Invalid: " + awsErr.Message())
+		}
+		return err
+	}
+	if len(resp.InstanceTypes) == 0 {
+		return errors.New("Instance type [" + instanceType + "] is not available in [" + instance.Region + "]!")
+	}
+
+	info := resp.InstanceTypes[0]
+
+	if !containsString(aws.StringValueSlice(info.SupportedVirtualizationTypes), instance.Virtualization) {
+		return errors.New("Instance type [" + instanceType + "] doesn't support the [" + instance.Virtualization + "] virtualization of Instance [" + instance.Name + "]!")
+	}
+
+	if instance.Architecture != "" && info.ProcessorInfo != nil && !containsString(aws.StringValueSlice(info.ProcessorInfo.SupportedArchitectures), instance.Architecture) {
+		return errors.New("Instance type [" + instanceType + "] doesn't support the [" + instance.Architecture + "] architecture of Instance [" + instance.Name + "]!")
+	}
+
+	if instance.EbsOptimized && info.EbsInfo != nil && aws.StringValue(info.EbsInfo.EbsOptimizedSupport) == "unsupported" {
+		return errors.New("Instance [" + instance.Name + "] is EBS Optimized, which instance type [" + instanceType + "] doesn't support!")
+	}
+
+	if !instance.EnaSupport && info.NetworkInfo != nil && aws.StringValue(info.NetworkInfo.EnaSupport) == "required" {
+		return errors.New("Instance type [" + instanceType + "] requires ENA, which Instance [" + instance.Name + "] doesn't have enabled!")
+	}
+
+	return nil
+}
+
+// resizeInstance stops an Instance, changes its instance type and starts it again if it was running, restoring it on failure
+func resizeInstance(ctx context.Context, p *progress, instance Instance, instanceType string, dryRun bool) error {
+	running := instance.State == "running" || instance.State == "pending"
+
+	if dryRun {
+		p.notice("Would resize Instance [" + instance.Name + "] from [" + instance.Size + "] to [" + instanceType + "]")
+		return nil
+	}
+
+	svc := ec2.New(newSession(instance.Region))
+	instanceIds := []*string{aws.String(instance.InstanceID)}
+
+	// register registers the Instance with Load Balancers and Target Groups, waiting for it to be healthy in each
+	register := func(lbNames []string, targets []instanceTarget) error {
+		for _, lbName := range lbNames {
+			p.change("Registering Instance [" + instance.Name + "] with Load Balancer [" + lbName + "], waiting for it to be healthy...")
+			err := registerInstance(ctx, instance.Region, lbName, instance.InstanceID)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, target := range targets {
+			p.change("Registering Instance [" + instance.Name + "] with Target Group [" + target.targetGroupName + "], waiting for it to be healthy...")
+			err := registerTarget(ctx, instance.Region, target)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Load Balancers and the target groups of Application and Network Load Balancers
+	var lbNames []string
+	var targets []instanceTarget
+	if running {
+		var err error
+		lbNames, err = getInstanceLoadBalancerNames(ctx, instance.Region, instance.InstanceID)
+		if err != nil {
+			return err
+		}
+
+		targets, err = getInstanceTargets(ctx, instance.Region, instance.InstanceID)
+		if err != nil {
+			return err
+		}
+
+		for i, lbName := range lbNames {
+			p.change("Deregistering Instance [" + instance.Name + "] from Load Balancer [" + lbName + "], waiting for connection draining...")
+			err := deregisterInstance(ctx, instance.Region, lbName, instance.InstanceID)
+			if err != nil {
+				if regErr := register(lbNames[:i], nil); regErr != nil {
+					p.warn("Unable to restore Instance [" + instance.Name + "]: " + regErr.Error())
+				}
+				return err
+			}
+		}
+
+		for i, target := range targets {
+			p.change("Deregistering Instance [" + instance.Name + "] from Target Group [" + target.targetGroupName + "], waiting for connection draining...")
+			err := deregisterTarget(ctx, instance.Region, target)
+			if err != nil {
+				if regErr := register(lbNames, targets[:i]); regErr != nil {
+					p.warn("Unable to restore Instance [" + instance.Name + "]: " + regErr.Error())
+				}
+				return err
+			}
+		}
+	}
+
+	// start starts the Instance again, waits for its status checks, and registers it with its Load Balancers and Target Groups
+	start := func() error {
+		p.change("Starting Instance [" + instance.Name + "]...")
+		_, err := svc.StartInstancesWithContext(ctx, &ec2.StartInstancesInput{InstanceIds: instanceIds})
+		if err != nil {
+			return err
+		}
+
+		p.notice("Waiting for Instance [" + instance.Name + "] to pass its status checks...")
+		err = svc.WaitUntilInstanceStatusOkWithContext(ctx, &ec2.DescribeInstanceStatusInput{InstanceIds: instanceIds})
+		if err != nil {
+			return err
+		}
+
+		return register(lbNames, targets)
+	}
+
+	// Stop
+	if running {
+		p.change("Stopping Instance [" + instance.Name + "]...")
+		_, err := svc.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{InstanceIds: instanceIds})
+		if err == nil {
+			err = svc.WaitUntilInstanceStoppedWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIds})
+		}
+		if err != nil {
+			if startErr := start(); startErr != nil {
+				p.warn("Unable to restore Instance [" + instance.Name + "]: " + startErr.Error())
+			}
+			return err
+		}
+	}
+
+	// Resize
+	_, err := svc.ModifyInstanceAttributeWithContext(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(instance.InstanceID),
+		InstanceType: &ec2.AttributeValue{Value: aws.String(instanceType)},
+	})
+	if err != nil {
+		if running {
+			if startErr := start(); startErr != nil {
+				p.warn("Unable to restore Instance [" + instance.Name + "]: " + startErr.Error())
+			}
+		}
+		if awsErr, ok := err.(awserr.Error); ok {
+			return errors.New(awsErr.Message())
+		}
+		return err
+	}
+
+	p.change("Resized Instance [" + instance.Name + "] from [" + instance.Size + "] to [" + instanceType + "]!")
+
+	if running {
+		return start()
+	}
+
+	return nil
+}
+
+// containsString returns true if a slice of strings contains a string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
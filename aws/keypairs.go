@@ -2,20 +2,18 @@ package aws
 
 import (
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"reflect"
 	"regexp"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -33,7 +31,7 @@ func GetKeyPairByName(region, name string) (KeyPair, error) {
 		return KeyPair{}, errors.New("No KeyName provided!")
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeKeyPairsInput{
@@ -63,26 +61,11 @@ func GetKeyPairByName(region, name string) (KeyPair, error) {
 
 // GetKeyPairs returns a slice of KeyPairs that match the provided search term
 func GetKeyPairs(search string) (*KeyPairs, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	keyList := new(KeyPairs)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionKeyPairs(*region.RegionName, keyList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering key pair list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-
-	wg.Wait()
+	errs := collectAccountRegions("key pair", func(account Account, region string) error {
+		return getAccountRegionKeyPairs(account, region, keyList, search)
+	})
 
 	return keyList, errs
 }
@@ -96,8 +79,13 @@ func (k *KeyPair) Marshal(keyPair *ec2.KeyPairInfo, region string) {
 
 // GetRegionKeyPairs returns a list of KeyPairs for a given region into the provided KeyPairs slice
 func GetRegionKeyPairs(region string, keyList *KeyPairs, search string) error {
+	return getAccountRegionKeyPairs(CurrentAccount(), region, keyList, search)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionKeyPairs returns a list of KeyPairs for a given region of an account into the provided KeyPairs slice
+func getAccountRegionKeyPairs(account Account, region string, keyList *KeyPairs, search string) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{})
@@ -108,6 +96,7 @@ func GetRegionKeyPairs(region string, keyList *KeyPairs, search string) error {
 	key := make(KeyPairs, len(result.KeyPairs))
 	for i, keyPair := range result.KeyPairs {
 		key[i].Marshal(keyPair, region)
+		key[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -178,12 +167,15 @@ func CreateKeyPair(class, region string, dryRun bool) error {
 		return err
 	}
 
+	terminal.Delta("Created public key named [" + class + "] in [" + region + "]!")
+
 	return nil
 }
 
+// private function without terminal output
 func importKeyPair(region, name string, publicKey []byte, dryRun bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.ImportKeyPairInput{
@@ -197,8 +189,6 @@ func importKeyPair(region, name string, publicKey []byte, dryRun bool) error {
 		return err
 	}
 
-	terminal.Delta("Created public key named [" + name + "] in [" + region + "]!")
-
 	return nil
 }
 
@@ -211,6 +201,7 @@ func DeleteKeyPairs(name string, dryRun bool) error {
 
 	keyList, err := GetKeyPairs(name)
 	if err != nil {
+		ShowErrors(err)
 		terminal.ErrorLine("Error gathering KeyPair list")
 		return nil
 	}
@@ -224,14 +215,14 @@ func DeleteKeyPairs(name string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these KeyPairs?") {
+	if !prompt.Confirm("Are you sure you want to delete these KeyPairs?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
 
 	// Delete 'Em
 	for _, key := range *keyList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(key.Region)}))
+		sess := newSession(key.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteKeyPairInput{
@@ -263,6 +254,13 @@ func InstallKeyPair(class string, dryRun bool) error {
 
 	terminal.Information("Found KeyPair class configuration for [" + class + "]!")
 
+	// Decrypt the private key
+	opened, err := config.OpenSecrets(keypairCfg)
+	if err != nil {
+		return err
+	}
+	keypairCfg = opened.(config.KeyPairClass)
+
 	if !dryRun {
 
 		currentUser, _ := user.Current()
@@ -272,7 +270,7 @@ func InstallKeyPair(class string, dryRun bool) error {
 		publicKeyPath := sshLocation + class + ".pub"
 
 		// Private Key
-		privateKey := []byte(keypairCfg.PrivateKey1 + keypairCfg.PrivateKey2 + keypairCfg.PrivateKey3 + keypairCfg.PrivateKey4)
+		privateKey := []byte(keypairCfg.PrivateKey)
 
 		if _, err := os.Stat(privateKeyPath); !os.IsNotExist(err) {
 			terminal.ErrorLine("Local private key named [" + class + "] already exists!")
@@ -13,7 +13,6 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	humanize "github.com/dustin/go-humanize"
@@ -22,6 +21,7 @@ import (
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -62,7 +62,7 @@ func GetLaunchConfigurationVersion(class string, previous bool) (int, error) {
 // GetLaunchConfigurationsByName returns a slice of Launch Configurations for a given region and name
 func GetLaunchConfigurationsByName(region, name string) (LaunchConfigs, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := autoscaling.New(sess)
 
 	params := &autoscaling.DescribeLaunchConfigurationsInput{
@@ -95,35 +95,26 @@ func GetLaunchConfigurationsByName(region, name string) (LaunchConfigs, error) {
 
 // GetLaunchConfigurations returns a slice of Launch Configurations that match the provided search term
 func GetLaunchConfigurations(search string) (*LaunchConfigs, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	lcList := new(LaunchConfigs)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionLaunchConfigurations(*region.RegionName, lcList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering launch config list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("launch config", func(account Account, region string) error {
+		return getAccountRegionLaunchConfigurations(account, region, lcList, search)
+	})
 
 	return lcList, errs
 }
 
 // GetRegionLaunchConfigurations returns a slice of Launch Configurations into the provided LaunchConfigs slice that match the region and search term
 func GetRegionLaunchConfigurations(region string, lcList *LaunchConfigs, search string) error {
+	return getAccountRegionLaunchConfigurations(CurrentAccount(), region, lcList, search)
+}
+
+// getAccountRegionLaunchConfigurations returns a slice of Launch Configurations into the provided LaunchConfigs slice that match the region of an account and search term
+func getAccountRegionLaunchConfigurations(account Account, region string, lcList *LaunchConfigs, search string) error {
 
 	var launchConfigurations []*autoscaling.LaunchConfiguration
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := autoscaling.New(sess)
 
 	params := &autoscaling.DescribeLaunchConfigurationsInput{}
@@ -146,17 +137,18 @@ func GetRegionLaunchConfigurations(region string, lcList *LaunchConfigs, search
 	}
 
 	secGrpList := new(SecurityGroups)
-	err := GetRegionSecurityGroups(region, secGrpList, "")
+	err := getAccountRegionSecurityGroups(account, region, secGrpList, "")
 	if err != nil {
 		return nil
 	}
 
 	imgList := new(Images)
-	GetRegionImages(region, imgList, "", false)
+	getAccountRegionImages(account, region, imgList, "", false)
 
 	lc := make(LaunchConfigs, len(launchConfigurations))
 	for i, config := range launchConfigurations {
 		lc[i].Marshal(config, region, secGrpList, imgList)
+		lc[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -265,9 +257,7 @@ func CreateLaunchConfigurations(class string, dryRun bool) (err error) {
 		},
 		InstanceType: aws.String(instanceCfg.InstanceType),
 		//KernelId:         aws.String("XmlStringMaxLen255"),
-		//PlacementTenancy: aws.String("XmlStringMaxLen64"),
 		//RamdiskId:        aws.String("XmlStringMaxLen255"),
-		//SpotPrice: aws.String("SpotPrice"),
 		//ClassicLinkVPCId:         aws.String("XmlStringMaxLen255"),
 		//ClassicLinkVPCSecurityGroups: []*string{
 		//aws.String("XmlStringMaxLen255"),
@@ -286,8 +276,49 @@ func CreateLaunchConfigurations(class string, dryRun bool) (err error) {
 
 	}
 
+	// Spot / Tenancy
+	err = validateCapacity(instanceCfg)
+	if err != nil {
+		return err
+	}
+
+	if instanceCfg.Spot {
+		if instanceCfg.SpotMaxPrice == "" {
+			return errors.New("Launch Configurations need a Spot Max Price to launch Spot Instances!")
+		}
+		if instanceCfg.SpotPersistent || instanceCfg.SpotInterruptionBehavior != "" {
+			terminal.Notice("Launch Configurations only launch one-time Spot Instances that terminate when interrupted, ignoring the Spot request type and interruption behavior")
+		}
+
+		terminal.Information("Launching as Spot Instances with a max price of [" + instanceCfg.SpotMaxPrice + "]")
+		params.SpotPrice = aws.String(instanceCfg.SpotMaxPrice)
+	}
+
+	switch instanceCfg.Tenancy {
+	case "host":
+		return errors.New("Launch Configurations can't launch Instances on Dedicated Hosts!")
+	case "default", "dedicated":
+		params.PlacementTenancy = aws.String(instanceCfg.Tenancy)
+	}
+
+	if instanceCfg.CapacityReservation != "" {
+		terminal.Notice("Launch Configurations can't target Capacity Reservations, ignoring [" + instanceCfg.CapacityReservation + "]")
+	}
+
+	// Instance Metadata Service
+	err = validateMetadataOptions(instanceCfg)
+	if err != nil {
+		return err
+	}
+	params.MetadataOptions = launchConfigurationMetadataOptions(instanceCfg)
+
 	for _, region := range cfg.Regions {
 
+		if !regions.Selected(region) {
+			terminal.Notice("Region [" + region + "] is not selected, skipping!")
+			continue
+		}
+
 		if !regions.ValidRegion(region) {
 			return errors.New("Region [" + region + "] is not valid!")
 		} else {
@@ -443,7 +474,7 @@ func CreateLaunchConfigurations(class string, dryRun bool) (err error) {
 			terminal.Notice("User Data:")
 			terminal.Notice(parsedUserData)
 		} else {
-			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+			sess := newSession(region)
 			svc := autoscaling.New(sess)
 
 			_, err = svc.CreateLaunchConfiguration(params)
@@ -479,11 +510,15 @@ func RotateLaunchConfigurations(class string, cfg config.LaunchConfigurationClas
 
 	autoScaleGroups, err := GetAutoScaleGroups(class)
 	if err != nil {
+		ShowErrors(err)
 		return errors.New("Error while retrieving the list of launch configurations to exclude from rotation!")
 	}
 	excludedConfigs := autoScaleGroups.LockedLaunchConfigurations()
 
-	regions := GetRegionListWithoutIgnored()
+	regions, regionErr := GetRegionListWithoutIgnored()
+	if regionErr != nil {
+		return regionErr
+	}
 
 	for _, region := range regions {
 		wg.Add(1)
@@ -558,7 +593,9 @@ func DeleteLaunchConfigurations(search, region string, dryRun bool) (err error)
 	if region != "" {
 		err = GetRegionLaunchConfigurations(region, lcList, search)
 	} else {
-		lcList, _ = GetLaunchConfigurations(search)
+		var errs []error
+		lcList, errs = GetLaunchConfigurations(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -573,7 +610,7 @@ func DeleteLaunchConfigurations(search, region string, dryRun bool) (err error)
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Launch Configurations?") {
+	if !prompt.Confirm("Are you sure you want to delete these Launch Configurations?") {
 		return errors.New("Aborting!")
 	}
 
@@ -594,7 +631,7 @@ func DeleteLaunchConfigurations(search, region string, dryRun bool) (err error)
 // Private function without the confirmation terminal prompts
 func deleteLaunchConfigurations(lcList *LaunchConfigs, dryRun bool) (err error) {
 	for _, lc := range *lcList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lc.Region)}))
+		sess := newSession(lc.Region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.DeleteLaunchConfigurationInput{
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,18 +9,16 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"sync"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/mitchellh/hashstructure"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -32,33 +31,24 @@ type LoadBalancer models.LoadBalancer
 
 // GetLoadBalancers returns a slice of AWS Load Balancers
 func GetLoadBalancers(search string) (*LoadBalancers, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	lbList := new(LoadBalancers)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionLoadBalancers(*region.RegionName, lbList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering loadbalancer list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("loadbalancer", func(account Account, region string) error {
+		return getAccountRegionLoadBalancers(account, region, lbList, search)
+	})
 
 	return lbList, errs
 }
 
 // GetRegionLoadBalancers returns a list of Load Balancers in a region into the provided LoadBalancers slice
 func GetRegionLoadBalancers(region string, lbList *LoadBalancers, search string) error {
+	return getAccountRegionLoadBalancers(CurrentAccount(), region, lbList, search)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionLoadBalancers returns a list of Load Balancers in a region of an account into the provided LoadBalancers slice
+func getAccountRegionLoadBalancers(account Account, region string, lbList *LoadBalancers, search string) error {
+
+	sess := account.Session(region)
 	svc := elb.New(sess)
 
 	result, err := svc.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
@@ -70,9 +60,9 @@ func GetRegionLoadBalancers(region string, lbList *LoadBalancers, search string)
 	secGrpList := new(SecurityGroups)
 	vpcList := new(Vpcs)
 	subList := new(Subnets)
-	GetRegionSecurityGroups(region, secGrpList, "")
-	GetRegionVpcs(region, vpcList, "")
-	GetRegionSubnets(region, subList, "")
+	getAccountRegionSecurityGroups(account, region, secGrpList, "")
+	getAccountRegionVpcs(account, region, vpcList, "")
+	getAccountRegionSubnets(account, region, subList, "")
 
 	// Get the tags all at once, to save time
 	elbNames := []string{}
@@ -80,7 +70,7 @@ func GetRegionLoadBalancers(region string, lbList *LoadBalancers, search string)
 		elbNames = append(elbNames, aws.StringValue(lb.LoadBalancerName))
 	}
 
-	elbTags, err := GetLoadBalancerTags(elbNames, region)
+	elbTags, err := getAccountLoadBalancerTags(account, elbNames, region)
 	if err != nil {
 		return err
 	}
@@ -88,6 +78,7 @@ func GetRegionLoadBalancers(region string, lbList *LoadBalancers, search string)
 	lb := make(LoadBalancers, len(result.LoadBalancerDescriptions))
 	for i, balancer := range result.LoadBalancerDescriptions {
 		lb[i].Marshal(balancer, region, secGrpList, vpcList, subList, elbTags)
+		lb[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -115,7 +106,7 @@ func GetRegionLoadBalancers(region string, lbList *LoadBalancers, search string)
 // GetLoadBalancerByName returns a single Load Balancer given the provided region and name
 func GetLoadBalancerByName(region, name string) (LoadBalancer, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := elb.New(sess)
 
 	params := &elb.DescribeLoadBalancersInput{
@@ -150,7 +141,69 @@ func GetLoadBalancerByName(region, name string) (LoadBalancer, error) {
 	return LoadBalancer{}, errors.New("Found more than one Load Balancer named [" + name + "] in [" + region + "]!")
 }
 
+// getInstanceLoadBalancerNames returns the names of the Load Balancers that an Instance is registered with
+func getInstanceLoadBalancerNames(ctx context.Context, region, instanceID string) ([]string, error) {
+	svc := elb.New(newSession(region))
+
+	var names []string
+	err := svc.DescribeLoadBalancersPagesWithContext(ctx, &elb.DescribeLoadBalancersInput{}, func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+		for _, balancer := range page.LoadBalancerDescriptions {
+			for _, instance := range balancer.Instances {
+				if aws.StringValue(instance.InstanceId) == instanceID {
+					names = append(names, aws.StringValue(balancer.LoadBalancerName))
+				}
+			}
+		}
+		return true
+	})
+
+	return names, err
+}
+
+// deregisterInstance deregisters an Instance from a Load Balancer, and waits for its connection draining to finish
+func deregisterInstance(ctx context.Context, region, name, instanceID string) error {
+	svc := elb.New(newSession(region))
+	instances := []*elb.Instance{{InstanceId: aws.String(instanceID)}}
+
+	_, err := svc.DeregisterInstancesFromLoadBalancerWithContext(ctx, &elb.DeregisterInstancesFromLoadBalancerInput{
+		LoadBalancerName: aws.String(name),
+		Instances:        instances,
+	})
+	if err != nil {
+		return err
+	}
+
+	return svc.WaitUntilInstanceDeregisteredWithContext(ctx, &elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String(name),
+		Instances:        instances,
+	})
+}
+
+// registerInstance registers an Instance with a Load Balancer, and waits for it to pass the health check
+func registerInstance(ctx context.Context, region, name, instanceID string) error {
+	svc := elb.New(newSession(region))
+	instances := []*elb.Instance{{InstanceId: aws.String(instanceID)}}
+
+	_, err := svc.RegisterInstancesWithLoadBalancerWithContext(ctx, &elb.RegisterInstancesWithLoadBalancerInput{
+		LoadBalancerName: aws.String(name),
+		Instances:        instances,
+	})
+	if err != nil {
+		return err
+	}
+
+	return svc.WaitUntilInstanceInServiceWithContext(ctx, &elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String(name),
+		Instances:        instances,
+	})
+}
+
 func GetLoadBalancerTags(names []string, region string) (map[string][]*elb.Tag, error) {
+	return getAccountLoadBalancerTags(CurrentAccount(), names, region)
+}
+
+// getAccountLoadBalancerTags returns the tags of Load Balancers in a region of an account
+func getAccountLoadBalancerTags(account Account, names []string, region string) (map[string][]*elb.Tag, error) {
 
 	elbTags := make(map[string][]*elb.Tag)
 
@@ -158,7 +211,7 @@ func GetLoadBalancerTags(names []string, region string) (map[string][]*elb.Tag,
 		return elbTags, nil
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := elb.New(sess)
 
 	params := &elb.DescribeTagsInput{
@@ -199,7 +252,7 @@ func (l *LoadBalancer) Marshal(balancer *elb.LoadBalancerDescription, region str
 	subnetClassesSorted.Sort()
 
 	// attributes
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := elb.New(sess)
 	params := &elb.DescribeLoadBalancerAttributesInput{
 		LoadBalancerName: balancer.LoadBalancerName,
@@ -235,6 +288,7 @@ func (l *LoadBalancer) Marshal(balancer *elb.LoadBalancerDescription, region str
 	l.AvailabilityZones = aws.StringValueSlice(balancer.AvailabilityZones)
 	l.Region = region
 	l.Class = GetTagValue("Class", tags[l.Name])
+	l.Tags = GetTagMap(tags[l.Name])
 
 	// Get the listeners
 	for _, listenerDesc := range balancer.ListenerDescriptions {
@@ -341,23 +395,17 @@ func CreateLoadBalancer(class, region string, dryRun bool) error {
 		}
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := elb.New(sess)
 
 	params := &elb.CreateLoadBalancerInput{
 		LoadBalancerName: aws.String(class),
 		Scheme:           aws.String(elbCfg.Scheme),
 
-		Tags: []*elb.Tag{
-			{
-				Key:   aws.String("Name"),
-				Value: aws.String(class),
-			},
-			{
-				Key:   aws.String("Class"),
-				Value: aws.String(class),
-			},
-		},
+		Tags: elbTags(mergeTags(elbCfg.Tags, map[string]string{
+			"Name":  class,
+			"Class": class,
+		})),
 	}
 
 	// Add Security Groups
@@ -371,10 +419,12 @@ func CreateLoadBalancer(class, region string, dryRun bool) error {
 	} else {
 		// Add Availability Zones that are in this region
 		azs := []*string{}
-		regionAzs := new(regions.AZs)
-		regions.GetRegionAZs(region, regionAzs)
+		allAzs, errs := regions.GetAZs()
+		if len(errs) > 0 {
+			return errs[0]
+		}
 		for _, az := range elbCfg.AvailabilityZones {
-			if regionAzs.ValidAZ(az) {
+			if allAzs.GetRegion(az) == region {
 				terminal.Delta(fmt.Sprintf("[%s %s] - Enable -	[Load Balancer Availability Zones] [%s]", class, region, az))
 
 				azs = append(azs, aws.String(az))
@@ -443,7 +493,9 @@ func UpdateLoadBalancers(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionLoadBalancers(region, lbList, search)
 	} else {
-		lbList, _ = GetLoadBalancers(search)
+		var errs []error
+		lbList, errs = GetLoadBalancers(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -468,7 +520,7 @@ func UpdateLoadBalancers(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to update these Load Balancers?") {
+	if !prompt.Confirm("Are you sure you want to update these Load Balancers?") {
 		return errors.New("Aborting!")
 	}
 
@@ -515,6 +567,14 @@ func updateLoadBalancers(changes []LoadBalancerChange, dryRun bool) error {
 				}
 			}
 
+			// Tags
+			if len(change.Tags) > 0 {
+				err := addLoadBalancerTags(change.LoadBalancer, change.Tags)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Health Check
 			if change.HealthCheck != (config.LoadBalancerHealthCheck{}) {
 				err := configureHealthCheck(change.LoadBalancer, change.HealthCheck)
@@ -604,7 +664,7 @@ func modifyAttributes(lb LoadBalancer, attributes config.LoadBalancerAttributes)
 		})
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.ModifyLoadBalancerAttributes(params)
@@ -618,6 +678,27 @@ func modifyAttributes(lb LoadBalancer, attributes config.LoadBalancerAttributes)
 	return nil
 }
 
+func addLoadBalancerTags(lb LoadBalancer, tags map[string]string) error {
+
+	params := &elb.AddTagsInput{
+		LoadBalancerNames: []*string{aws.String(lb.Name)},
+		Tags:              elbTags(tags),
+	}
+
+	sess := newSession(lb.Region)
+	svc := elb.New(sess)
+
+	_, err := svc.AddTags(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return errors.New(awsErr.Message())
+		}
+		return err
+	}
+
+	return nil
+}
+
 func applySecurityGroups(lb LoadBalancer, securityGroupIds []string) error {
 
 	params := &elb.ApplySecurityGroupsToLoadBalancerInput{
@@ -625,7 +706,7 @@ func applySecurityGroups(lb LoadBalancer, securityGroupIds []string) error {
 		SecurityGroups:   aws.StringSlice(securityGroupIds),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.ApplySecurityGroupsToLoadBalancer(params)
@@ -646,7 +727,7 @@ func attachSubnets(lb LoadBalancer, subnetIds []string) error {
 		Subnets:          aws.StringSlice(subnetIds),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.AttachLoadBalancerToSubnets(params)
@@ -667,7 +748,7 @@ func detachSubnets(lb LoadBalancer, subnetIds []string) error {
 		Subnets:          aws.StringSlice(subnetIds),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.DetachLoadBalancerFromSubnets(params)
@@ -688,7 +769,7 @@ func disableAvailabilityZone(lb LoadBalancer, azs []string) error {
 		LoadBalancerName:  aws.String(lb.Name),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.DisableAvailabilityZonesForLoadBalancer(params)
@@ -709,7 +790,7 @@ func enableAvailabilityZones(lb LoadBalancer, azs []string) error {
 		LoadBalancerName:  aws.String(lb.Name),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.EnableAvailabilityZonesForLoadBalancer(params)
@@ -736,7 +817,7 @@ func configureHealthCheck(lb LoadBalancer, healthcheck config.LoadBalancerHealth
 		LoadBalancerName: aws.String(lb.Name),
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.ConfigureHealthCheck(params)
@@ -777,7 +858,7 @@ func addListener(lb LoadBalancer, listeners []config.LoadBalancerListener) error
 
 	params.SetListeners(elbListeners)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.CreateLoadBalancerListeners(params)
@@ -810,7 +891,7 @@ func removeListener(lb LoadBalancer, listeners []config.LoadBalancerListener) er
 
 	params.SetLoadBalancerPorts(elbPorts)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+	sess := newSession(lb.Region)
 	svc := elb.New(sess)
 
 	_, err := svc.DeleteLoadBalancerListeners(params)
@@ -835,6 +916,7 @@ type LoadBalancerChange struct {
 	AvailabilityZones []string
 	Disable           bool
 	Detach            bool
+	Tags              map[string]string // the custom tags of the class that the Load Balancer is missing
 }
 
 func (s LoadBalancers) Diff() ([]LoadBalancerChange, error) {
@@ -876,6 +958,17 @@ func (s LoadBalancers) Diff() ([]LoadBalancerChange, error) {
 			})
 		}
 
+		/////////////////
+		// TAGS
+
+		if tags := missingTags(lb.Tags, cfg.Tags); len(tags) > 0 {
+			terminal.Delta(fmt.Sprintf("[%s %s] - Tag -	%s", lb.Name, lb.Region, formatTags(tags)))
+			changes = append(changes, LoadBalancerChange{
+				Tags:         tags,
+				LoadBalancer: lb,
+			})
+		}
+
 		/////////////////
 		// HEALTH CHECK
 
@@ -1099,7 +1192,9 @@ func DeleteLoadBalancers(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionLoadBalancers(region, elbList, search)
 	} else {
-		elbList, _ = GetLoadBalancers(search)
+		var errs []error
+		elbList, errs = GetLoadBalancers(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -1114,7 +1209,7 @@ func DeleteLoadBalancers(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Load Balancers?") {
+	if !prompt.Confirm("Are you sure you want to delete these Load Balancers?") {
 		return errors.New("Aborting!")
 	}
 
@@ -1137,7 +1232,7 @@ func DeleteLoadBalancers(search, region string, dryRun bool) (err error) {
 // Private function without the confirmation terminal prompts
 func deleteLoadBalancers(elbList *LoadBalancers) (err error) {
 	for _, lb := range *elbList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(lb.Region)}))
+		sess := newSession(lb.Region)
 		svc := elb.New(sess)
 
 		params := &elb.DeleteLoadBalancerInput{
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -8,9 +9,9 @@ import (
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/murdinc/awsm/logger"
 	"github.com/murdinc/awsm/models"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
@@ -28,7 +29,10 @@ func GetLoadBalancersV2() (*LoadBalancersV2, []error) {
 	var errs []error
 
 	lbList := new(LoadBalancersV2)
-	regions := GetRegionListWithoutIgnored()
+	regions, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return lbList, []error{err}
+	}
 
 	for _, region := range regions {
 		wg.Add(1)
@@ -50,19 +54,21 @@ func GetLoadBalancersV2() (*LoadBalancersV2, []error) {
 // GetRegionLoadBalancersV2 returns a slice of Application Load Balancers in the region into the provided LoadBalancersV2 slice
 func GetRegionLoadBalancersV2(region string, lbList *LoadBalancersV2) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := elbv2.New(sess)
 
 	result, err := svc.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{})
-
-	fmt.Println(result)
-
 	if err != nil {
 		return err
 	}
 
+	logger.Debug("Described the Application Load Balancers", "region", region, "count", len(result.LoadBalancers))
+
 	secGrpList := new(SecurityGroups)
 	err = GetRegionSecurityGroups(region, secGrpList, "")
+	if err != nil {
+		logger.Warn("Unable to get the Security Groups of the Application Load Balancers", "region", region, "error", err)
+	}
 
 	lb := make(LoadBalancersV2, len(result.LoadBalancers))
 	for i, balancer := range result.LoadBalancers {
@@ -95,6 +101,89 @@ func (l *LoadBalancerV2) Marshal(balancer *elbv2.LoadBalancer, region string, se
 
 }
 
+// instanceTarget is an Instance registered with a target group of an Application or Network Load Balancer
+type instanceTarget struct {
+	targetGroupName string
+	targetGroupArn  string
+	target          *elbv2.TargetDescription
+}
+
+// getInstanceTargets returns the target groups that an Instance is registered with, along with the port it is registered on
+func getInstanceTargets(ctx context.Context, region, instanceID string) ([]instanceTarget, error) {
+	svc := elbv2.New(newSession(region))
+
+	var targetGroups []*elbv2.TargetGroup
+	err := svc.DescribeTargetGroupsPagesWithContext(ctx, &elbv2.DescribeTargetGroupsInput{}, func(page *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+		for _, targetGroup := range page.TargetGroups {
+			if aws.StringValue(targetGroup.TargetType) == elbv2.TargetTypeEnumInstance {
+				targetGroups = append(targetGroups, targetGroup)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []instanceTarget
+	for _, targetGroup := range targetGroups {
+		resp, err := svc.DescribeTargetHealthWithContext(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: targetGroup.TargetGroupArn})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, health := range resp.TargetHealthDescriptions {
+			if health.Target != nil && aws.StringValue(health.Target.Id) == instanceID {
+				targets = append(targets, instanceTarget{
+					targetGroupName: aws.StringValue(targetGroup.TargetGroupName),
+					targetGroupArn:  aws.StringValue(targetGroup.TargetGroupArn),
+					target:          &elbv2.TargetDescription{Id: health.Target.Id, Port: health.Target.Port},
+				})
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// deregisterTarget deregisters an Instance from a target group, and waits for its deregistration delay to finish
+func deregisterTarget(ctx context.Context, region string, target instanceTarget) error {
+	svc := elbv2.New(newSession(region))
+	targets := []*elbv2.TargetDescription{target.target}
+
+	_, err := svc.DeregisterTargetsWithContext(ctx, &elbv2.DeregisterTargetsInput{
+		TargetGroupArn: aws.String(target.targetGroupArn),
+		Targets:        targets,
+	})
+	if err != nil {
+		return err
+	}
+
+	return svc.WaitUntilTargetDeregisteredWithContext(ctx, &elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(target.targetGroupArn),
+		Targets:        targets,
+	})
+}
+
+// registerTarget registers an Instance with a target group again, and waits for it to pass the health check
+func registerTarget(ctx context.Context, region string, target instanceTarget) error {
+	svc := elbv2.New(newSession(region))
+	targets := []*elbv2.TargetDescription{target.target}
+
+	_, err := svc.RegisterTargetsWithContext(ctx, &elbv2.RegisterTargetsInput{
+		TargetGroupArn: aws.String(target.targetGroupArn),
+		Targets:        targets,
+	})
+	if err != nil {
+		return err
+	}
+
+	return svc.WaitUntilTargetInServiceWithContext(ctx, &elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(target.targetGroupArn),
+		Targets:        targets,
+	})
+}
+
 // PrintTable Prints an ascii table of the list of Application Load Balancers
 func (i *LoadBalancersV2) PrintTable() {
 	if len(*i) == 0 {
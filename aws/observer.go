@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"sync"
+
+	"github.com/murdinc/terminal"
+)
+
+// EventType is the kind of a progress event
+type EventType int
+
+const (
+	// EventInfo is something that was found or decided
+	EventInfo EventType = iota
+	// EventChange is a change being made
+	EventChange
+	// EventNotice is something worth pointing out, like waiting on AWS
+	EventNotice
+	// EventWarning is a problem that did not stop the operation
+	EventWarning
+)
+
+// Event represents a single progress event from a library function
+type Event struct {
+	Type    EventType
+	Message string
+}
+
+// Observer receives progress events from library functions, possibly from several goroutines at once
+type Observer interface {
+	Event(e Event)
+}
+
+// ObserverFunc adapts a function to an Observer
+type ObserverFunc func(e Event)
+
+// Event calls f(e)
+func (f ObserverFunc) Event(e Event) {
+	f(e)
+}
+
+// TerminalObserver prints progress events the same way as the awsm CLI
+var TerminalObserver Observer = ObserverFunc(func(e Event) {
+	switch e.Type {
+	case EventChange:
+		terminal.Delta(e.Message)
+	case EventNotice:
+		terminal.Notice(e.Message)
+	case EventWarning:
+		terminal.ErrorLine(e.Message)
+	default:
+		terminal.Information(e.Message)
+	}
+})
+
+// progress sends events to an optional observer, and keeps the warnings for the result
+type progress struct {
+	observer Observer
+	mu       sync.Mutex
+	warnings []string
+}
+
+// newProgress returns a progress for an observer, which may be nil
+func newProgress(observer Observer) *progress {
+	return &progress{observer: observer}
+}
+
+func (p *progress) send(eventType EventType, message string) {
+	if p.observer != nil {
+		p.observer.Event(Event{Type: eventType, Message: message})
+	}
+}
+
+func (p *progress) info(message string) {
+	p.send(EventInfo, message)
+}
+
+func (p *progress) change(message string) {
+	p.send(EventChange, message)
+}
+
+func (p *progress) notice(message string) {
+	p.send(EventNotice, message)
+}
+
+func (p *progress) warn(message string) {
+	p.mu.Lock()
+	p.warnings = append(p.warnings, message)
+	p.mu.Unlock()
+
+	p.send(EventWarning, message)
+}
+
+// forward sends on an event of a nested operation, keeping its warnings
+func (p *progress) forward(e Event) {
+	if e.Type == EventWarning {
+		p.warn(e.Message)
+		return
+	}
+	p.send(e.Type, e.Message)
+}
+
+// warnErrors keeps errors that did not stop the operation, like those of regions that could not be gathered, as warnings
+func (p *progress) warnErrors(errs []error) {
+	for _, err := range errs {
+		p.warn(err.Error())
+	}
+}
@@ -0,0 +1,227 @@
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
+	"github.com/murdinc/terminal"
+)
+
+// CallerIdentity represents the AWS identity our requests are made as
+type CallerIdentity struct {
+	Account string `json:"account"`
+	Arn     string `json:"arn"`
+	UserID  string `json:"userId"`
+}
+
+// cachedCreds represents temporary credentials cached in ~/.awsm/cache
+type cachedCreds struct {
+	RoleArn         string    `json:"roleArn"`
+	AccessKeyID     string    `json:"accessKeyId"`
+	SecretAccessKey string    `json:"secretAccessKey"`
+	SessionToken    string    `json:"sessionToken"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+// GetCallerIdentity returns the AWS identity of the current credentials
+func GetCallerIdentity() (CallerIdentity, error) {
+	sess := newSession(settings.Current().DefaultRegion)
+	svc := sts.New(sess)
+
+	resp, err := svc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return CallerIdentity{}, errors.New(awsErr.Message())
+		}
+		return CallerIdentity{}, err
+	}
+
+	return CallerIdentity{
+		Account: aws.StringValue(resp.Account),
+		Arn:     aws.StringValue(resp.Arn),
+		UserID:  aws.StringValue(resp.UserId),
+	}, nil
+}
+
+// UseProfile makes a named profile from ~/.aws/credentials the credentials for all following requests.
+// Profiles with a role_arn assume that role, reusing cached temporary credentials until they expire.
+func UseProfile(name string) error {
+	cfg, err := readCreds()
+	if err != nil {
+		return errors.New("Unable to read the AWS Credentials config file: " + err.Error())
+	}
+
+	profile, ok := cfg.getProfile(name)
+	if !ok {
+		return errors.New("Unable to find the [" + name + "] profile in the AWS Credentials config file!")
+	}
+
+	if profile.RoleArn == "" {
+		// The SDK reads the keys of the profile itself, as long as there are no keys in the environment to win over it
+		for _, env := range []string{"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY", "AWS_SESSION_TOKEN"} {
+			os.Unsetenv(env)
+		}
+		os.Setenv("AWS_PROFILE", name)
+		return nil
+	}
+
+	creds, err := cfg.getRoleCreds(profile)
+	if err != nil {
+		return err
+	}
+
+	// Exported so that every new session picks them up, ahead of any shared profile
+	os.Unsetenv("AWS_PROFILE")
+	os.Setenv("AWS_ACCESS_KEY_ID", creds.AccessKeyID)
+	os.Setenv("AWS_SECRET_ACCESS_KEY", creds.SecretAccessKey)
+	os.Setenv("AWS_SESSION_TOKEN", creds.SessionToken)
+
+	return nil
+}
+
+// getProfile returns a profile by name
+func (a *awsmCreds) getProfile(name string) (Profile, bool) {
+	for _, profile := range a.Profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return Profile{}, false
+}
+
+// getRoleCreds returns temporary credentials for the role of a profile, from the cache if they are still valid
+func (a *awsmCreds) getRoleCreds(profile Profile) (cachedCreds, error) {
+	creds, err := loadCachedCreds(profile)
+	if err == nil {
+		return creds, nil
+	}
+
+	creds, err = a.assumeRole(profile)
+	if err != nil {
+		return creds, err
+	}
+
+	err = saveCachedCreds(profile.Name, creds)
+	if err != nil {
+		logger.Warn("Unable to cache the temporary credentials", "profile", profile.Name, "error", err)
+	}
+
+	return creds, nil
+}
+
+// assumeRole assumes the role of a profile with the credentials of its source profile, prompting for an MFA code if required
+func (a *awsmCreds) assumeRole(profile Profile) (cachedCreds, error) {
+	opts := session.Options{
+		Config: aws.Config{Region: aws.String(settings.Current().DefaultRegion)},
+	}
+
+	if profile.SourceProfile != "" {
+		source, ok := a.getProfile(profile.SourceProfile)
+		if !ok {
+			return cachedCreds{}, errors.New("Unable to find the source profile [" + profile.SourceProfile + "] of the [" + profile.Name + "] profile!")
+		}
+		if source.RoleArn != "" {
+			return cachedCreds{}, errors.New("The source profile [" + source.Name + "] of the [" + profile.Name + "] profile can not assume a role itself!")
+		}
+		opts.Profile = source.Name
+	}
+
+	sess := sessions.NewWithOptions(opts)
+	svc := sts.New(sess)
+
+	roleSessionName := "awsm"
+	if currentUser, err := user.Current(); err == nil {
+		roleSessionName = "awsm-" + currentUser.Username
+	}
+
+	params := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(profile.RoleArn),
+		RoleSessionName: aws.String(roleSessionName),
+		DurationSeconds: aws.Int64(3600),
+	}
+
+	if profile.ExternalID != "" {
+		params.SetExternalId(profile.ExternalID)
+	}
+
+	if profile.MFASerial != "" {
+		params.SetSerialNumber(profile.MFASerial)
+		params.SetTokenCode(prompt.String("Enter the MFA code for [" + profile.MFASerial + "]:"))
+	}
+
+	terminal.Delta("Assuming role [" + profile.RoleArn + "] for the [" + profile.Name + "] profile...")
+
+	resp, err := svc.AssumeRole(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return cachedCreds{}, errors.New(awsErr.Message())
+		}
+		return cachedCreds{}, err
+	}
+
+	return cachedCreds{
+		RoleArn:         profile.RoleArn,
+		AccessKeyID:     aws.StringValue(resp.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(resp.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(resp.Credentials.SessionToken),
+		Expiration:      aws.TimeValue(resp.Credentials.Expiration),
+	}, nil
+}
+
+// cachedCredsLocation returns the location of the cached temporary credentials of a profile
+func cachedCredsLocation(profileName string) string {
+	currentUser, _ := user.Current()
+	sep := string(os.PathSeparator)
+	return currentUser.HomeDir + sep + ".awsm" + sep + "cache" + sep + "credentials-" + profileName + ".json"
+}
+
+// loadCachedCreds returns the cached temporary credentials of a profile, if they are still valid for a few minutes
+func loadCachedCreds(profile Profile) (cachedCreds, error) {
+	var creds cachedCreds
+
+	data, err := ioutil.ReadFile(cachedCredsLocation(profile.Name))
+	if err != nil {
+		return creds, err
+	}
+
+	err = json.Unmarshal(data, &creds)
+	if err != nil {
+		return creds, err
+	}
+
+	if creds.RoleArn != profile.RoleArn || time.Now().Add(5*time.Minute).After(creds.Expiration) {
+		return creds, errors.New("Cached credentials for the [" + profile.Name + "] profile have expired!")
+	}
+
+	return creds, nil
+}
+
+// saveCachedCreds caches the temporary credentials of a profile
+func saveCachedCreds(profileName string, creds cachedCreds) error {
+	location := cachedCredsLocation(profileName)
+
+	err := os.MkdirAll(filepath.Dir(location), 0700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(location, data, 0600)
+}
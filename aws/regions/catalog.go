@@ -0,0 +1,216 @@
+package regions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
+)
+
+// Catalog represents the regions and availability zones available to the account, cached in ~/.awsm/cache per profile
+type Catalog struct {
+	Regions []string  `json:"regions"`
+	AZs     AZs       `json:"azs"`
+	Updated time.Time `json:"updated"`
+}
+
+var (
+	catalogs  = make(map[string]*Catalog) // by profile, see catalogKey
+	catalogMu sync.Mutex
+)
+
+// GetCatalog returns the region catalog. The cached catalog is used until it is older than the region cache TTL
+// of the current environment, or at any age in offline mode, otherwise it is refreshed from AWS.
+func GetCatalog() (*Catalog, error) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	key := catalogKey()
+	if catalog, ok := catalogs[key]; ok {
+		return catalog, nil
+	}
+
+	env := settings.Current()
+	cached, cacheErr := loadCatalog(key)
+
+	if env.Offline {
+		if cacheErr != nil {
+			return nil, errors.New("Unable to read the cached region catalog in offline mode, run `awsm refreshRegions` first: " + cacheErr.Error())
+		}
+		logger.Debug("Using the cached region catalog in offline mode", "profile", key, "updated", cached.Updated)
+		catalogs[key] = cached
+		return cached, nil
+	}
+
+	if cacheErr == nil && time.Since(cached.Updated) < env.RegionCacheTTL {
+		logger.Debug("Using the cached region catalog", "profile", key, "updated", cached.Updated, "ttl", env.RegionCacheTTL)
+		catalogs[key] = cached
+		return cached, nil
+	}
+
+	fresh, err := refreshCatalog(key)
+	if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		// An outdated catalog is better than none
+		logger.Warn("Unable to refresh the region catalog, using the cached one", "profile", key, "updated", cached.Updated, "error", err)
+		catalogs[key] = cached
+		return cached, nil
+	}
+
+	catalogs[key] = fresh
+	return fresh, nil
+}
+
+// RefreshCatalog refreshes the region catalog from AWS, regardless of its age
+func RefreshCatalog() (*Catalog, error) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if settings.Current().Offline {
+		return nil, errors.New("Unable to refresh the region catalog in offline mode!")
+	}
+
+	key := catalogKey()
+	fresh, err := refreshCatalog(key)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs[key] = fresh
+	return fresh, nil
+}
+
+// refreshCatalog gets the regions and their availability zones from AWS and caches them for a profile
+func refreshCatalog(key string) (*Catalog, error) {
+	sess := sessions.New(&aws.Config{Region: aws.String(settings.Current().DefaultRegion)})
+	svc := ec2.New(sess)
+
+	// Create a context with a timeout that will abort the request if it takes too long
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancelFn()
+
+	resp, err := svc.DescribeRegionsWithContext(ctx, nil)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return nil, errors.New("Unable to get the list of regions: " + awsErr.Message())
+		}
+		return nil, errors.New("Unable to get the list of regions: " + err.Error())
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	fresh := &Catalog{Updated: time.Now()}
+
+	for _, region := range resp.Regions {
+		fresh.Regions = append(fresh.Regions, aws.StringValue(region.RegionName))
+
+		wg.Add(1)
+
+		go func(region string) {
+			defer wg.Done()
+
+			regionAZs := new(AZs)
+			err := GetRegionAZs(region, regionAZs)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			fresh.AZs = append(fresh.AZs, *regionAZs...)
+		}(aws.StringValue(region.RegionName))
+	}
+	wg.Wait()
+
+	// A partial catalog is not cached
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	sort.Strings(fresh.Regions)
+	sort.Slice(fresh.AZs, func(i, j int) bool { return fresh.AZs[i].Name < fresh.AZs[j].Name })
+
+	err = saveCatalog(key, fresh)
+	if err != nil {
+		logger.Warn("Unable to cache the region catalog", "profile", key, "error", err)
+	}
+
+	logger.Debug("Refreshed the region catalog", "profile", key, "regions", len(fresh.Regions), "azs", len(fresh.AZs))
+
+	return fresh, nil
+}
+
+// catalogKey returns the profile whose credentials are in use, as the regions and availability zones (and their
+// names) differ between accounts
+func catalogKey() string {
+	if profile := settings.Current().Profile; profile != "" {
+		return profile
+	}
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
+// catalogLocation returns the location of the cached region catalog of a profile
+func catalogLocation(key string) string {
+	currentUser, _ := user.Current()
+	sep := string(os.PathSeparator)
+	return currentUser.HomeDir + sep + ".awsm" + sep + "cache" + sep + "regions-" + key + ".json"
+}
+
+// loadCatalog reads the cached region catalog of a profile
+func loadCatalog(key string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(catalogLocation(key))
+	if err != nil {
+		return nil, err
+	}
+
+	cached := new(Catalog)
+	err = json.Unmarshal(data, cached)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cached.Regions) == 0 {
+		return nil, errors.New("The cached region catalog is empty!")
+	}
+
+	return cached, nil
+}
+
+// saveCatalog caches the region catalog of a profile
+func saveCatalog(key string, c *Catalog) error {
+	location := catalogLocation(key)
+
+	err := os.MkdirAll(filepath.Dir(location), 0700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(location, data, 0600)
+}
@@ -2,59 +2,96 @@ package regions
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/settings"
 )
 
-// GetRegionList returns a list of AWS Regions as a slice of *ec2.Region
-func GetRegionList() []*ec2.Region {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")}))
-	svc := ec2.New(sess)
+// GetRegionList returns a list of AWS Regions as a slice of *ec2.Region, from the region catalog
+func GetRegionList() ([]*ec2.Region, error) {
+	catalog, err := GetCatalog()
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a context with a timeout that will abort the request if it takes too long
-	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancelFn()
+	regionList := make([]*ec2.Region, len(catalog.Regions))
+	for i, name := range catalog.Regions {
+		regionList[i] = &ec2.Region{RegionName: aws.String(name)}
+	}
 
-	resp, err := svc.DescribeRegionsWithContext(ctx, nil)
+	return regionList, nil
+}
 
+// GetRegionNameList returns a list of AWS Region Names as slice of strings
+func GetRegionNameList() ([]string, error) {
+	catalog, err := GetCatalog()
 	if err != nil {
-		fmt.Println(err.Error())
-		return nil
+		return nil, err
 	}
 
-	return resp.Regions
+	return append([]string{}, catalog.Regions...), nil
 }
 
-// GetRegionNameList returns a list of AWS Region Names as slice of strings
-func GetRegionNameList() (names []string) {
-	vregions := GetRegionList()
-	for _, vregion := range vregions {
-		names = append(names, aws.StringValue(vregion.RegionName))
+// Selected returns true if the provided region is in the region selection of the current environment, or if there is no selection
+func Selected(region string) bool {
+	selection := settings.Current().Regions
+	if len(selection) == 0 {
+		return true
 	}
-	return
+
+	for _, selected := range selection {
+		if region == selected {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRegions returns an error if any of the provided region names are not valid AWS regions
+func ValidateRegions(names []string) error {
+	valid, err := GetRegionNameList()
+	if err != nil {
+		return errors.New("Unable to get the list of regions to validate against: " + err.Error())
+	}
+
+Loop:
+	for _, name := range names {
+		for _, region := range valid {
+			if name == region {
+				continue Loop
+			}
+		}
+		return errors.New("Region [" + name + "] is not valid! Valid regions are: " + strings.Join(valid, ", "))
+	}
+
+	return nil
 }
 
 // GetAZNameList returns a list of AWS Availability Zone Names as slice of strings
-func GetAZNameList() (names []string) {
-	azs, _ := GetAZs()
-	azlist := make([]string, len(*azs))
+func GetAZNameList() ([]string, error) {
+	catalog, err := GetCatalog()
+	if err != nil {
+		return nil, err
+	}
 
-	for i, az := range *azs {
+	azlist := make([]string, len(catalog.AZs))
+	for i, az := range catalog.AZs {
 		azlist[i] = az.Name
 	}
-	return azlist
+	return azlist, nil
 }
 
 // ValidRegion returns true if the provided region is valid
 func ValidRegion(region string) bool {
-	vregions := GetRegionList()
+	vregions, _ := GetRegionNameList()
 	for _, vregion := range vregions {
-		if region == aws.StringValue(vregion.RegionName) {
+		if region == vregion {
 			return true
 		}
 	}
@@ -71,35 +108,22 @@ type AZ struct {
 	State  string `json:"state"`
 }
 
-// GetAZs returns a slice of Availability Zones
+// GetAZs returns a slice of Availability Zones, from the region catalog
 func GetAZs() (*AZs, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
-	azList := new(AZs)
-	regions := GetRegionList()
-
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionAZs(*region.RegionName, azList)
-			if err != nil {
-				errs = append(errs, err)
-			}
-		}(region)
+	catalog, err := GetCatalog()
+	if err != nil {
+		return new(AZs), []error{err}
 	}
 
-	wg.Wait()
+	azList := append(AZs{}, catalog.AZs...)
 
-	return azList, errs
+	return &azList, nil
 }
 
 // GetRegionAZs returns a slice of a regions Availability Zones into the provided AZs
 func GetRegionAZs(region string, azList *AZs) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := sessions.New(&aws.Config{Region: aws.String(region)})
 	svc := ec2.New(sess)
 
 	// Create a context with a timeout that will abort the request if it takes too long
@@ -109,8 +133,10 @@ func GetRegionAZs(region string, azList *AZs) error {
 	result, err := svc.DescribeAvailabilityZonesWithContext(ctx, nil)
 
 	if err != nil {
-		/*terminal.ErrorLine("Region AZ request timeout in [" + region + "], skipping...")*/
-		return nil
+		if awsErr, ok := err.(awserr.Error); ok {
+			return errors.New("Unable to get the availability zones of region [" + region + "]: " + awsErr.Message())
+		}
+		return errors.New("Unable to get the availability zones of region [" + region + "]: " + err.Error())
 	}
 
 	azs := make(AZs, len(result.AvailabilityZones))
@@ -149,13 +175,11 @@ func (a *AZs) GetRegion(az string) string {
 
 // GetRegionMap returns a map of Regions and their Availability Zones
 func (a *AZs) GetRegionMap(azList []string) map[string][]string {
-	azs, _ := GetAZs()
-
 	regionMap := make(map[string][]string)
 
 	// Get the list of regions from the AZs
 	for _, az := range azList {
-		region := azs.GetRegion(az)
+		region := a.GetRegion(az)
 		regionMap[region] = append(regionMap[region], az)
 
 	}
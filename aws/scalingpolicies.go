@@ -7,15 +7,13 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -28,33 +26,24 @@ type ScalingPolicy models.ScalingPolicy
 
 // GetScalingPolicies returns a slice of Scaling Policies based on the given search term
 func GetScalingPolicies(search string) (*ScalingPolicies, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	spList := new(ScalingPolicies)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionScalingPolicies(*region.RegionName, spList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering scaling policy list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("scaling policy", func(account Account, region string) error {
+		return getAccountRegionScalingPolicies(account, region, spList, search)
+	})
 
 	return spList, errs
 }
 
 // GetRegionScalingPolicies returns a slice of Scaling Policies for a region into the given ScalingPolicies slice
 func GetRegionScalingPolicies(region string, spList *ScalingPolicies, search string) error {
+	return getAccountRegionScalingPolicies(CurrentAccount(), region, spList, search)
+}
+
+// getAccountRegionScalingPolicies returns a slice of Scaling Policies for a region of an account into the given ScalingPolicies slice
+func getAccountRegionScalingPolicies(account Account, region string, spList *ScalingPolicies, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := autoscaling.New(sess)
 
 	result, err := svc.DescribePolicies(&autoscaling.DescribePoliciesInput{})
@@ -65,6 +54,7 @@ func GetRegionScalingPolicies(region string, spList *ScalingPolicies, search str
 	sp := make(ScalingPolicies, len(result.ScalingPolicies))
 	for i, policy := range result.ScalingPolicies {
 		sp[i].Marshal(policy, region)
+		sp[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -150,7 +140,9 @@ func UpdateScalingPolicies(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionScalingPolicies(region, spList, search)
 	} else {
-		spList, _ = GetScalingPolicies(search)
+		var errs []error
+		spList, errs = GetScalingPolicies(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -165,7 +157,7 @@ func UpdateScalingPolicies(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to update these Scaling Policies?") {
+	if !prompt.Confirm("Are you sure you want to update these Scaling Policies?") {
 		return errors.New("Aborting!")
 	}
 
@@ -194,7 +186,7 @@ func updateScalingPolicies(spList *ScalingPolicies, dryRun bool) (arn string, er
 		}
 		terminal.Information("Found Scaling Policy class configuration for [" + sp.Name + "]")
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(sp.Region)}))
+		sess := newSession(sp.Region)
 		svc := autoscaling.New(sess)
 
 		// Create the scaling policy
@@ -243,7 +235,8 @@ func CreateScalingPolicy(class, asgSearch string, dryRun bool) error {
 	}
 	terminal.Information("Found Scaling Policy class configuration for [" + class + "]")
 
-	asgList, _ := GetAutoScaleGroups(asgSearch)
+	asgList, errs := GetAutoScaleGroups(asgSearch)
+	ShowErrors(errs)
 
 	if len(*asgList) > 0 {
 		// Print the table
@@ -253,7 +246,7 @@ func CreateScalingPolicy(class, asgSearch string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to create this Scaling Policy in these AutoScaling Groups?") {
+	if !prompt.Confirm("Are you sure you want to create this Scaling Policy in these AutoScaling Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -274,7 +267,7 @@ func createScalingPolicy(name string, cfg config.ScalingPolicyClass, asgList *Au
 
 		terminal.Delta("Adding Scaling Policy [" + name + "] to AutoScale Group [" + asg.Name + "] in [" + asg.Region + "]")
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(asg.Region)}))
+		sess := newSession(asg.Region)
 		svc := autoscaling.New(sess)
 
 		// Create the scaling policy
@@ -322,7 +315,9 @@ func DeleteScalingPolicies(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionScalingPolicies(region, spList, search)
 	} else {
-		spList, _ = GetScalingPolicies(search)
+		var errs []error
+		spList, errs = GetScalingPolicies(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -337,7 +332,7 @@ func DeleteScalingPolicies(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Scaling Policies?") {
+	if !prompt.Confirm("Are you sure you want to delete these Scaling Policies?") {
 		return errors.New("Aborting!")
 	}
 
@@ -356,7 +351,7 @@ func DeleteScalingPolicies(search, region string, dryRun bool) (err error) {
 // Private function without the confirmation terminal prompts
 func deleteScalingPolicies(spList *ScalingPolicies, dryRun bool) (err error) {
 	for _, policy := range *spList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(policy.Region)}))
+		sess := newSession(policy.Region)
 		svc := autoscaling.New(sess)
 
 		params := &autoscaling.DeletePolicyInput{
@@ -403,7 +398,9 @@ func ExecuteScalingPolicies(search, region string, force, dryRun bool) (err erro
 	if region != "" {
 		err = GetRegionScalingPolicies(region, spList, search)
 	} else {
-		spList, _ = GetScalingPolicies(search)
+		var errs []error
+		spList, errs = GetScalingPolicies(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -418,7 +415,7 @@ func ExecuteScalingPolicies(search, region string, force, dryRun bool) (err erro
 	}
 
 	// Confirm
-	if !force && !terminal.PromptBool("Are you sure you want to execute these Scaling Policies?") {
+	if !force && !prompt.Confirm("Are you sure you want to execute these Scaling Policies?") {
 		return errors.New("Aborting!")
 	}
 
@@ -440,7 +437,7 @@ func executeScalingPolicies(spList *ScalingPolicies, force, dryRun bool) (arn st
 
 		terminal.Delta("Executing Scaling Policy [" + sp.Name + "] on AutoScale Group [" + sp.AutoScaleGroupName + "] in [" + sp.Region + "]")
 
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(sp.Region)}))
+		sess := newSession(sp.Region)
 		svc := autoscaling.New(sess)
 
 		// Create the scaling policy
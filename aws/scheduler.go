@@ -0,0 +1,487 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/schedule"
+	"github.com/murdinc/terminal"
+	"github.com/olekukonko/tablewriter"
+)
+
+// schedulerCapacityTag holds the capacity of an AutoScale Group while the scheduler has it stopped, as min/max/desired
+const schedulerCapacityTag = "awsm:scheduler:capacity"
+
+// ScheduledActions is a slice of Scheduled Actions
+type ScheduledActions []ScheduledAction
+
+// ScheduledAction is a start or stop of the Instances or AutoScale Groups of a class
+type ScheduledAction struct {
+	Time      time.Time
+	Action    string // start or stop
+	ClassType string // instances or autoscalegroups
+	Class     string
+}
+
+// classSchedule is the start and stop schedules of a class, either of which can be nil
+type classSchedule struct {
+	classType string
+	class     string
+	start     *schedule.Schedule
+	stop      *schedule.Schedule
+}
+
+// PrintTable Prints an ascii table of the list of Scheduled Actions
+func (s ScheduledActions) PrintTable() {
+	if len(s) == 0 {
+		terminal.ShowErrorMessage("Warning", "No Scheduled Actions found!")
+		return
+	}
+
+	rows := make([][]string, len(s))
+	for i, action := range s {
+		rows[i] = []string{
+			action.Time.Format("Mon Jan 2 15:04 MST"),
+			action.Action,
+			action.ClassType,
+			action.Class,
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Time", "Action", "Class Type", "Class"})
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+// loadSchedules loads the schedules of the Instance and AutoScale Group classes that have one. Classes with an invalid
+// schedule are warned about and skipped, so that they don't hold up the others.
+func loadSchedules(p *progress) ([]classSchedule, error) {
+	var schedules []classSchedule
+
+	add := func(classType, class, start, stop, timezone string) error {
+		if start == "" && stop == "" {
+			return nil
+		}
+
+		s := classSchedule{classType: classType, class: class}
+
+		var err error
+		if start != "" {
+			if s.start, err = schedule.Parse(start, timezone); err != nil {
+				return errors.New("The start schedule of class [" + class + "] is invalid: " + err.Error())
+			}
+		}
+		if stop != "" {
+			if s.stop, err = schedule.Parse(stop, timezone); err != nil {
+				return errors.New("The stop schedule of class [" + class + "] is invalid: " + err.Error())
+			}
+		}
+
+		schedules = append(schedules, s)
+		return nil
+	}
+
+	instanceCfgs, err := config.LoadAllInstanceClasses()
+	if err != nil {
+		return nil, err
+	}
+	for class, cfg := range instanceCfgs {
+		if err := add("instances", class, cfg.ScheduleStart, cfg.ScheduleStop, cfg.ScheduleTimezone); err != nil {
+			p.warn(err.Error() + ", skipping it!")
+			logger.Warn("Skipping an invalid class schedule", "classType", "instances", "class", class, "error", err)
+		}
+	}
+
+	asgCfgs, err := config.LoadAllAutoscalingGroupClasses()
+	if err != nil {
+		return nil, err
+	}
+	for class, cfg := range asgCfgs {
+		if err := add("autoscalegroups", class, cfg.ScheduleStart, cfg.ScheduleStop, cfg.ScheduleTimezone); err != nil {
+			p.warn(err.Error() + ", skipping it!")
+			logger.Warn("Skipping an invalid class schedule", "classType", "autoscalegroups", "class", class, "error", err)
+		}
+	}
+
+	return schedules, nil
+}
+
+// dueActions returns the actions of the schedules that fire after a time, up to and including another, in order
+func dueActions(schedules []classSchedule, after, until time.Time) ScheduledActions {
+	var actions ScheduledActions
+
+	for _, s := range schedules {
+		for _, action := range []struct {
+			name     string
+			schedule *schedule.Schedule
+		}{{"start", s.start}, {"stop", s.stop}} {
+			if action.schedule == nil {
+				continue
+			}
+
+			for t := action.schedule.Next(after); !t.IsZero() && !t.After(until); t = action.schedule.Next(t) {
+				actions = append(actions, ScheduledAction{Time: t, Action: action.name, ClassType: s.classType, Class: s.class})
+			}
+		}
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Time.Before(actions[j].Time) })
+
+	return actions
+}
+
+// GetScheduledActions returns the actions that the scheduler would run from now until the given time
+func GetScheduledActions(until time.Time) (ScheduledActions, error) {
+	schedules, err := loadSchedules(newProgress(TerminalObserver))
+	if err != nil {
+		return nil, err
+	}
+
+	return dueActions(schedules, time.Now(), until), nil
+}
+
+// SchedulerOptions are the options for SchedulerWithContext
+type SchedulerOptions struct {
+	DryRun   bool
+	Observer Observer
+}
+
+// RunScheduler runs the scheduler until it is interrupted, starting and stopping the Instances and AutoScale Groups of
+// classes with a schedule. With dryRun, it only shows the actions of the next week.
+func RunScheduler(dryRun bool) error {
+
+	actions, err := GetScheduledActions(time.Now().AddDate(0, 0, 7))
+	if err != nil {
+		return err
+	}
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, showing the scheduled actions of the next week!")
+		actions.PrintTable()
+		return nil
+	}
+
+	terminal.Information("Starting the scheduler, the scheduled actions of the next week are:")
+	actions.PrintTable()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			terminal.Information("Stopping the scheduler...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err = SchedulerWithContext(ctx, SchedulerOptions{Observer: TerminalObserver})
+	if err == context.Canceled {
+		return nil
+	}
+
+	return err
+}
+
+// SchedulerWithContext runs the scheduled actions of classes until the context is done, without any terminal output.
+// The classes are reloaded every minute, so that schedule changes are picked up without a restart. Actions that were
+// due while the scheduler wasn't running are not caught up on.
+func SchedulerWithContext(ctx context.Context, opts SchedulerOptions) error {
+	p := newProgress(opts.Observer)
+
+	last := time.Now()
+
+	for {
+		// Wake up at the start of each minute, when the schedules fire
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		now = time.Now()
+
+		schedules, err := loadSchedules(p)
+		if err != nil {
+			// Keep going with the next minute, the class store may be briefly unavailable
+			p.warn("Unable to load the class schedules: " + err.Error())
+			logger.Warn("Unable to load the class schedules", "error", err)
+			continue
+		}
+
+		for _, action := range dueActions(schedules, last, now) {
+			logger.Info("Running scheduled action", "action", action.Action, "classType", action.ClassType, "class", action.Class, "dryRun", opts.DryRun)
+
+			err := runScheduledAction(ctx, p, action, opts.DryRun)
+			if err != nil {
+				p.warn(fmt.Sprintf("Unable to %s the %s of class [%s]: %s", action.Action, action.ClassType, action.Class, err.Error()))
+				logger.Error("Scheduled action failed", "action", action.Action, "classType", action.ClassType, "class", action.Class, "error", err)
+			}
+		}
+
+		last = now
+	}
+}
+
+// runScheduledAction starts or stops the Instances or AutoScale Groups of a class in every region
+func runScheduledAction(ctx context.Context, p *progress, action ScheduledAction, dryRun bool) error {
+	regionList, err := GetRegionListWithoutIgnored()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []string
+
+	for _, region := range regionList {
+		wg.Add(1)
+
+		go func(region string) {
+			defer wg.Done()
+
+			var err error
+			switch action.ClassType {
+			case "instances":
+				err = scheduleInstances(ctx, p, region, action, dryRun)
+			case "autoscalegroups":
+				err = scheduleAutoScaleGroup(ctx, p, region, action, dryRun)
+			}
+
+			if err != nil {
+				if awsErr, ok := err.(awserr.Error); ok {
+					err = errors.New(awsErr.Message())
+				}
+				mu.Lock()
+				errs = append(errs, "["+region+"] "+err.Error())
+				mu.Unlock()
+			}
+		}(aws.StringValue(region.RegionName))
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// scheduleInstances starts the stopped, or stops the running, Instances of a class in a region. Instances launched by
+// AutoScale Groups are left alone, as their groups would replace them.
+func scheduleInstances(ctx context.Context, p *progress, region string, action ScheduledAction, dryRun bool) error {
+	state := "running"
+	if action.Action == "start" {
+		state = "stopped"
+	}
+
+	svc := ec2.New(newSession(region))
+
+	var instanceIds []*string
+	var names []string
+
+	err := svc.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:Class"),
+				Values: []*string{aws.String(action.Class)},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String(state)},
+			},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if GetTagValue("aws:autoscaling:groupName", instance.Tags) != "" {
+					continue
+				}
+				instanceIds = append(instanceIds, instance.InstanceId)
+				names = append(names, GetTagValue("Name", instance.Tags))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(instanceIds) == 0 {
+		return nil
+	}
+
+	description := fmt.Sprintf("Instances [%s] of class [%s] in [%s]", strings.Join(names, ", "), action.Class, region)
+
+	if dryRun {
+		p.notice("Would " + action.Action + " " + description)
+		return nil
+	}
+
+	done := "Stopped"
+	if action.Action == "start" {
+		done = "Started"
+		_, err = svc.StartInstancesWithContext(ctx, &ec2.StartInstancesInput{InstanceIds: instanceIds})
+	} else {
+		_, err = svc.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{InstanceIds: instanceIds})
+	}
+	if err != nil {
+		return err
+	}
+
+	p.change(done + " " + description + "!")
+
+	return nil
+}
+
+// scheduleAutoScaleGroup sets the capacity of the AutoScale Group of a class in a region to zero, remembering its
+// capacity in a tag, or restores it. Without the tag, starting restores the capacity of the AutoScale Group class.
+func scheduleAutoScaleGroup(ctx context.Context, p *progress, region string, action ScheduledAction, dryRun bool) error {
+	svc := autoscaling.New(newSession(region))
+
+	resp, err := svc.DescribeAutoScalingGroupsWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(action.Class)},
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.AutoScalingGroups) == 0 {
+		return nil
+	}
+
+	asg := resp.AutoScalingGroups[0]
+	name := aws.StringValue(asg.AutoScalingGroupName)
+	saved := GetTagValue(schedulerCapacityTag, asg.Tags)
+
+	params := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: asg.AutoScalingGroupName,
+	}
+
+	if action.Action == "stop" {
+		if aws.Int64Value(asg.MaxSize) == 0 {
+			return nil
+		}
+
+		capacity := fmt.Sprintf("%d/%d/%d", aws.Int64Value(asg.MinSize), aws.Int64Value(asg.MaxSize), aws.Int64Value(asg.DesiredCapacity))
+
+		if dryRun {
+			p.notice("Would stop AutoScale Group [" + name + "] in [" + region + "], saving its capacity [" + capacity + "]")
+			return nil
+		}
+
+		// Save the capacity first, so that it isn't lost if the update fails halfway
+		_, err = svc.CreateOrUpdateTagsWithContext(ctx, &autoscaling.CreateOrUpdateTagsInput{
+			Tags: []*autoscaling.Tag{
+				{
+					Key:               aws.String(schedulerCapacityTag),
+					PropagateAtLaunch: aws.Bool(false),
+					ResourceId:        asg.AutoScalingGroupName,
+					ResourceType:      aws.String("auto-scaling-group"),
+					Value:             aws.String(capacity),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		params.MinSize = aws.Int64(0)
+		params.MaxSize = aws.Int64(0)
+		params.DesiredCapacity = aws.Int64(0)
+
+		_, err = svc.UpdateAutoScalingGroupWithContext(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		p.change("Stopped AutoScale Group [" + name + "] in [" + region + "], saving its capacity [" + capacity + "]!")
+		return nil
+	}
+
+	// Start
+	if aws.Int64Value(asg.MaxSize) > 0 && saved == "" {
+		return nil
+	}
+
+	var min, max, desired int64
+	if saved != "" {
+		parts := strings.Split(saved, "/")
+		if len(parts) != 3 {
+			return errors.New("Invalid saved capacity [" + saved + "] on AutoScale Group [" + name + "]!")
+		}
+		for i, v := range []*int64{&min, &max, &desired} {
+			*v, err = strconv.ParseInt(parts[i], 10, 64)
+			if err != nil {
+				return errors.New("Invalid saved capacity [" + saved + "] on AutoScale Group [" + name + "]!")
+			}
+		}
+	} else {
+		cfg, err := config.LoadAutoscalingGroupClass(action.Class)
+		if err != nil {
+			return err
+		}
+		min, max, desired = int64(cfg.MinSize), int64(cfg.MaxSize), int64(cfg.DesiredCapacity)
+	}
+
+	capacity := fmt.Sprintf("%d/%d/%d", min, max, desired)
+
+	if dryRun {
+		p.notice("Would start AutoScale Group [" + name + "] in [" + region + "] with the capacity [" + capacity + "]")
+		return nil
+	}
+
+	params.MinSize = aws.Int64(min)
+	params.MaxSize = aws.Int64(max)
+	params.DesiredCapacity = aws.Int64(desired)
+
+	_, err = svc.UpdateAutoScalingGroupWithContext(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if saved != "" {
+		_, err = svc.DeleteTagsWithContext(ctx, &autoscaling.DeleteTagsInput{
+			Tags: []*autoscaling.Tag{
+				{
+					Key:          aws.String(schedulerCapacityTag),
+					ResourceId:   asg.AutoScalingGroupName,
+					ResourceType: aws.String("auto-scaling-group"),
+				},
+			},
+		})
+		if err != nil {
+			p.warn("Unable to remove the saved capacity of AutoScale Group [" + name + "] in [" + region + "]: " + err.Error())
+		}
+	}
+
+	p.change("Started AutoScale Group [" + name + "] in [" + region + "] with the capacity [" + capacity + "]!")
+
+	return nil
+}
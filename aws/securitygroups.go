@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -9,17 +10,16 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"sync"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/mitchellh/hashstructure"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -58,7 +58,7 @@ func (s *SecurityGroups) GetSecurityGroupIDs() []string {
 // GetSecurityGroupByName returns a single Security Group that matches a provided region and name
 func GetSecurityGroupByName(region, name string) (SecurityGroup, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSecurityGroupsInput{
@@ -93,7 +93,7 @@ func GetSecurityGroupByName(region, name string) (SecurityGroup, error) {
 // GetClassicSecurityGroupByName returns a single Security Group that matches a provided region and name, (non-vpc only)
 func GetEc2ClassicSecurityGroupByName(region, name string) (SecurityGroup, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSecurityGroupsInput{
@@ -136,7 +136,7 @@ func GetEc2ClassicSecurityGroupByName(region, name string) (SecurityGroup, error
 // GetSecurityGroupByTag returns a single Security Group that matches a provided region and key/value tag
 func GetSecurityGroupByTag(region, key, value string) (SecurityGroup, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSecurityGroupsInput{
@@ -189,38 +189,29 @@ func GetSecurityGroupByTagMulti(region, key string, value []string) (SecurityGro
 
 // GetSecurityGroups returns a slice of Security Groups given a provided search term
 func GetSecurityGroups(search string) (*SecurityGroups, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	secGrpList := new(SecurityGroups)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionSecurityGroups(*region.RegionName, secGrpList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering security group list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("security group", func(account Account, region string) error {
+		return getAccountRegionSecurityGroups(account, region, secGrpList, search)
+	})
 
 	return secGrpList, errs
 }
 
 // GetRegionSecurityGroups returns a regions Security Groups into the provided SecurityGroups slice
 func GetRegionSecurityGroups(region string, secGrpList *SecurityGroups, search string) error {
+	return getAccountRegionSecurityGroups(CurrentAccount(), region, secGrpList, search)
+}
+
+// getAccountRegionSecurityGroups returns the Security Groups of a region of an account into the provided SecurityGroups slice
+func getAccountRegionSecurityGroups(account Account, region string, secGrpList *SecurityGroups, search string) error {
 
 	// Validate the destination region
 	if !regions.ValidRegion(region) {
 		return errors.New("Region [" + region + "] is Invalid!")
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
@@ -230,11 +221,12 @@ func GetRegionSecurityGroups(region string, secGrpList *SecurityGroups, search s
 	}
 
 	vpcList := new(Vpcs)
-	GetRegionVpcs(region, vpcList, "")
+	getAccountRegionVpcs(account, region, vpcList, "")
 
 	sgroup := make(SecurityGroups, len(result.SecurityGroups))
 	for i, securitygroup := range result.SecurityGroups {
 		sgroup[i].Marshal(securitygroup, region, vpcList)
+		sgroup[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -266,6 +258,7 @@ func (s *SecurityGroup) Marshal(securitygroup *ec2.SecurityGroup, region string,
 
 	s.Name = GetTagValue("Name", securitygroup.Tags)
 	s.Class = GetTagValue("Class", securitygroup.Tags)
+	s.Tags = GetTagMap(securitygroup.Tags)
 	s.GroupID = aws.StringValue(securitygroup.GroupId)
 	s.Description = aws.StringValue(securitygroup.Description)
 	s.Vpc = vpc
@@ -390,7 +383,7 @@ func CreateSecurityGroup(class, region, vpc string, dryRun bool) error {
 	}
 
 	// Create the security group
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	createSecGrpResponse, err := svc.CreateSecurityGroup(params)
@@ -403,7 +396,7 @@ func CreateSecurityGroup(class, region, vpc string, dryRun bool) error {
 	}
 
 	// Add Tags
-	SetEc2NameAndClassTags(createSecGrpResponse.GroupId, class, class, region)
+	SetEc2ClassTags(createSecGrpResponse.GroupId, class, class, cfg.Tags, region)
 	terminal.Delta("Created Security Group [" + aws.StringValue(createSecGrpResponse.GroupId) + "] in region [" + region + "]")
 
 	// Add Grants
@@ -435,7 +428,14 @@ func CreateSecurityGroup(class, region, vpc string, dryRun bool) error {
 		return err
 	}
 
-	return updateSecurityGroups(changes, dryRun)
+	err = updateSecurityGroups(context.Background(), changes, dryRun)
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
 }
 
 // DeleteSecurityGroups deletes one or more Security Groups that match the provided search term and optional region
@@ -452,7 +452,9 @@ func DeleteSecurityGroups(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionSecurityGroups(region, secGrpList, search)
 	} else {
-		secGrpList, _ = GetSecurityGroups(search)
+		var errs []error
+		secGrpList, errs = GetSecurityGroups(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -467,7 +469,7 @@ func DeleteSecurityGroups(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Security Groups?") {
+	if !prompt.Confirm("Are you sure you want to delete these Security Groups?") {
 		return errors.New("Aborting!")
 	}
 
@@ -485,6 +487,25 @@ func DeleteSecurityGroups(search, region string, dryRun bool) (err error) {
 	return nil
 }
 
+// UpdateSecurityGroupsOptions are the options for UpdateSecurityGroupsWithContext
+type UpdateSecurityGroupsOptions struct {
+	Search string
+	Region string // optional, all regions are searched if empty
+	DryRun bool
+
+	// Confirm is called with the Security Groups and their changes before they are made, nothing is changed if it returns false
+	Confirm func(groups SecurityGroups, changes SecurityGroupChanges) bool
+
+	Observer Observer
+}
+
+// UpdateSecurityGroupsResult is the result of UpdateSecurityGroupsWithContext
+type UpdateSecurityGroupsResult struct {
+	Groups   SecurityGroups
+	Changes  SecurityGroupChanges
+	Warnings []string
+}
+
 // UpdateSecurityGroups updates one or more Security Groups that match the provided search term and optional region
 func UpdateSecurityGroups(search, region string, dryRun bool) (err error) {
 
@@ -493,51 +514,86 @@ func UpdateSecurityGroups(search, region string, dryRun bool) (err error) {
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	secGrpList := new(SecurityGroups)
-
-	// Check if we were given a region or not
-	if region != "" {
-		err = GetRegionSecurityGroups(region, secGrpList, search)
-	} else {
-		secGrpList, _ = GetSecurityGroups(search)
+	opts := UpdateSecurityGroupsOptions{
+		Search: search,
+		Region: region,
+		DryRun: dryRun,
+		Confirm: func(groups SecurityGroups, changes SecurityGroupChanges) bool {
+			groups.PrintTable()
+			return prompt.Confirm("Are you sure you want to update these Security Groups?")
+		},
+		Observer: TerminalObserver,
 	}
 
+	result, err := UpdateSecurityGroupsWithContext(context.Background(), opts)
 	if err != nil {
 		return err
 	}
 
-	if len(*secGrpList) > 0 {
-		// Print the table
-		secGrpList.PrintTable()
+	if len(result.Changes) == 0 {
+		result.Groups.PrintTable()
+		terminal.Information("There are no changes needed on these security groups!")
+		return nil
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// UpdateSecurityGroupsWithContext updates the grants of Security Groups to match their classes, without any terminal output or prompts
+func UpdateSecurityGroupsWithContext(ctx context.Context, opts UpdateSecurityGroupsOptions) (*UpdateSecurityGroupsResult, error) {
+	p := newProgress(opts.Observer)
+
+	secGrpList := new(SecurityGroups)
+
+	// Check if we were given a region or not
+	if opts.Region != "" {
+		err := GetRegionSecurityGroups(opts.Region, secGrpList, opts.Search)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		return errors.New("No Security Groups found, Aborting!")
+		var errs []error
+		secGrpList, errs = GetSecurityGroups(opts.Search)
+		p.warnErrors(errs)
+	}
+
+	if len(*secGrpList) == 0 {
+		return nil, errors.New("No Security Groups found, Aborting!")
 	}
 
-	changes, err := secGrpList.Diff()
+	changes, err := secGrpList.diff(p)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := &UpdateSecurityGroupsResult{
+		Groups:  *secGrpList,
+		Changes: changes,
 	}
 
 	if len(changes) == 0 {
-		terminal.Information("There are no changes needed on these security groups!")
-		return nil
+		return result, nil
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to update these Security Groups?") {
-		return errors.New("Aborting!")
+	if opts.Confirm != nil && !opts.Confirm(result.Groups, changes) {
+		return nil, ErrAborted
 	}
 
 	// Update 'Em
-	err = updateSecurityGroups(changes, dryRun)
+	err = updateSecurityGroups(ctx, changes, opts.DryRun)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return errors.New(awsErr.Message())
+			return result, errors.New(awsErr.Message())
 		}
-		return err
+		return result, err
 	}
 
-	return nil
+	result.Warnings = p.warnings
+
+	return result, nil
 }
 
 type SecurityGroupChanges []SecurityGroupChange
@@ -545,13 +601,19 @@ type SecurityGroupChanges []SecurityGroupChange
 type SecurityGroupChange struct {
 	Group  SecurityGroup
 	Revoke bool
-	Type   string
+	Type   string // ingress, egress or tags
 	Grants []config.SecurityGroupGrant
+	Tags   map[string]string // the custom tags of the class that the Security Group is missing
 }
 
 func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
+	return s.diff(newProgress(TerminalObserver))
+}
 
-	terminal.Delta("Comparing awsm Security Group grants...")
+// diff compares the grants of Security Groups to their classes, and returns the changes needed
+func (s SecurityGroups) diff(p *progress) ([]SecurityGroupChange, error) {
+
+	p.change("Comparing awsm Security Group grants...")
 
 	changes := []SecurityGroupChange{}
 	cfgHashes := make([]map[uint64]config.SecurityGroupGrant, len(s))
@@ -564,6 +626,16 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 			return changes, err
 		}
 
+		// custom tags of the class
+		if tags := missingTags(secGrp.Tags, cfg.Tags); len(tags) > 0 {
+			p.change(fmt.Sprintf("[%s %s] - Tag - %s", secGrp.Name, secGrp.Region, formatTags(tags)))
+			changes = append(changes, SecurityGroupChange{
+				Group: secGrp,
+				Type:  "tags",
+				Tags:  tags,
+			})
+		}
+
 		// cycle through the config grants and generate hashess
 		for _, cGrant := range cfg.SecurityGroupGrants {
 
@@ -613,7 +685,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 				}
 
 				if _, ok := cfgHashes[i][existingGrantHash]; !ok {
-					terminal.Delta(fmt.Sprintf("[%s %s] - Deauthorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, cidrIpGrant.Type, cidrIpGrant.IPProtocol, cidrIpGrant.FromPort, cidrIpGrant.ToPort, strings.Join(cidrIpGrant.CidrIPs, ", ")))
+					p.change(fmt.Sprintf("[%s %s] - Deauthorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, cidrIpGrant.Type, cidrIpGrant.IPProtocol, cidrIpGrant.FromPort, cidrIpGrant.ToPort, strings.Join(cidrIpGrant.CidrIPs, ", ")))
 
 					if cidrIpGrant.Type == "ingress" {
 						removeIngress = append(removeIngress, cidrIpGrant)
@@ -622,7 +694,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 					}
 
 				} else {
-					//terminal.Notice(fmt.Sprintf("[%s %s] - Keeping - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, cidrIpGrant.Type, cidrIpGrant.IPProtocol, cidrIpGrant.FromPort, cidrIpGrant.ToPort, strings.Join(cidrIpGrant.CidrIPs, ", ")))
+					//p.notice(fmt.Sprintf("[%s %s] - Keeping - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, cidrIpGrant.Type, cidrIpGrant.IPProtocol, cidrIpGrant.FromPort, cidrIpGrant.ToPort, strings.Join(cidrIpGrant.CidrIPs, ", ")))
 					delete(cfgHashes[i], existingGrantHash)
 				}
 			}
@@ -638,7 +710,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 				}
 
 				if _, ok := cfgHashes[i][existingGrantHash]; !ok {
-					terminal.Delta(fmt.Sprintf("[%s %s] - Deauthorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, secGrpGrant.Type, secGrpGrant.IPProtocol, secGrpGrant.FromPort, secGrpGrant.ToPort, strings.Join(secGrpGrant.SourceSecurityGroupNames, ", ")))
+					p.change(fmt.Sprintf("[%s %s] - Deauthorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, secGrpGrant.Type, secGrpGrant.IPProtocol, secGrpGrant.FromPort, secGrpGrant.ToPort, strings.Join(secGrpGrant.SourceSecurityGroupNames, ", ")))
 
 					if secGrpGrant.Type == "ingress" {
 						removeIngress = append(removeIngress, secGrpGrant)
@@ -647,7 +719,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 					}
 
 				} else {
-					//terminal.Notice(fmt.Sprintf("[%s %s] - Keeping - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, secGrpGrant.Type, secGrpGrant.IPProtocol, secGrpGrant.FromPort, secGrpGrant.ToPort, strings.Join(secGrpGrant.SourceSecurityGroupNames, ", ")))
+					//p.notice(fmt.Sprintf("[%s %s] - Keeping - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, secGrpGrant.Type, secGrpGrant.IPProtocol, secGrpGrant.FromPort, secGrpGrant.ToPort, strings.Join(secGrpGrant.SourceSecurityGroupNames, ", ")))
 					delete(cfgHashes[i], existingGrantHash)
 				}
 
@@ -662,7 +734,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 
 			// Skip egress rules on non vpc security groups
 			if secGrp.VpcID == "" && grant.Type == "egress" {
-				terminal.Notice(fmt.Sprintf("[%s %s] - Skip - [%s]	[%s :%d-%d]	Egress rules can only be applied to VPC Security Groups", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort))
+				p.notice(fmt.Sprintf("[%s %s] - Skip - [%s]	[%s :%d-%d]	Egress rules can only be applied to VPC Security Groups", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort))
 				continue
 			}
 
@@ -670,7 +742,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 				sGrant.CidrIPs = []string{ipGrant}
 				sGrant.SourceSecurityGroupNames = []string{}
 
-				terminal.Delta(fmt.Sprintf("[%s %s] - Authorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort, strings.Join(sGrant.CidrIPs, ", ")))
+				p.change(fmt.Sprintf("[%s %s] - Authorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort, strings.Join(sGrant.CidrIPs, ", ")))
 
 				if grant.Type == "ingress" {
 					addIngress = append(addIngress, sGrant)
@@ -684,7 +756,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 				sGrant.CidrIPs = []string{}
 				sGrant.SourceSecurityGroupNames = []string{secGrant}
 
-				terminal.Delta(fmt.Sprintf("[%s %s] - Authorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort, strings.Join(sGrant.SourceSecurityGroupNames, ", ")))
+				p.change(fmt.Sprintf("[%s %s] - Authorize - [%s]	[%s :%d-%d]	[%s]", secGrp.Name, secGrp.Region, sGrant.Type, sGrant.IPProtocol, sGrant.FromPort, sGrant.ToPort, strings.Join(sGrant.SourceSecurityGroupNames, ", ")))
 
 				if grant.Type == "ingress" {
 					addIngress = append(addIngress, sGrant)
@@ -731,7 +803,7 @@ func (s SecurityGroups) Diff() ([]SecurityGroupChange, error) {
 		}
 	}
 
-	terminal.Information("Comparison complete!")
+	p.info("Comparison complete!")
 
 	return changes, nil
 
@@ -753,12 +825,16 @@ func (c SecurityGroupChanges) Swap(i, j int) {
 }
 
 // private function without terminal prompts
-func updateSecurityGroups(changes SecurityGroupChanges, dryRun bool) error {
+func updateSecurityGroups(ctx context.Context, changes SecurityGroupChanges, dryRun bool) error {
 
 	// Sort so that we can revoke first
 	sort.Sort(changes)
 
 	for _, change := range changes {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		if change.Type == "ingress" {
 			if change.Revoke {
 				// revoke
@@ -788,11 +864,15 @@ func updateSecurityGroups(changes SecurityGroupChanges, dryRun bool) error {
 					return err
 				}
 			}
+
+		} else if change.Type == "tags" && !dryRun {
+			err := setEc2Tags(aws.String(change.Group.GroupID), change.Tags, change.Group.Region)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	terminal.Information("Done!")
-
 	return nil
 }
 
@@ -874,7 +954,7 @@ func authorizeIngress(secGrp SecurityGroup, grants []config.SecurityGroupGrant,
 
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(secGrp.Region)}))
+	sess := newSession(secGrp.Region)
 	svc := ec2.New(sess)
 	_, err := svc.AuthorizeSecurityGroupIngress(params)
 
@@ -969,7 +1049,7 @@ func authorizeEgress(secGrp SecurityGroup, grants []config.SecurityGroupGrant, d
 
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(secGrp.Region)}))
+	sess := newSession(secGrp.Region)
 	svc := ec2.New(sess)
 	_, err := svc.AuthorizeSecurityGroupEgress(params)
 
@@ -1064,7 +1144,7 @@ func revokeIngress(secGrp SecurityGroup, grants []config.SecurityGroupGrant, dry
 
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(secGrp.Region)}))
+	sess := newSession(secGrp.Region)
 	svc := ec2.New(sess)
 	_, err := svc.RevokeSecurityGroupIngress(params)
 
@@ -1159,7 +1239,7 @@ func revokeEgress(secGrp SecurityGroup, grants []config.SecurityGroupGrant, dryR
 
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(secGrp.Region)}))
+	sess := newSession(secGrp.Region)
 	svc := ec2.New(sess)
 	_, err := svc.RevokeSecurityGroupEgress(params)
 
@@ -1180,7 +1260,7 @@ func revokeEgress(secGrp SecurityGroup, grants []config.SecurityGroupGrant, dryR
 func deleteSecurityGroups(secGrpList *SecurityGroups, dryRun bool) error {
 
 	for _, secGrp := range *secGrpList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(secGrp.Region)}))
+		sess := newSession(secGrp.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteSecurityGroupInput{
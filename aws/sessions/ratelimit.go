@@ -0,0 +1,96 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
+)
+
+// limiter is a token bucket, allowing bursts of requests and then a steady rate
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var (
+	limiters   = make(map[string]*limiter)
+	limitersMu sync.Mutex
+)
+
+// limiterFor returns the rate limiter of a service and region, or nil if requests are not rate limited
+func limiterFor(service, region string) *limiter {
+	env := settings.Current()
+	if env.RateLimit <= 0 {
+		return nil
+	}
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	key := service + "/" + region
+	l, ok := limiters[key]
+	if !ok {
+		burst := float64(env.RateBurst)
+		if burst < 1 {
+			burst = 1
+		}
+		l = &limiter{rate: env.RateLimit, burst: burst, tokens: burst, last: time.Now()}
+		limiters[key] = l
+	}
+
+	return l
+}
+
+// reserve takes a token and returns how long to wait for it
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// rateLimit holds a request until the rate limiter of its service and region allows it, or its context is done
+func rateLimit(r *request.Request) {
+	service := r.ClientInfo.ServiceName
+	region := aws.StringValue(r.Config.Region)
+
+	l := limiterFor(service, region)
+	if l == nil {
+		return
+	}
+
+	delay := l.reserve()
+	if delay == 0 {
+		return
+	}
+
+	logger.Debug("Rate limiting AWS request", "service", service, "operation", r.Operation.Name, "region", region, "delay", delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+		r.Error = awserr.New(request.CanceledErrorCode, "request context canceled", r.Context().Err())
+	}
+}
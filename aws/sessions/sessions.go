@@ -0,0 +1,156 @@
+// Package sessions builds the AWS sessions used by awsm, with the retries, backoff and rate limits of the current
+// environment and the debug logging of the logger package
+package sessions
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
+)
+
+func init() {
+	// Jitter spreads out the retries of concurrent requests
+	rand.Seed(time.Now().UnixNano())
+}
+
+// New returns a new session for an AWS config
+func New(cfg *aws.Config) *session.Session {
+	return NewWithOptions(session.Options{Config: *cfg})
+}
+
+// NewWithOptions returns a new session for session options, like a shared config profile
+func NewWithOptions(opts session.Options) *session.Session {
+	env := settings.Current()
+
+	logger.AWSConfig(&opts.Config)
+	opts.Config.Retryer = retryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: env.MaxRetries},
+		baseDelay:      env.RetryBaseDelay,
+		maxDelay:       env.RetryMaxDelay,
+	}
+
+	sess := session.Must(session.NewSessionWithOptions(opts))
+
+	// Every attempt, including retries, waits its turn with the rate limiter of its service and region
+	sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{Name: "awsm.RateLimit", Fn: rateLimit})
+	sess.Handlers.AfterRetry.PushBackNamed(request.NamedHandler{Name: "awsm.Throttled", Fn: markThrottled})
+
+	return sess
+}
+
+// throttleCodes are the error codes AWS services use to throttle requests
+var throttleCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+	"BandwidthLimitExceeded":                 true,
+	"SlowDown":                               true,
+}
+
+// ThrottleError is returned by AWS requests that were still being throttled once they ran out of retries
+type ThrottleError struct {
+	Err     awserr.Error // the error of the last attempt
+	Service string
+	Region  string
+	Retries int
+}
+
+// Code returns the error code of the last attempt
+func (e *ThrottleError) Code() string {
+	return e.Err.Code()
+}
+
+// Message describes the throttling, so that it stays distinct when only the message is passed on
+func (e *ThrottleError) Message() string {
+	return fmt.Sprintf("Throttled by AWS [%s] in [%s] after %d retries: %s", e.Service, e.Region, e.Retries, e.Err.Message())
+}
+
+// OrigErr returns the error of the last attempt
+func (e *ThrottleError) OrigErr() error {
+	return e.Err
+}
+
+func (e *ThrottleError) Error() string {
+	return e.Code() + ": " + e.Message()
+}
+
+// IsThrottle returns true if an error is AWS throttling a request
+func IsThrottle(err error) bool {
+	switch err := err.(type) {
+	case *ThrottleError:
+		return true
+	case awserr.Error:
+		return throttleCodes[err.Code()]
+	}
+	return false
+}
+
+// markThrottled wraps the final error of a throttled request in a ThrottleError
+func markThrottled(r *request.Request) {
+	if r.Error == nil || r.WillRetry() {
+		return
+	}
+
+	awsErr, ok := r.Error.(awserr.Error)
+	if !ok || !throttleCodes[awsErr.Code()] {
+		return
+	}
+
+	throttleErr := &ThrottleError{
+		Err:     awsErr,
+		Service: r.ClientInfo.ServiceName,
+		Region:  aws.StringValue(r.Config.Region),
+		Retries: r.RetryCount,
+	}
+	logger.Warn("AWS request throttled", "service", throttleErr.Service, "operation", r.Operation.Name, "region", throttleErr.Region, "retries", throttleErr.Retries)
+
+	r.Error = throttleErr
+}
+
+// retryer retries throttled requests along with the ones the SDK would retry, with exponential backoff and full jitter
+type retryer struct {
+	client.DefaultRetryer
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// ShouldRetry returns true if a request should be retried
+func (r retryer) ShouldRetry(req *request.Request) bool {
+	if IsThrottle(req.Error) {
+		return true
+	}
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules returns a random delay of up to the base delay doubled for each retry, capped at the max delay
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	backoff := r.maxDelay
+	if req.RetryCount < 30 && r.baseDelay<<uint(req.RetryCount) < r.maxDelay {
+		backoff = r.baseDelay << uint(req.RetryCount)
+	}
+
+	var delay time.Duration
+	if backoff > 0 {
+		delay = time.Duration(rand.Int63n(int64(backoff)))
+	}
+
+	logger.Debug("Retrying AWS request", "service", req.ClientInfo.ServiceName, "operation", req.Operation.Name, "region", aws.StringValue(req.Config.Region), "retry", req.RetryCount+1, "delay", delay, "error", req.Error)
+
+	return delay
+}
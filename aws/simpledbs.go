@@ -2,18 +2,15 @@ package aws
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"reflect"
 	"regexp"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/simpledb"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -26,35 +23,25 @@ type SimpleDBDomain models.SimpleDBDomain
 
 // GetSimpleDBDomains returns a slice of SimpleDB Domains that match the provided search term
 func GetSimpleDBDomains(search string) (*SimpleDBDomains, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	domainList := new(SimpleDBDomains)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionSimpleDBDomains(*region.RegionName, domainList, search)
-			if err != nil {
-				// TODO handle regions without service endpoints that work
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering simpledb domain list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
 
-	wg.Wait()
+	// TODO handle regions without service endpoints that work
+	errs := collectAccountRegions("simpledb domain", func(account Account, region string) error {
+		return getAccountRegionSimpleDBDomains(account, region, domainList, search)
+	})
 
 	return domainList, errs
 }
 
 // GetRegionSimpleDBDomains returns a slice of a regions SimpleDB Domains into the provided SimpleDBDomains slice
 func GetRegionSimpleDBDomains(region string, domainList *SimpleDBDomains, search string) error {
+	return getAccountRegionSimpleDBDomains(CurrentAccount(), region, domainList, search)
+}
+
+// getAccountRegionSimpleDBDomains returns a slice of the SimpleDB Domains of a region of an account into the provided SimpleDBDomains slice
+func getAccountRegionSimpleDBDomains(account Account, region string, domainList *SimpleDBDomains, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := simpledb.New(sess)
 
 	result, err := svc.ListDomains(nil)
@@ -66,8 +53,9 @@ func GetRegionSimpleDBDomains(region string, domainList *SimpleDBDomains, search
 	for i, domain := range result.DomainNames {
 
 		domains[i] = SimpleDBDomain{
-			Name:   aws.StringValue(domain),
-			Region: region,
+			Account: account.Name,
+			Name:    aws.StringValue(domain),
+			Region:  region,
 		}
 	}
 
@@ -121,7 +109,7 @@ func CreateSimpleDBDomain(domain, region string) error {
 		return errors.New("Region [" + region + "] is Invalid!")
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := simpledb.New(sess)
 
 	params := &simpledb.CreateDomainInput{
@@ -147,7 +135,9 @@ func DeleteSimpleDBDomains(search, region string) (err error) {
 	if region != "" {
 		err = GetRegionSimpleDBDomains(region, domainList, search)
 	} else {
-		domainList, _ = GetSimpleDBDomains(search)
+		var errs []error
+		domainList, errs = GetSimpleDBDomains(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -164,14 +154,14 @@ func DeleteSimpleDBDomains(search, region string) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these SimpleDB Domains?") {
+	if !prompt.Confirm("Are you sure you want to delete these SimpleDB Domains?") {
 		terminal.ErrorLine("Aborting!")
 		return
 	}
 
 	// Delete 'Em
 	for _, domain := range *domainList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(domain.Region)}))
+		sess := newSession(domain.Region)
 		svc := simpledb.New(sess)
 
 		params := &simpledb.DeleteDomainInput{
@@ -1,22 +1,24 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"reflect"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -31,7 +33,7 @@ type Snapshot models.Snapshot
 func GetSnapshotsByTag(region, key, value string, completed bool) (Snapshots, error) {
 	snapList := new(Snapshots)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSnapshotsInput{
@@ -102,25 +104,11 @@ func GetLatestSnapshotByTagMulti(region, key string, value []string) (Snapshots,
 
 // GetSnapshots returns a slice of EBS Snapshots that match the provided search term and optional completed flag
 func GetSnapshots(search string, completed bool) (*Snapshots, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	snapList := new(Snapshots)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionSnapshots(*region.RegionName, snapList, search, completed)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering snapshot list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("snapshot", func(account Account, region string) error {
+		return getAccountRegionSnapshots(account, region, snapList, search, completed)
+	})
 
 	return snapList, errs
 }
@@ -150,8 +138,13 @@ func (s *Snapshot) Marshal(snapshot *ec2.Snapshot, region string) {
 
 // GetRegionSnapshots returns a list of a regions Snapshots into the provided Snapshots slice that match the provided search term and optional completed flag
 func GetRegionSnapshots(region string, snapList *Snapshots, search string, completed bool) error {
+	return getAccountRegionSnapshots(CurrentAccount(), region, snapList, search, completed)
+}
+
+// getAccountRegionSnapshots returns a list of the Snapshots of a region of an account into the provided Snapshots slice that match the provided search term and optional completed flag
+func getAccountRegionSnapshots(account Account, region string, snapList *Snapshots, search string, completed bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeSnapshots(&ec2.DescribeSnapshotsInput{OwnerIds: []*string{aws.String("self")}})
@@ -163,6 +156,7 @@ func GetRegionSnapshots(region string, snapList *Snapshots, search string, compl
 	snap := make(Snapshots, len(result.Snapshots))
 	for i, snapshot := range result.Snapshots {
 		snap[i].Marshal(snapshot, region)
+		snap[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -209,7 +203,8 @@ func CopySnapshot(search, region string, dryRun bool) error {
 	}
 
 	// Get the source snapshot
-	snapshots, _ := GetSnapshots(search, true)
+	snapshots, errs := GetSnapshots(search, true)
+	ShowErrors(errs)
 	snapCount := len(*snapshots)
 	if snapCount == 0 {
 		return errors.New("No available snapshots found for your search terms.")
@@ -221,7 +216,7 @@ func CopySnapshot(search, region string, dryRun bool) error {
 
 	snapshot := (*snapshots)[0]
 
-	_, err := copySnapshot(snapshot, region, dryRun)
+	_, err := copySnapshot(context.Background(), snapshot, region, dryRun, newProgress(TerminalObserver))
 	if err != nil {
 		return err
 	}
@@ -232,9 +227,9 @@ func CopySnapshot(search, region string, dryRun bool) error {
 }
 
 // private function without terminal prompts
-func copySnapshot(snapshot Snapshot, region string, dryRun bool) (string, error) {
+func copySnapshot(ctx context.Context, snapshot Snapshot, region string, dryRun bool, p *progress) (string, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Copy snapshot to the destination region
@@ -250,17 +245,19 @@ func copySnapshot(snapshot Snapshot, region string, dryRun bool) (string, error)
 
 	}
 
-	copySnapResp, err := svc.CopySnapshot(params)
-	newSnapshotId := aws.StringValue(copySnapResp.SnapshotId)
-
+	copySnapResp, err := svc.CopySnapshotWithContext(ctx, params)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return newSnapshotId, errors.New(awsErr.Message())
+			return "", errors.New(awsErr.Message())
 		}
+		return "", err
 	}
 
-	// Add Tags
-	err = SetEc2NameAndClassTags(&newSnapshotId, snapshot.Name, snapshot.Class, region)
+	newSnapshotId := aws.StringValue(copySnapResp.SnapshotId)
+
+	// Add Tags, with the custom tags of the class if it has one
+	snapCfg, _ := config.LoadSnapshotClass(snapshot.Class)
+	err = SetEc2ClassTags(&newSnapshotId, snapshot.Name, snapshot.Class, snapCfg.Tags, region)
 
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
@@ -269,11 +266,43 @@ func copySnapshot(snapshot Snapshot, region string, dryRun bool) (string, error)
 		return newSnapshotId, err
 	}
 
-	terminal.Delta("Created [" + newSnapshotId + "] from [" + snapshot.SnapshotID + "] named [" + snapshot.Name + "] copied from region [" + snapshot.Region + "] to region [" + region + "]!")
+	p.change("Created [" + newSnapshotId + "] from [" + snapshot.SnapshotID + "] named [" + snapshot.Name + "] copied from region [" + snapshot.Region + "] to region [" + region + "]!")
 
 	return newSnapshotId, err
 }
 
+// CreateSnapshotOptions are the options for CreateSnapshotWithContext
+type CreateSnapshotOptions struct {
+	Class  string
+	Search string // Volume search term, overriding the Volume of the Snapshot class
+	Wait   bool   // wait for the Snapshots to complete
+	DryRun bool
+
+	// SetDefaultVolume is called when the Volume is not the default of the Snapshot class, it is saved as the new default if it returns true
+	SetDefaultVolume func(volume Volume) bool
+	// Confirm is called before the Snapshot is created, nothing is created if it returns false
+	Confirm func(volume Volume) bool
+	// SkipCommands is called when the pre/post Snapshot commands can not be sent, the Snapshot is created without them if it returns true
+	SkipCommands func(err error) bool
+
+	Observer Observer
+}
+
+// CreateSnapshotResult is the result of CreateSnapshotWithContext
+type CreateSnapshotResult struct {
+	SnapshotID  string
+	Name        string
+	Region      string
+	VolumeID    string
+	Version     int
+	Copies      map[string]string // Snapshot ids of the propagated copies, by region
+	Invocations CommandInvocations
+	Warnings    []string
+}
+
+// ErrAborted is returned when a confirmation callback declines an operation
+var ErrAborted = errors.New("Aborting!")
+
 // CreateSnapshot creates a new EBS Snapshot
 func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error {
 
@@ -292,52 +321,93 @@ func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error
 		terminal.Information("--force-yes flag is set!")
 	}
 
+	opts := CreateSnapshotOptions{
+		Class:  class,
+		Search: search,
+		Wait:   waitFlag,
+		DryRun: dryRun,
+		SetDefaultVolume: func(volume Volume) bool {
+			volTable := &Volumes{volume}
+			volTable.PrintTable()
+
+			// Save into config prompt
+			return forceYes || prompt.Confirm("Do you want to set volume ["+volume.VolumeID+"] named ["+volume.Name+"] as the new default for the "+class+" snapshot class?")
+		},
+		Confirm: func(volume Volume) bool {
+			return forceYes || prompt.Confirm("Are you sure you want to create this Snapshot?")
+		},
+		SkipCommands: func(err error) bool {
+			terminal.ErrorLine(err.Error() + " No SSM pre/post SnapshotCommands will be run on this instance!")
+
+			// Confirm continue if we can't run them.
+			return prompt.Confirm("Do you want to continue without running any pre/post Snapshot scripts?")
+		},
+		Observer: TerminalObserver,
+	}
+
+	result, err := CreateSnapshotWithContext(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Invocations) > 0 {
+		result.Invocations.PrintOutput()
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// CreateSnapshotWithContext creates a new EBS Snapshot of a class, without any terminal output or prompts
+func CreateSnapshotWithContext(ctx context.Context, opts CreateSnapshotOptions) (*CreateSnapshotResult, error) {
+	p := newProgress(opts.Observer)
+	class, dryRun := opts.Class, opts.DryRun
+
 	// Class Config
 	snapCfg, err := config.LoadSnapshotClass(class)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	terminal.Information("Found Snapshot Class Configuration for [" + class + "]!")
+	p.info("Found Snapshot Class Configuration for [" + class + "]!")
 
 	sourceVolume := snapCfg.Volume
-	if search != "" {
-		sourceVolume = search
-		terminal.Information("Volume search argument passed, looking for Volume matching [" + sourceVolume + "]...")
+	if opts.Search != "" {
+		sourceVolume = opts.Search
+		p.info("Volume search argument passed, looking for Volume matching [" + sourceVolume + "]...")
 	}
 	if sourceVolume == "" {
-		return errors.New("No volume specified in command arguments or Snapshot class config. Please provide the volume search argument or set one in the config.")
+		return nil, errors.New("No volume specified in command arguments or Snapshot class config. Please provide the volume search argument or set one in the config.")
 	}
 
 	// Locate the Volume
-	volumes, _ := GetVolumes(sourceVolume, false)
+	volumes, errs := GetVolumes(sourceVolume, false)
+	p.warnErrors(errs)
 	if len(*volumes) == 0 {
-		return errors.New("No volumes found matching [" + sourceVolume + "], Aborting!")
+		return nil, errors.New("No volumes found matching [" + sourceVolume + "], Aborting!")
 	}
 	if len(*volumes) > 1 {
-		volumes.PrintTable()
-		return errors.New("Found more than one volume matching [" + sourceVolume + "], Aborting!")
+		volumeIds := make([]string, len(*volumes))
+		for i, volume := range *volumes {
+			volumeIds[i] = volume.VolumeID
+		}
+		return nil, errors.New("Found more than one volume matching [" + sourceVolume + "]: [" + strings.Join(volumeIds, ", ") + "], Aborting!")
 	}
 
 	volume := (*volumes)[0]
 	region := volume.Region
 
-	terminal.Information("Found Volume [" + volume.VolumeID + "] named [" + volume.Name + "] in region [" + region + "]!")
+	p.info("Found Volume [" + volume.VolumeID + "] named [" + volume.Name + "] in region [" + region + "]!")
 
-	// Ask to save the new volume id if it doesn't match the snapCfg.Volume
-	if !dryRun && snapCfg.Volume != volume.VolumeID {
-		volTable := &Volumes{volume}
-		volTable.PrintTable()
-
-		// Save into config prompt
-		if forceYes || terminal.PromptBool("Do you want to set volume ["+volume.VolumeID+"] named ["+volume.Name+"] as the new default for the "+class+" snapshot class?") {
-			snapCfg.SetVolume(class, volume.VolumeID)
-		}
+	// Offer to save the new volume id if it doesn't match the snapCfg.Volume
+	if !dryRun && snapCfg.Volume != volume.VolumeID && opts.SetDefaultVolume != nil && opts.SetDefaultVolume(volume) {
+		snapCfg.SetVolume(class, volume.VolumeID)
 	}
 
 	// Confirm
-	if !forceYes && !terminal.PromptBool("Are you sure you want to create this Snapshot?") {
-		return errors.New("Aborting!")
+	if opts.Confirm != nil && !opts.Confirm(volume) {
+		return nil, ErrAborted
 	}
 
 	// Check if we are able to send SSM commands to this instance, if needed
@@ -346,47 +416,60 @@ func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error
 
 	if volume.InstanceID != "" {
 		if snapCfg.PreSnapshotCommand != "" || snapCfg.PostSnapshotCommand != "" {
-			terminal.Information("Snapshot Class [" + class + "] has SSM Commands configured, checking if we are able to send them...")
+			p.info("Snapshot Class [" + class + "] has SSM Commands configured, checking if we are able to send them...")
 
 			ssmInstance, err = GetSSMInstanceById(volume.Region, volume.InstanceID)
 			if err != nil || ssmInstance.InstanceID == "" {
-				terminal.ErrorLine(err.Error() + " No SSM pre/post SnapshotCommands will be run on this instance!")
+				if err == nil {
+					err = errors.New("Unable to find SSM Instance [" + volume.InstanceID + "]!")
+				}
 
-				// Confirm continue if we can't run them.
-				if !terminal.PromptBool("Do you want to continue without running any pre/post Snapshot scripts?") {
-					return errors.New("Aborting!")
+				// Continue without them only if asked to
+				if opts.SkipCommands == nil || !opts.SkipCommands(err) {
+					return nil, ErrAborted
 				}
+				p.warn("No SSM pre/post SnapshotCommands will be run on instance [" + volume.InstanceID + "]!")
 			} else {
 				runCmds = true
-				terminal.Information("Found SSM Instance [" + ssmInstance.InstanceID + "] named [" + ssmInstance.ComputerName + "] and a ping time of [" + humanize.Time(ssmInstance.LastPingDateTime) + "]!")
+				p.info("Found SSM Instance [" + ssmInstance.InstanceID + "] named [" + ssmInstance.ComputerName + "] and a ping time of [" + humanize.Time(ssmInstance.LastPingDateTime) + "]!")
 			}
 		}
 	}
 
 	// Increment the version
-	terminal.Information(fmt.Sprintf("Previous version of snapshot is [%d]", snapCfg.Version))
+	p.info(fmt.Sprintf("Previous version of snapshot is [%d]", snapCfg.Version))
 	if !dryRun {
 		snapCfg.Increment(class)
 	} else {
 		snapCfg.Version++
 	}
-	terminal.Delta(fmt.Sprintf("New version of snapshot is [%d]", snapCfg.Version))
+	p.change(fmt.Sprintf("New version of snapshot is [%d]", snapCfg.Version))
 
 	name := fmt.Sprintf("%s-v%d", class, snapCfg.Version)
 
 	// Create the snapshot
-	newSnapshotId, err := createSnapshot(volume, snapCfg, ssmInstance, runCmds, dryRun)
+	newSnapshotId, invocations, err := createSnapshot(ctx, volume, snapCfg, ssmInstance, runCmds, dryRun, p)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := &CreateSnapshotResult{
+		SnapshotID:  newSnapshotId,
+		Name:        name,
+		Region:      region,
+		VolumeID:    volume.VolumeID,
+		Version:     snapCfg.Version,
+		Copies:      make(map[string]string),
+		Invocations: invocations,
 	}
 
 	// Add Tags
-	err = SetEc2NameAndClassTags(&newSnapshotId, name, class, region)
+	err = SetEc2ClassTags(&newSnapshotId, name, class, snapCfg.Tags, region)
 	if err != nil {
-		return err
+		return result, err
 	}
 
-	terminal.Delta("Created Snapshot [" + newSnapshotId + "] named [" + name + "] in [" + region + "]!")
+	p.change("Created Snapshot [" + newSnapshotId + "] named [" + name + "] in [" + region + "]!")
 
 	sourceSnapshot := Snapshot{Name: name, Class: class, SnapshotID: newSnapshotId, Region: region, Description: snapCfg.Description}
 
@@ -394,19 +477,25 @@ func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error
 	if snapCfg.Propagate && snapCfg.PropagateRegions != nil {
 
 		var wg sync.WaitGroup
+		var mu sync.Mutex
 		var errs []error
 
-		terminal.Notice("Propagate flag is set, waiting for initial snapshot to complete...")
+		p.notice("Propagate flag is set, waiting for initial snapshot to complete...")
 
 		// Wait for the snapshot to complete.
-		err = waitForSnapshot(newSnapshotId, region, dryRun)
+		err = waitForSnapshot(ctx, newSnapshotId, region, dryRun)
 		if err != nil {
-			return err
+			return result, err
 		}
 
 		// Copy to other regions
 		for _, propRegion := range snapCfg.PropagateRegions {
 
+			if !regions.Selected(propRegion) {
+				p.notice("Region [" + propRegion + "] is not selected, skipping propagation!")
+				continue
+			}
+
 			if propRegion != region {
 
 				wg.Add(1)
@@ -414,26 +503,33 @@ func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error
 					defer wg.Done()
 
 					// Copy snapshot to the destination region
-					newSnapshotId, err := copySnapshot(sourceSnapshot, propRegion, dryRun)
+					newSnapshotId, err := copySnapshot(ctx, sourceSnapshot, propRegion, dryRun, p)
 
 					if err != nil {
-						terminal.ShowErrorMessage(fmt.Sprintf("Error propagating snapshot [%s] to region [%s]", sourceSnapshot.SnapshotID, propRegion), err.Error())
+						p.warn(fmt.Sprintf("Error propagating snapshot [%s] to region [%s]: %s", sourceSnapshot.SnapshotID, propRegion, err.Error()))
+						mu.Lock()
 						errs = append(errs, err)
-					} else {
-						// Add Tags
-						SetEc2NameAndClassTags(&newSnapshotId, name, class, propRegion)
-						terminal.Information(fmt.Sprintf("Copied snapshot [%s] to region [%s].", sourceSnapshot.SnapshotID, propRegion))
-
+						mu.Unlock()
+						return
 					}
 
-					if waitFlag {
+					mu.Lock()
+					result.Copies[propRegion] = newSnapshotId
+					mu.Unlock()
+
+					p.info(fmt.Sprintf("Copied snapshot [%s] to region [%s].", sourceSnapshot.SnapshotID, propRegion))
+
+					if opts.Wait {
 						// Wait for the snapshot to complete.
-						terminal.Notice(fmt.Sprintf("Waiting for snapshot [%s] to complete...", newSnapshotId))
-						err = waitForSnapshot(newSnapshotId, propRegion, dryRun)
+						p.notice(fmt.Sprintf("Waiting for snapshot [%s] to complete...", newSnapshotId))
+						err = waitForSnapshot(ctx, newSnapshotId, propRegion, dryRun)
 						if err != nil {
+							mu.Lock()
 							errs = append(errs, err)
+							mu.Unlock()
+							return
 						}
-						terminal.Delta(fmt.Sprintf("Snapshot [%s] in [%s] has completed!", newSnapshotId, propRegion))
+						p.change(fmt.Sprintf("Snapshot [%s] in [%s] has completed!", newSnapshotId, propRegion))
 					}
 
 				}(propRegion)
@@ -443,48 +539,50 @@ func CreateSnapshot(class, search string, waitFlag, forceYes, dryRun bool) error
 		wg.Wait()
 
 		if errs != nil {
-			return errors.New("Error propagating snapshot to other regions!")
+			result.Warnings = p.warnings
+			return result, errors.New("Error propagating snapshot to other regions!")
 		}
 
-	} else if waitFlag {
+	} else if opts.Wait {
 		// Wait for the snapshot to complete here otherwise, maybe
-		terminal.Notice(fmt.Sprintf("Waiting for snapshot [%s] to complete...", newSnapshotId))
-		err = waitForSnapshot(newSnapshotId, region, dryRun)
+		p.notice(fmt.Sprintf("Waiting for snapshot [%s] to complete...", newSnapshotId))
+		err = waitForSnapshot(ctx, newSnapshotId, region, dryRun)
 		if err != nil {
-			return err
+			return result, err
 		}
-		terminal.Delta(fmt.Sprintf("Snapshot [%s] completed!", newSnapshotId))
+		p.change(fmt.Sprintf("Snapshot [%s] completed!", newSnapshotId))
 	}
 
 	// Rotate out older snapshots
 	if snapCfg.Rotate && snapCfg.Retain > 1 {
-		terminal.Notice("Rotate flag is set, looking for snapshots to rotate...")
-		err := rotateSnapshots(class, snapCfg, dryRun)
+		p.notice("Rotate flag is set, looking for snapshots to rotate...")
+		err := rotateSnapshots(class, snapCfg, dryRun, p)
 		if err != nil {
-			terminal.ShowErrorMessage(fmt.Sprintf("Error rotating [%s] snapshots!", sourceSnapshot.Class), err.Error())
-			return err
+			result.Warnings = p.warnings
+			return result, errors.New("Error rotating [" + class + "] snapshots: " + err.Error())
 		}
 	}
 
-	terminal.Information("Done!")
+	result.Warnings = p.warnings
 
-	return nil
+	return result, nil
 }
 
 // private function without terminal prompts
-func createSnapshot(volume Volume, snapCfg config.SnapshotClass, ssmInstance SSMInstance, runCmds, dryRun bool) (string, error) {
+func createSnapshot(ctx context.Context, volume Volume, snapCfg config.SnapshotClass, ssmInstance SSMInstance, runCmds, dryRun bool, p *progress) (string, CommandInvocations, error) {
+	var invocations CommandInvocations
 
 	// Run the Pre-Snapshot Command on the Instance
 	if runCmds && snapCfg.PreSnapshotCommand != "" {
-		terminal.Delta("Running Pre-Snapshot Command...")
-		invocations, err := runCommand(&SSMInstances{ssmInstance}, snapCfg.PreSnapshotCommand, dryRun)
+		p.change("Running Pre-Snapshot Command...")
+		preInvocations, err := runCommand(&SSMInstances{ssmInstance}, snapCfg.PreSnapshotCommand, dryRun, p)
 		if err != nil {
-			return "", err
+			return "", invocations, err
 		}
-		invocations.PrintOutput()
+		invocations = append(invocations, *preInvocations...)
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(volume.Region)}))
+	sess := newSession(volume.Region)
 	svc := ec2.New(sess)
 
 	// Create the Snapshot
@@ -494,44 +592,49 @@ func createSnapshot(volume Volume, snapCfg config.SnapshotClass, ssmInstance SSM
 		Description: aws.String(snapCfg.Description),
 	}
 
-	createSnapshotResp, err := svc.CreateSnapshot(snapshotParams)
+	createSnapshotResp, err := svc.CreateSnapshotWithContext(ctx, snapshotParams)
 
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
-			return "", errors.New(awsErr.Message())
+			return "", invocations, errors.New(awsErr.Message())
 		}
+		return "", invocations, err
 	}
 
 	// Run the Post-Snapshot Command on the Instance
 	if runCmds && snapCfg.PostSnapshotCommand != "" {
-		terminal.Delta("Running Post-Snapshot Command...")
-		invocations, err := runCommand(&SSMInstances{ssmInstance}, snapCfg.PostSnapshotCommand, dryRun)
+		p.change("Running Post-Snapshot Command...")
+		postInvocations, err := runCommand(&SSMInstances{ssmInstance}, snapCfg.PostSnapshotCommand, dryRun, p)
 		if err != nil {
-			return "", err
+			return "", invocations, err
 		}
-		invocations.PrintOutput()
+		invocations = append(invocations, *postInvocations...)
 	}
 
-	return aws.StringValue(createSnapshotResp.SnapshotId), err
+	return aws.StringValue(createSnapshotResp.SnapshotId), invocations, nil
 }
 
 // rotateSnapshots rotates out older Snapshots
-func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool) error {
+func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool, p *progress) error {
 	// Bail early
 	if cfg.Retain <= 0 {
 		return nil
 	}
 
 	var wg sync.WaitGroup
+	var mu sync.Mutex
 	var errs []error
 
 	launchConfigs, err := GetLaunchConfigurations("")
 	if err != nil {
-		return errors.New("Error while retrieving the list of assets to exclude from rotation!")
+		return errors.New("Error while retrieving the list of assets to exclude from rotation: " + err[0].Error())
 	}
 	lockedSnapshots := launchConfigs.LockedSnapshotIds()
 
-	regions := GetRegionListWithoutIgnored()
+	regions, regionErr := GetRegionListWithoutIgnored()
+	if regionErr != nil {
+		return regionErr
+	}
 
 	for _, region := range regions {
 		wg.Add(1)
@@ -542,8 +645,10 @@ func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool) error
 			// Get all the snapshots of this class in this region
 			snapshots, err := GetSnapshotsByTag(*region.RegionName, "Class", class, true)
 			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering snapshot list for region [%s]", *region.RegionName), err.Error())
+				p.warn(fmt.Sprintf("Error gathering snapshot list for region [%s]: %s", *region.RegionName, err.Error()))
+				mu.Lock()
 				errs = append(errs, err)
+				mu.Unlock()
 			}
 
 			var unlockedSnapshots Snapshots
@@ -551,7 +656,7 @@ func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool) error
 			// Exclude the snapshots being used in Launch Configurations
 			for _, snap := range snapshots {
 				if lockedSnapshots[snap.SnapshotID] {
-					terminal.Notice("Snapshot [" + snap.SnapshotID + "] in [" + *region.RegionName + "] named [" + snap.Name + "] is being used in a launch configuration, skipping!")
+					p.notice("Snapshot [" + snap.SnapshotID + "] in [" + *region.RegionName + "] named [" + snap.Name + "] is being used in a launch configuration, skipping!")
 				} else {
 					unlockedSnapshots = append(unlockedSnapshots, snap)
 				}
@@ -561,7 +666,7 @@ func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool) error
 			if len(unlockedSnapshots) > cfg.Retain {
 				sort.Sort(unlockedSnapshots) // important!
 				ds := unlockedSnapshots[cfg.Retain:]
-				deleteSnapshots(&ds, dryRun)
+				deleteSnapshots(&ds, dryRun, p)
 			}
 
 		}(region)
@@ -576,9 +681,9 @@ func rotateSnapshots(class string, cfg config.SnapshotClass, dryRun bool) error
 }
 
 // waitForSnapshot waits for a snapshot to complete
-func waitForSnapshot(snapshotID, region string, dryRun bool) error {
+func waitForSnapshot(ctx context.Context, snapshotID, region string, dryRun bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Wait for the snapshot to complete.
@@ -587,7 +692,7 @@ func waitForSnapshot(snapshotID, region string, dryRun bool) error {
 		DryRun:      aws.Bool(dryRun),
 	}
 
-	err := svc.WaitUntilSnapshotCompleted(waitParams)
+	err := svc.WaitUntilSnapshotCompletedWithContext(ctx, waitParams)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
 			return errors.New(awsErr.Message())
@@ -610,7 +715,9 @@ func DeleteSnapshots(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionSnapshots(region, snapList, search, false)
 	} else {
-		snapList, _ = GetSnapshots(search, false)
+		var errs []error
+		snapList, errs = GetSnapshots(search, false)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -625,12 +732,12 @@ func DeleteSnapshots(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Snapshots?") {
+	if !prompt.Confirm("Are you sure you want to delete these Snapshots?") {
 		return errors.New("Aborting!")
 	}
 
 	// Delete 'Em
-	err = deleteSnapshots(snapList, dryRun)
+	err = deleteSnapshots(snapList, dryRun, newProgress(TerminalObserver))
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
 			return errors.New(awsErr.Message())
@@ -644,9 +751,9 @@ func DeleteSnapshots(search, region string, dryRun bool) (err error) {
 }
 
 // private function without the confirmation terminal prompts
-func deleteSnapshots(snapList *Snapshots, dryRun bool) (err error) {
+func deleteSnapshots(snapList *Snapshots, dryRun bool, p *progress) (err error) {
 	for _, snapshot := range *snapList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(snapshot.Region)}))
+		sess := newSession(snapshot.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteSnapshotInput{
@@ -659,7 +766,7 @@ func deleteSnapshots(snapList *Snapshots, dryRun bool) (err error) {
 			return err
 		}
 
-		terminal.Delta("Deleted Snapshot [" + snapshot.SnapshotID + "] named [" + snapshot.Name + "] in [" + snapshot.Region + "]!")
+		p.change("Deleted Snapshot [" + snapshot.SnapshotID + "] named [" + snapshot.Name + "] in [" + snapshot.Region + "]!")
 	}
 
 	return nil
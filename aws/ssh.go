@@ -0,0 +1,387 @@
+package aws
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/settings"
+	"github.com/murdinc/terminal"
+)
+
+// The lines around the Host entries that sshConfig manages in ~/.ssh/config
+const (
+	sshConfigBegin = "# BEGIN awsm managed hosts, changes inside this block are overwritten by awsm sshConfig"
+	sshConfigEnd   = "# END awsm managed hosts"
+)
+
+// SSHHosts represents a slice of SSH Hosts
+type SSHHosts []SSHHost
+
+// SSHHost represents a single Host entry for an EC2 Instance
+type SSHHost struct {
+	Alias        string
+	HostName     string
+	User         string
+	IdentityFile string
+	ProxyJump    string // the alias of the bastion of the VPC of the Instance
+	Instance     Instance
+}
+
+// GetSSHHosts returns a Host entry for every running Instance that matches the provided search term, named after its
+// Name tag. Instances in a VPC with a bastion are reached through it with their private IP address.
+func GetSSHHosts(search, sshUser string) (SSHHosts, []error) {
+	hosts, errs := getSSHHosts(sshUser)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	matching, err := hosts.matching(search)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return matching, nil
+}
+
+// getSSHHosts returns a Host entry for every running Instance. Aliases and bastions are worked out over all of them,
+// so that they are the same whatever is searched for.
+func getSSHHosts(sshUser string) (SSHHosts, []error) {
+	instList, errs := GetInstances("", true)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if sshUser == "" {
+		sshUser = settings.Current().SSHUser
+	}
+
+	bastions, err := vpcBastions()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	currentUser, _ := user.Current()
+	sshLocation := filepath.Join(currentUser.HomeDir, ".ssh")
+
+	// Instances without a name, or sharing one with another Instance, are told apart by their id
+	names := make(map[string]int)
+	for _, instance := range *instList {
+		names[instance.Name]++
+	}
+
+	hosts := make(SSHHosts, len(*instList))
+	for i, instance := range *instList {
+		host := SSHHost{
+			Alias:    instance.Name,
+			HostName: instance.PublicIP,
+			User:     sshUser,
+			Instance: instance,
+		}
+
+		if instance.Name == "" {
+			host.Alias = instance.InstanceID
+		} else if names[instance.Name] > 1 {
+			host.Alias = instance.Name + "-" + instance.InstanceID
+		}
+
+		if instance.KeyPair != "" {
+			host.IdentityFile = filepath.Join(sshLocation, instance.KeyPair+".pem")
+		}
+
+		hosts[i] = host
+	}
+
+	sort.Sort(hosts)
+
+	// The bastion of a VPC is the Instance in it named after the bastion of its class, by its alias
+	bastionAliases := make(map[string]string)
+	for _, host := range hosts {
+		vpcID := host.Instance.VPCID
+		if bastion := bastions[vpcID]; bastion != "" && host.Instance.Name == bastion && bastionAliases[vpcID] == "" {
+			bastionAliases[vpcID] = host.Alias
+		}
+	}
+
+	for i, host := range hosts {
+		if bastion := bastionAliases[host.Instance.VPCID]; bastion != "" && bastion != host.Alias {
+			hosts[i].ProxyJump = bastion
+			hosts[i].HostName = host.Instance.PrivateIP
+		}
+
+		if hosts[i].HostName == "" {
+			hosts[i].HostName = host.Instance.PrivateIP
+		}
+	}
+
+	return hosts, nil
+}
+
+// matching returns the Hosts of the Instances that match the provided search term
+func (h SSHHosts) matching(search string) (SSHHosts, error) {
+	if search == "" {
+		return h, nil
+	}
+
+	term, err := regexp.Compile(search)
+	if err != nil {
+		return nil, errors.New("Invalid search term [" + search + "]: " + err.Error())
+	}
+
+	var matching SSHHosts
+	for _, host := range h {
+		if host.Instance.matches(term) {
+			matching = append(matching, host)
+		}
+	}
+
+	return matching, nil
+}
+
+// withBastions returns the Hosts along with the bastions they jump through, taken from all Hosts
+func (h SSHHosts) withBastions(all SSHHosts) SSHHosts {
+	included := make(map[string]bool)
+	needed := make(map[string]bool)
+	for _, host := range h {
+		included[host.Alias] = true
+		if host.ProxyJump != "" {
+			needed[host.ProxyJump] = true
+		}
+	}
+
+	hosts := append(SSHHosts{}, h...)
+	for _, host := range all {
+		if needed[host.Alias] && !included[host.Alias] {
+			hosts = append(hosts, host)
+		}
+	}
+
+	sort.Sort(hosts)
+
+	return hosts
+}
+
+// vpcBastions returns the bastion of each VPC with a VPC class that has one, by VPC id
+func vpcBastions() (map[string]string, error) {
+	bastions := make(map[string]string)
+
+	vpcCfgs, err := config.LoadAllVpcClasses()
+	if err != nil {
+		return bastions, err
+	}
+
+	var hasBastion bool
+	for _, vpcCfg := range vpcCfgs {
+		if vpcCfg.Bastion != "" {
+			hasBastion = true
+		}
+	}
+	if !hasBastion {
+		return bastions, nil
+	}
+
+	vpcList, errs := GetVpcs("")
+	if len(errs) > 0 {
+		return bastions, errs[0]
+	}
+
+	for _, vpc := range *vpcList {
+		if vpcCfg, ok := vpcCfgs[vpc.Class]; ok && vpcCfg.Bastion != "" {
+			bastions[vpc.VpcID] = vpcCfg.Bastion
+		}
+	}
+
+	return bastions, nil
+}
+
+func (h SSHHosts) Len() int           { return len(h) }
+func (h SSHHosts) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h SSHHosts) Less(i, j int) bool { return h[i].Alias < h[j].Alias }
+
+// Config returns the ssh config of the Host entries, inside the lines that mark it as managed by awsm
+func (h SSHHosts) Config() string {
+	var b bytes.Buffer
+	b.WriteString(sshConfigBegin + "\n")
+
+	for _, host := range h {
+		b.WriteString("Host " + host.Alias + "\n")
+		b.WriteString("    HostName " + host.HostName + "\n")
+		if host.User != "" {
+			b.WriteString("    User " + host.User + "\n")
+		}
+		if host.IdentityFile != "" {
+			b.WriteString("    IdentityFile " + host.IdentityFile + "\n")
+			b.WriteString("    IdentitiesOnly yes\n")
+		}
+		if host.ProxyJump != "" {
+			b.WriteString("    ProxyJump " + host.ProxyJump + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(sshConfigEnd + "\n")
+	return b.String()
+}
+
+// replaceSSHConfigBlock replaces the block managed by awsm in an ssh config, or appends it if there isn't one yet
+func replaceSSHConfigBlock(current, block string) (string, error) {
+	begin := strings.Index(current, sshConfigBegin)
+	if begin == -1 {
+		if current != "" && !strings.HasSuffix(current, "\n") {
+			current += "\n"
+		}
+		if current != "" {
+			current += "\n"
+		}
+		return current + block, nil
+	}
+
+	end := strings.Index(current[begin:], sshConfigEnd)
+	if end == -1 {
+		return "", errors.New("Found the start of the awsm managed hosts in the ssh config, but not the end [" + sshConfigEnd + "], Aborting!")
+	}
+	end += begin + len(sshConfigEnd)
+	if end < len(current) && current[end] == '\n' {
+		end++
+	}
+
+	return current[:begin] + block + current[end:], nil
+}
+
+// SSHConfig writes a Host entry for every running Instance matching the provided search term to ~/.ssh/config, in a
+// block that is replaced each time
+func SSHConfig(search, sshUser string, dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	all, errs := getSSHHosts(sshUser)
+	if len(errs) > 0 {
+		return errors.New("Error gathering Instance list: " + errs[0].Error())
+	}
+
+	hosts, err := all.matching(search)
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Found [" + strconv.Itoa(len(hosts)) + "] running Instances!")
+
+	// Bastions are written along with the Hosts that jump through them, even if they don't match the search
+	hosts = hosts.withBastions(all)
+
+	currentUser, _ := user.Current()
+	sshLocation := filepath.Join(currentUser.HomeDir, ".ssh")
+	configPath := filepath.Join(sshLocation, "config")
+
+	current, err := ioutil.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated, err := replaceSSHConfigBlock(string(current), hosts.Config())
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		terminal.Notice("SSH Config:\n" + hosts.Config())
+		return nil
+	}
+
+	err = os.MkdirAll(sshLocation, 0700)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(configPath, []byte(updated), 0600)
+	if err != nil {
+		return err
+	}
+
+	terminal.Delta("Wrote [" + strconv.Itoa(len(hosts)) + "] Host entries to [" + configPath + "]")
+
+	return nil
+}
+
+// Connect opens an ssh session to the single running Instance that matches the provided search term
+func Connect(search, sshUser string) error {
+	hosts, errs := getSSHHosts(sshUser)
+	if len(errs) > 0 {
+		return errors.New("Error gathering Instance list: " + errs[0].Error())
+	}
+
+	matching, err := hosts.matching(search)
+	if err != nil {
+		return err
+	}
+
+	switch len(matching) {
+	case 0:
+		return errors.New("No running Instances found matching [" + search + "], Aborting!")
+	case 1:
+	default:
+		instList := make(Instances, len(matching))
+		for i, host := range matching {
+			instList[i] = host.Instance
+		}
+		instList.PrintTable()
+		return errors.New("Found more than one running Instance matching [" + search + "], please narrow your search!")
+	}
+
+	host := matching[0]
+	args := host.args()
+
+	if host.ProxyJump != "" {
+		var bastion *SSHHost
+		for i := range hosts {
+			if hosts[i].Alias == host.ProxyJump {
+				bastion = &hosts[i]
+			}
+		}
+		if bastion == nil {
+			return errors.New("Unable to find the running bastion [" + host.ProxyJump + "] of Instance [" + host.Alias + "]!")
+		}
+
+		terminal.Information("Connecting through bastion [" + bastion.Alias + "] at [" + bastion.HostName + "]")
+		bastionArgs := bastion.args()
+		proxy := append([]string{"ssh"}, bastionArgs[:len(bastionArgs)-1]...)
+		proxy = append(proxy, "-W", "%h:%p", bastionArgs[len(bastionArgs)-1])
+		args = append([]string{"-o", "ProxyCommand=" + strings.Join(proxy, " ")}, args...)
+	}
+
+	terminal.Information("Connecting to [" + host.Alias + "] at [" + host.HostName + "]")
+
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// args returns the arguments for ssh to connect to the Host, ending with the destination
+func (h SSHHost) args() []string {
+	var args []string
+	if h.IdentityFile != "" {
+		args = append(args, "-i", h.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+
+	destination := h.HostName
+	if h.User != "" {
+		destination = h.User + "@" + destination
+	}
+
+	return append(args, destination)
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -11,10 +12,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	humanize "github.com/dustin/go-humanize"
+	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -52,7 +54,7 @@ type CommandPlugin models.CommandPlugin
 // GetSSMInstanceById returns a single SSM Instance by its instance ID and region
 func GetSSMInstanceById(region, id string) (SSMInstance, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ssm.New(sess)
 
 	params := &ssm.DescribeInstanceInformationInput{
@@ -94,6 +96,10 @@ func GetSSMInstances(search string) (*SSMInstances, []error) {
 	ssmInstList := new(SSMInstances)
 
 	for _, region := range ssmRegions {
+		if !regions.Selected(region) {
+			continue
+		}
+
 		wg.Add(1)
 
 		go func(region string) {
@@ -114,7 +120,7 @@ func GetSSMInstances(search string) (*SSMInstances, []error) {
 // GetRegionSSMInstances returns a slice of Instances into the passed Instances slice based on the provided region and search term, and optional running flag
 func GetRegionSSMInstances(region string, ssmInstList *SSMInstances, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ssm.New(sess)
 
 	result, err := svc.DescribeInstanceInformation(&ssm.DescribeInstanceInformationInput{})
@@ -159,6 +165,10 @@ func GetInventory(search string) (*Inventory, []error) {
 	invList := new(Inventory)
 
 	for _, region := range ssmRegions {
+		if !regions.Selected(region) {
+			continue
+		}
+
 		wg.Add(1)
 
 		go func(region string) {
@@ -179,7 +189,7 @@ func GetInventory(search string) (*Inventory, []error) {
 // GetRegionInventory returns a slice of Inventory into the passed Inventories slice based on the provided region and search term, and optional running flag
 func GetRegionInventory(region string, invList *Inventory, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ssm.New(sess)
 
 	result, err := svc.GetInventory(&ssm.GetInventoryInput{})
@@ -225,6 +235,10 @@ func ListCommandInvocations(search string, details bool) (*CommandInvocations, [
 	cmdInvocationsList := new(CommandInvocations)
 
 	for _, region := range ssmRegions {
+		if !regions.Selected(region) {
+			continue
+		}
+
 		wg.Add(1)
 
 		go func(region string) {
@@ -245,7 +259,7 @@ func ListCommandInvocations(search string, details bool) (*CommandInvocations, [
 // GetRegionCommandInvocations returns a slice of Command Invocations into the passed CommandInvocations slice based on the provided region and search term, and optional details flag
 func GetRegionCommandInvocations(region string, cmdInvocationsList *CommandInvocations, search string, details bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ssm.New(sess)
 
 	result, err := svc.ListCommandInvocations(&ssm.ListCommandInvocationsInput{
@@ -288,7 +302,7 @@ func GetRegionCommandInvocations(region string, cmdInvocationsList *CommandInvoc
 // GetRegionCommandInvocationssByCommandID returns a slice of Command Invocations based on the provided region and commandId, and optional details flag
 func GetRegionCommandInvocationsByCommandID(region string, commandId string, details bool) (CommandInvocations, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ssm.New(sess)
 
 	result, err := svc.ListCommandInvocations(&ssm.ListCommandInvocationsInput{
@@ -335,12 +349,12 @@ func RunCommand(search, command string, dryRun bool) (*CommandInvocations, error
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to run the command [" + command + "] on these instances?") {
+	if !prompt.Confirm("Are you sure you want to run the command [" + command + "] on these instances?") {
 		return &CommandInvocations{}, errors.New("Aborting!")
 	}
 
 	// Run Em
-	commandInvocations, err := runCommand(instList, command, dryRun)
+	commandInvocations, err := runCommand(instList, command, dryRun, newProgress(TerminalObserver))
 	if err != nil {
 		return commandInvocations, err
 	}
@@ -351,7 +365,12 @@ func RunCommand(search, command string, dryRun bool) (*CommandInvocations, error
 }
 
 // private function without the confirmation terminal prompts
-func runCommand(instList *SSMInstances, command string, dryRun bool) (*CommandInvocations, error) {
+func runCommand(instList *SSMInstances, command string, dryRun bool, p *progress) (*CommandInvocations, error) {
+	return runCommandWithContext(context.Background(), instList, command, dryRun, p)
+}
+
+// runCommandWithContext is runCommand, giving up on waiting for responses once the context is done
+func runCommandWithContext(ctx context.Context, instList *SSMInstances, command string, dryRun bool, p *progress) (*CommandInvocations, error) {
 
 	regionInstanceIds := make(map[string][]string)
 	regionInstanceNames := make(map[string][]string)
@@ -373,11 +392,11 @@ func runCommand(instList *SSMInstances, command string, dryRun bool) (*CommandIn
 	for region, instanceIds := range regionInstanceIds {
 		wg.Add(1)
 
-		terminal.Delta("Sending Command [" + command + "] to instances [" + strings.Join(regionInstanceNames[region], ", ") + "] in [" + region + "]!")
+		p.change("Sending Command [" + command + "] to instances [" + strings.Join(regionInstanceNames[region], ", ") + "] in [" + region + "]!")
 
 		go func(region string, instanceIds []string, command string) {
 			defer wg.Done()
-			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+			sess := newSession(region)
 			svc := ssm.New(sess)
 
 			params := &ssm.SendCommandInput{
@@ -392,12 +411,12 @@ func runCommand(instList *SSMInstances, command string, dryRun bool) (*CommandIn
 				Comment: aws.String("awsm sendCommand: " + command),
 			}
 
-			resp, err := svc.SendCommand(params)
+			resp, err := svc.SendCommandWithContext(ctx, params)
 			if err != nil {
-				terminal.ErrorLine(err.Error())
+				p.warn(err.Error())
 				return
 			} else {
-				terminal.Information("Sent Command [" + command + "] [" + aws.StringValue(resp.Command.CommandId) + "] to instances [" + strings.Join(regionInstanceNames[region], ", ") + "] in [" + region + "]!")
+				p.info("Sent Command [" + command + "] [" + aws.StringValue(resp.Command.CommandId) + "] to instances [" + strings.Join(regionInstanceNames[region], ", ") + "] in [" + region + "]!")
 			}
 
 			targetCount := int(aws.Int64Value(resp.Command.TargetCount))
@@ -405,15 +424,21 @@ func runCommand(instList *SSMInstances, command string, dryRun bool) (*CommandIn
 			for {
 				cmdInvocations, err := GetRegionCommandInvocationsByCommandID(region, aws.StringValue(resp.Command.CommandId), true)
 				if err != nil {
-					terminal.ErrorLine(err.Error())
+					p.warn(err.Error())
 					break
 				}
 
 				if len(cmdInvocations) != targetCount || !cmdInvocations.Finished() {
-					terminal.Notice("Waiting for response from [" + region + "]..")
-					time.Sleep(time.Second * 10)
+					p.notice("Waiting for response from [" + region + "]..")
+					select {
+					case <-time.After(time.Second * 10):
+					case <-ctx.Done():
+						p.warn("Gave up waiting for a response from [" + region + "]: " + ctx.Err().Error())
+						*cmdInvocationsCombined = append(*cmdInvocationsCombined, cmdInvocations...)
+						return
+					}
 				} else {
-					terminal.Information("Recieved a response from [" + region + "]!")
+					p.info("Recieved a response from [" + region + "]!")
 					*cmdInvocationsCombined = append(*cmdInvocationsCombined, cmdInvocations...)
 					break
 				}
@@ -497,7 +522,7 @@ func DeregisterInstances(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to deregister these Instances?") {
+	if !prompt.Confirm("Are you sure you want to deregister these Instances?") {
 		return errors.New("Aborting!")
 	}
 
@@ -516,7 +541,7 @@ func DeregisterInstances(search, region string, dryRun bool) (err error) {
 func deregisterInstances(invList *Inventory, dryRun bool) (err error) {
 	if !dryRun {
 		for _, entity := range *invList {
-			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(entity.Region)}))
+			sess := newSession(entity.Region)
 			svc := ssm.New(sess)
 
 			params := &ssm.DeregisterManagedInstanceInput{
@@ -2,19 +2,17 @@ package aws
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"reflect"
 	"regexp"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/cli"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
@@ -59,7 +57,7 @@ func (s *Subnets) GetSubnetClasses(ids []string) []string {
 // GetSubnetByTag returns a single Subnet given the provided region and Tag key/value
 func GetSubnetByTag(region, key, value string) (Subnet, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSubnetsInput{
@@ -98,25 +96,11 @@ func GetSubnetByTag(region, key, value string) (Subnet, error) {
 
 // GetSubnets returns a slice of Subnets that match the provided search term
 func GetSubnets(search string) (*Subnets, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	subList := new(Subnets)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionSubnets(*region.RegionName, subList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering subnet list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("subnet", func(account Account, region string) error {
+		return getAccountRegionSubnets(account, region, subList, search)
+	})
 
 	return subList, errs
 }
@@ -139,8 +123,13 @@ func (s *Subnet) Marshal(subnet *ec2.Subnet, region string, vpcList *Vpcs) {
 
 // GetRegionSubnets returns a list of Subnets of a region into the provided Subnets slice
 func GetRegionSubnets(region string, subList *Subnets, search string) error {
+	return getAccountRegionSubnets(CurrentAccount(), region, subList, search)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionSubnets returns a list of Subnets of a region of an account into the provided Subnets slice
+func getAccountRegionSubnets(account Account, region string, subList *Subnets, search string) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeSubnets(&ec2.DescribeSubnetsInput{})
@@ -150,11 +139,12 @@ func GetRegionSubnets(region string, subList *Subnets, search string) error {
 	}
 
 	vpcList := new(Vpcs)
-	GetRegionVpcs(region, vpcList, "")
+	getAccountRegionVpcs(account, region, vpcList, "")
 
 	subs := make(Subnets, len(result.Subnets))
 	for i, subnet := range result.Subnets {
 		subs[i].Marshal(subnet, region, vpcList)
+		subs[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -182,7 +172,7 @@ func GetRegionSubnets(region string, subList *Subnets, search string) error {
 // GetSubnetsByVpcID returns a slice of Subnets that belong to the provided VPC ID
 func GetSubnetsByVpcID(vpcID string, region string) (Subnets, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSubnetsInput{
@@ -264,7 +254,8 @@ func CreateSubnet(class, name, vpcSearch, ip, az string, dryRun bool) error {
 	terminal.Information("Found Subnet Class Configuration for [" + class + "]!")
 
 	// Verify the VPC input
-	vpcs, _ := GetVpcs(vpcSearch)
+	vpcs, errs := GetVpcs(vpcSearch)
+	ShowErrors(errs)
 	vpcCount := len(*vpcs)
 	if vpcCount == 0 {
 		return errors.New("No VPCs found for your search terms.")
@@ -297,7 +288,7 @@ func CreateSubnet(class, name, vpcSearch, ip, az string, dryRun bool) error {
 	}
 
 	// Create the Subnet
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(vpc.Region)}))
+	sess := newSession(vpc.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.CreateSubnetInput{
@@ -336,7 +327,7 @@ func CreateSubnet(class, name, vpcSearch, ip, az string, dryRun bool) error {
 	terminal.Delta("Adding Subnet Tags...")
 
 	// Add Tags
-	err = SetEc2NameAndClassTags(createSubnetResp.Subnet.SubnetId, name, class, vpc.Region)
+	err = SetEc2ClassTags(createSubnetResp.Subnet.SubnetId, name, class, cfg.Tags, vpc.Region)
 	if err != nil {
 		return err
 	}
@@ -466,7 +457,9 @@ func DeleteSubnets(name, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionSubnets(region, subnetList, name)
 	} else {
-		subnetList, _ = GetSubnets(name)
+		var errs []error
+		subnetList, errs = GetSubnets(name)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -481,7 +474,7 @@ func DeleteSubnets(name, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Subnets?") {
+	if !prompt.Confirm("Are you sure you want to delete these Subnets?") {
 		return errors.New("Aborting!")
 	}
 
@@ -499,7 +492,7 @@ func DeleteSubnets(name, region string, dryRun bool) (err error) {
 // private function without the confirmation terminal prompts
 func deleteSubnets(subnetList *Subnets, dryRun bool) (err error) {
 	for _, subnet := range *subnetList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(subnet.Region)}))
+		sess := newSession(subnet.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteSubnetInput{
@@ -2,10 +2,11 @@ package aws
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
@@ -38,26 +39,129 @@ func GetTagValue(key string, tags interface{}) string {
 	return ""
 }
 
+// GetTagMap returns the tags of an asset as a map of keys to values
+func GetTagMap(tags interface{}) map[string]string {
+	tagMap := make(map[string]string)
+
+	switch v := tags.(type) {
+	case []*ec2.Tag:
+		for _, tag := range v {
+			tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	case []*elb.Tag:
+		for _, tag := range v {
+			tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	case []*autoscaling.TagDescription:
+		for _, tag := range v {
+			tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+
+	return tagMap
+}
+
+// mergeTags merges tag maps in order, later maps overriding the keys of earlier ones
+func mergeTags(tagMaps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, tags := range tagMaps {
+		for key, value := range tags {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// missingTags returns the tags that an asset doesn't have yet, or has with a different value
+func missingTags(current, tags map[string]string) map[string]string {
+	missing := make(map[string]string)
+	for key, value := range tags {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			missing[key] = value
+		}
+	}
+	return missing
+}
+
+// sortedTagKeys returns the keys of a tag map in order
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatTags formats a tag map for display, eg: [team=web, environment=prod]
+func formatTags(tags map[string]string) string {
+	var pairs []string
+	for _, key := range sortedTagKeys(tags) {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	return "[" + strings.Join(pairs, ", ") + "]"
+}
+
+// ec2Tags converts a tag map to EC2 tags
+func ec2Tags(tags map[string]string) []*ec2.Tag {
+	var list []*ec2.Tag
+	for _, key := range sortedTagKeys(tags) {
+		list = append(list, &ec2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+	return list
+}
+
+// elbTags converts a tag map to Load Balancer tags
+func elbTags(tags map[string]string) []*elb.Tag {
+	var list []*elb.Tag
+	for _, key := range sortedTagKeys(tags) {
+		list = append(list, &elb.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+	return list
+}
+
+// autoscalingTags converts a tag map to the tags of an AutoScale Group, propagated to the Instances it launches
+func autoscalingTags(asgName string, tags map[string]string) []*autoscaling.Tag {
+	var list []*autoscaling.Tag
+	for _, key := range sortedTagKeys(tags) {
+		list = append(list, &autoscaling.Tag{
+			Key:               aws.String(key),
+			PropagateAtLaunch: aws.Bool(true),
+			ResourceId:        aws.String(asgName),
+			ResourceType:      aws.String("auto-scaling-group"),
+			Value:             aws.String(tags[key]),
+		})
+	}
+	return list
+}
+
 // SetEc2NameAndClassTags sets the Name and Class tags of an EC2 asset
 func SetEc2NameAndClassTags(resource *string, name, class, region string) error {
+	return SetEc2ClassTags(resource, name, class, nil, region)
+}
+
+// SetEc2ClassTags sets the Name and Class tags of an EC2 asset, along with the custom tags of its class
+func SetEc2ClassTags(resource *string, name, class string, tags map[string]string, region string) error {
+	return setEc2Tags(resource, mergeTags(tags, map[string]string{"Name": name, "Class": class}), region)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// setEc2Tags sets the tags of an EC2 asset
+func setEc2Tags(resource *string, tags map[string]string, region string) error {
+
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.CreateTagsInput{
 		Resources: []*string{
 			resource,
 		},
-		Tags: []*ec2.Tag{
-			{
-				Key:   aws.String("Name"),
-				Value: aws.String(name),
-			},
-			{
-				Key:   aws.String("Class"),
-				Value: aws.String(class),
-			},
-		},
+		Tags: ec2Tags(tags),
 	}
 	_, err := svc.CreateTags(params)
 
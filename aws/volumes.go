@@ -7,16 +7,15 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/cli"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
@@ -31,7 +30,7 @@ type Volume models.Volume
 // GetVolumesByInstanceID returns a list of EBS Volumes given an instance Id
 func GetVolumesByInstanceID(region, instanceId string) (Volumes, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVolumesInput{
@@ -65,7 +64,7 @@ func GetVolumesByInstanceID(region, instanceId string) (Volumes, error) {
 // GetVolumeById returns a single EBS Volume given a region and volume id
 func GetVolumeById(region, volumeId string) (Volume, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVolumesInput{
@@ -106,7 +105,7 @@ func GetVolumeById(region, volumeId string) (Volume, error) {
 // GetVolumeByTag returns a single EBS Volume given a region and Tag key/value
 func GetVolumeByTag(region, key, value string) (Volume, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVolumesInput{
@@ -154,7 +153,7 @@ func GetVolumeByTag(region, key, value string) (Volume, error) {
 // GetVolumeByInstanceIDandTag returns a list of EBS Volumes given an Instance Id and tag pair
 func GetVolumeByInstanceIDSearch(region, instanceId, search string) (*Volumes, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVolumesInput{
@@ -204,33 +203,24 @@ Loop:
 
 // GetVolumes returns a slice of Volumes that match the provided search term and optional available flag
 func GetVolumes(search string, available bool) (*Volumes, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	volList := new(Volumes)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionVolumes(*region.RegionName, volList, search, available)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering volume list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("volume", func(account Account, region string) error {
+		return getAccountRegionVolumes(account, region, volList, search, available)
+	})
 
 	return volList, errs
 }
 
 // GetRegionVolumes returns a slice of region Volumes into the provided Volumes slice that matches the provided region and search, and optional available flag
 func GetRegionVolumes(region string, volList *Volumes, search string, available bool) error {
+	return getAccountRegionVolumes(CurrentAccount(), region, volList, search, available)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionVolumes returns a slice of region Volumes into the provided Volumes slice that matches the provided region of an account and search, and optional available flag
+func getAccountRegionVolumes(account Account, region string, volList *Volumes, search string, available bool) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeVolumes(&ec2.DescribeVolumesInput{})
@@ -239,11 +229,12 @@ func GetRegionVolumes(region string, volList *Volumes, search string, available
 	}
 
 	instList := new(Instances)
-	GetRegionInstances(region, instList, "", false)
+	getAccountRegionInstances(account, region, instList, "", false)
 
 	vol := make(Volumes, len(result.Volumes))
 	for i, volume := range result.Volumes {
 		vol[i].Marshal(volume, region, instList)
+		vol[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -284,7 +275,8 @@ func RefreshVolume(volumeSearch, instanceSearch string, force, dryRun bool) erro
 	}
 
 	// Get the instance
-	instances, _ := GetInstances(instanceSearch, true)
+	instances, errs := GetInstances(instanceSearch, true)
+	ShowErrors(errs)
 	instCount := len(*instances)
 	if instCount == 0 {
 		return errors.New("No instances found for search term.")
@@ -322,7 +314,7 @@ func RefreshVolume(volumeSearch, instanceSearch string, force, dryRun bool) erro
 	volList.PrintTable()
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to refresh this Volume?") {
+	if !prompt.Confirm("Are you sure you want to refresh this Volume?") {
 		return errors.New("Aborting!")
 	}
 
@@ -343,7 +335,7 @@ func RefreshVolume(volumeSearch, instanceSearch string, force, dryRun bool) erro
 			terminal.ErrorLine(err.Error() + " No attach/detach SSM Commands will be run on this instance!")
 
 			// Confirm continue if we can't run them.
-			if !terminal.PromptBool("Do you want to continue without running any attach/detach scripts?") {
+			if !prompt.Confirm("Do you want to continue without running any attach/detach scripts?") {
 				return errors.New("Aborting!")
 			}
 		} else {
@@ -424,7 +416,8 @@ func DetachVolume(volumeSearch, instanceSearch string, force, dryRun bool) error
 	}
 
 	// Get the instance
-	instances, _ := GetInstances(instanceSearch, true)
+	instances, errs := GetInstances(instanceSearch, true)
+	ShowErrors(errs)
 	instCount := len(*instances)
 	if instCount == 0 {
 		return errors.New("No instances found for search term.")
@@ -466,7 +459,7 @@ func DetachVolume(volumeSearch, instanceSearch string, force, dryRun bool) error
 	volList.PrintTable()
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to detatch this Volume?") {
+	if !prompt.Confirm("Are you sure you want to detatch this Volume?") {
 		return errors.New("Aborting!")
 	}
 
@@ -481,7 +474,7 @@ func DetachVolume(volumeSearch, instanceSearch string, force, dryRun bool) error
 			terminal.ErrorLine(err.Error() + " No attach/detach SSM Commands will be run on this instance!")
 
 			// Confirm continue if we can't run them.
-			if !terminal.PromptBool("Do you want to continue without running any attach/detach scripts?") {
+			if !prompt.Confirm("Do you want to continue without running any attach/detach scripts?") {
 				return errors.New("Aborting!")
 			}
 		} else {
@@ -507,14 +500,14 @@ func detachVolume(volume Volume, volCfg config.VolumeClass, ssmInstance SSMInsta
 	// Run the Detach Command on the Instance
 	if runCmd && volCfg.DetachCommand != "" {
 		terminal.Delta("Running Detach Command...")
-		invocations, err := runCommand(&SSMInstances{ssmInstance}, volCfg.DetachCommand, dryRun)
+		invocations, err := runCommand(&SSMInstances{ssmInstance}, volCfg.DetachCommand, dryRun, newProgress(TerminalObserver))
 		if err != nil {
 			return err
 		}
 		invocations.PrintOutput()
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(volume.Region)}))
+	sess := newSession(volume.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DetachVolumeInput{
@@ -554,7 +547,8 @@ func AttachVolume(volumeSearch, instanceSearch string, dryRun bool) error {
 	}
 
 	// Get the instance
-	instances, _ := GetInstances(instanceSearch, true)
+	instances, errs := GetInstances(instanceSearch, true)
+	ShowErrors(errs)
 	instCount := len(*instances)
 	if instCount == 0 {
 		return errors.New("No instances found for search term.")
@@ -598,7 +592,7 @@ func AttachVolume(volumeSearch, instanceSearch string, dryRun bool) error {
 	volList.PrintTable()
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to attach this Volume?") {
+	if !prompt.Confirm("Are you sure you want to attach this Volume?") {
 		return errors.New("Aborting!")
 	}
 
@@ -613,7 +607,7 @@ func AttachVolume(volumeSearch, instanceSearch string, dryRun bool) error {
 			terminal.ErrorLine(err.Error() + " No SSM Attach Command will be run on this instance!")
 
 			// Confirm continue if we can't run it.
-			if !terminal.PromptBool("Do you want to continue without running any attach commands?") {
+			if !prompt.Confirm("Do you want to continue without running any attach commands?") {
 				return errors.New("Aborting!")
 			}
 		} else {
@@ -635,7 +629,7 @@ func AttachVolume(volumeSearch, instanceSearch string, dryRun bool) error {
 // Private function without the confirmation terminal prompts
 func attachVolume(volume Volume, volCfg config.VolumeClass, instance Instance, ssmInstance SSMInstance, runCmd, dryRun bool) (err error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(volume.Region)}))
+	sess := newSession(volume.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.AttachVolumeInput{
@@ -660,7 +654,7 @@ func attachVolume(volume Volume, volCfg config.VolumeClass, instance Instance, s
 	// Run the Attach Command on the Instance
 	if runCmd && volCfg.AttachCommand != "" {
 		terminal.Delta("Running Attach Command...")
-		invocations, err := runCommand(&SSMInstances{ssmInstance}, volCfg.AttachCommand, dryRun)
+		invocations, err := runCommand(&SSMInstances{ssmInstance}, volCfg.AttachCommand, dryRun, newProgress(TerminalObserver))
 		if err != nil {
 			return err
 		}
@@ -670,8 +664,8 @@ func attachVolume(volume Volume, volCfg config.VolumeClass, instance Instance, s
 	return nil
 }
 
-// CreateVolume creates a new EBS Volume
-func CreateVolume(class, name, az string, dryRun bool) error {
+// CreateVolume creates a new EBS Volume. The Availability Zone is picked in the region (or the default region) if not given.
+func CreateVolume(class, name, az, region string, dryRun bool) error {
 
 	// --dry-run flag
 	if dryRun {
@@ -686,6 +680,19 @@ func CreateVolume(class, name, az string, dryRun bool) error {
 
 	terminal.Information("Found Volume Class Configuration for [" + class + "]!")
 
+	// Pick an Availability Zone if we weren't given one
+	if az == "" {
+		region, err = launchRegion(region, nil)
+		if err != nil {
+			return err
+		}
+
+		az, err = volumeAZ(newProgress(TerminalObserver), class, region, name)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Verify the az input
 	azs, errs := regions.GetAZs()
 	if errs != nil {
@@ -697,7 +704,7 @@ func CreateVolume(class, name, az string, dryRun bool) error {
 
 	terminal.Information("Found Availability Zone [" + az + "]!")
 
-	region := azs.GetRegion(az)
+	region = azs.GetRegion(az)
 
 	// Get the latest snapshot
 	latestSnapshot, err := GetLatestSnapshotByTag(region, "Class", volCfg.Snapshot)
@@ -708,7 +715,7 @@ func CreateVolume(class, name, az string, dryRun bool) error {
 	terminal.Information("Found Snapshot [" + latestSnapshot.SnapshotID + "] named [" + latestSnapshot.Name + "] with a class of [" + latestSnapshot.Class + "] created [" + humanize.Time(latestSnapshot.StartTime) + "]!")
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to create this Volume?") {
+	if !prompt.Confirm("Are you sure you want to create this Volume?") {
 		return errors.New("Aborting!")
 	}
 
@@ -722,10 +729,53 @@ func CreateVolume(class, name, az string, dryRun bool) error {
 
 }
 
+// volumeAZ picks the available Availability Zone of a region with the fewest Volumes of a class for a new Volume, with
+// the same policy as Instances, see spreadAZs
+func volumeAZ(p *progress, class, region, name string) (string, error) {
+	azs, errs := regions.GetAZs()
+	if len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	var azList []string
+	for _, az := range *azs {
+		if az.Region == region && az.State == "available" {
+			azList = append(azList, az.Name)
+		}
+	}
+	if len(azList) == 0 {
+		return "", errors.New("No Availability Zones found in region [" + region + "]!")
+	}
+	sort.Strings(azList)
+
+	svc := ec2.New(newSession(region))
+	resp, err := svc.DescribeVolumes(&ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:Class"),
+				Values: []*string{aws.String(class)},
+			},
+		},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return "", errors.New(awsErr.Message())
+		}
+		return "", err
+	}
+
+	inAZ := make(map[string]int)
+	for _, volume := range resp.Volumes {
+		inAZ[aws.StringValue(volume.AvailabilityZone)]++
+	}
+
+	return spreadAZs(p, azList, inAZ, []string{name}, "Volumes", class)[0], nil
+}
+
 // Private function without the confirmation terminal prompts
 func createVolume(name, class, az string, volCfg config.VolumeClass, latestSnapshot Snapshot, dryRun bool) (Volume, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(latestSnapshot.Region)}))
+	sess := newSession(latestSnapshot.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.CreateVolumeInput{
@@ -766,7 +816,7 @@ func createVolume(name, class, az string, volCfg config.VolumeClass, latestSnaps
 	terminal.Delta("Adding EBS Tags...")
 
 	// Add Tags
-	err = SetEc2NameAndClassTags(&newVolumeId, name, class, latestSnapshot.Region)
+	err = SetEc2ClassTags(&newVolumeId, name, class, volCfg.Tags, latestSnapshot.Region)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
 			return Volume{}, errors.New(awsErr.Message())
@@ -781,7 +831,7 @@ func createVolume(name, class, az string, volCfg config.VolumeClass, latestSnaps
 // waitForVolume waits for a Volume to complete being created
 func waitForVolume(volumeID, region string, dryRun bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Wait for the snapshot to complete.
@@ -813,7 +863,9 @@ func DeleteVolumes(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionVolumes(region, volList, search, true)
 	} else {
-		volList, _ = GetVolumes(search, true)
+		var errs []error
+		volList, errs = GetVolumes(search, true)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -828,7 +880,7 @@ func DeleteVolumes(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these Volumes?") {
+	if !prompt.Confirm("Are you sure you want to delete these Volumes?") {
 		return errors.New("Aborting!")
 	}
 
@@ -849,7 +901,7 @@ func DeleteVolumes(search, region string, dryRun bool) (err error) {
 // Private function without the confirmation terminal prompts
 func deleteVolumes(volList *Volumes, dryRun bool) (err error) {
 	for _, volume := range *volList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(volume.Region)}))
+		sess := newSession(volume.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteVolumeInput{
@@ -2,19 +2,17 @@ package aws
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"reflect"
 	"regexp"
-	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
 	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/prompt"
 	"github.com/murdinc/terminal"
 	"github.com/olekukonko/tablewriter"
 )
@@ -46,7 +44,7 @@ type RouteTableAssociation models.RouteTableAssociation
 // GetRegionVpcByTag returns a single VPC that matches the provided region and Tag key/value
 func GetRegionVpcByTag(region, key, value string) (Vpc, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVpcsInput{
@@ -82,7 +80,7 @@ func GetRegionVpcByTag(region, key, value string) (Vpc, error) {
 // GetVpcByTag returns a single VPC that matches the provided and Tag key/value
 func GetVpcByTag(region, key, value string) (Vpc, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeVpcsInput{
@@ -118,7 +116,7 @@ func GetVpcByTag(region, key, value string) (Vpc, error) {
 // GetVpcSecurityGroupByTag returns a VPC Security Group that matches the provided Tag key/value
 func (v *Vpc) GetVpcSecurityGroupByTag(key, value string) (SecurityGroup, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(v.Region)}))
+	sess := newSession(v.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSecurityGroupsInput{
@@ -175,7 +173,7 @@ func (v *Vpc) GetVpcSecurityGroupByTagMulti(key string, value []string) (Securit
 // GetVpcSubnetByTag Gets a single VPC Subnet that matches the provided Tag key/value
 func (v *Vpc) GetVpcSubnetByTag(key, value string) (Subnet, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(v.Region)}))
+	sess := newSession(v.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DescribeSubnetsInput{
@@ -215,27 +213,54 @@ func (v *Vpc) GetVpcSubnetByTag(key, value string) (Subnet, error) {
 	return Subnet{}, errors.New("Please limit your request to return only one Subnet")
 }
 
-// GetVpcs returns a slice of VPCs that match the provided search term
-func GetVpcs(search string) (*Vpcs, []error) {
-	var wg sync.WaitGroup
-	var errs []error
+// GetVpcSubnetsByTag returns all of the Subnets of a VPC that match the provided Tag key/value, one per Availability Zone for a Subnet class
+func (v *Vpc) GetVpcSubnetsByTag(key, value string) (Subnets, error) {
 
-	vpcList := new(Vpcs)
-	regions := GetRegionListWithoutIgnored()
+	sess := newSession(v.Region)
+	svc := ec2.New(sess)
+
+	params := &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name: aws.String("tag:" + key),
+				Values: []*string{
+					aws.String(value),
+				},
+			},
+			{
+				Name: aws.String("vpc-id"),
+				Values: []*string{
+					aws.String(v.VpcID),
+				},
+			},
+		},
+	}
 
-	for _, region := range regions {
-		wg.Add(1)
+	result, err := svc.DescribeSubnets(params)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionVpcs(*region.RegionName, vpcList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering vpc list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
+	if err != nil {
+		return Subnets{}, err
 	}
-	wg.Wait()
+
+	if len(result.Subnets) == 0 {
+		return Subnets{}, errors.New("No Subnet found with [" + key + "] of [" + value + "] in [" + v.Region + "] VPC [" + v.Name + "], Aborting!")
+	}
+
+	subList := make(Subnets, len(result.Subnets))
+	for i, subnet := range result.Subnets {
+		subList[i].Marshal(subnet, v.Region, &Vpcs{*v})
+	}
+
+	return subList, nil
+}
+
+// GetVpcs returns a slice of VPCs that match the provided search term
+func GetVpcs(search string) (*Vpcs, []error) {
+	vpcList := new(Vpcs)
+
+	errs := collectAccountRegions("vpc", func(account Account, region string) error {
+		return getAccountRegionVpcs(account, region, vpcList, search)
+	})
 
 	return vpcList, errs
 }
@@ -249,6 +274,7 @@ func AttachInternetGateway(gatewaySearch, vpcSearch string, dryRun bool) error {
 	// Get the Internet Gateway
 	gatewayList, errs := GetInternetGateways(gatewaySearch, true)
 	if errs != nil {
+		ShowErrors(errs)
 		return errors.New("Error while trying to find the internet gateway!")
 	}
 
@@ -283,7 +309,7 @@ func AttachInternetGateway(gatewaySearch, vpcSearch string, dryRun bool) error {
 	terminal.Information("Found VPC [" + vpc.VpcID + "] named [" + vpc.Name + "] with a class of [" + vpc.Class + "] in [" + vpc.Region + "]!")
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to attach this Internet Gateway to this VPC?") {
+	if !prompt.Confirm("Are you sure you want to attach this Internet Gateway to this VPC?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -300,7 +326,7 @@ func AttachInternetGateway(gatewaySearch, vpcSearch string, dryRun bool) error {
 }
 
 func attachInternetGateway(vpcId, internetGatewayId, region string, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.AttachInternetGatewayInput{
@@ -326,7 +352,8 @@ func AssociateRouteTable(routeTableSearch, subnetSearch string, dryRun bool) err
 	}
 
 	// Get the Subnet
-	subnetList, _ := GetSubnets(subnetSearch)
+	subnetList, errs := GetSubnets(subnetSearch)
+	ShowErrors(errs)
 	subCount := len(*subnetList)
 	if subCount == 0 {
 		return errors.New("No Subnets found for your search terms.")
@@ -359,7 +386,7 @@ func AssociateRouteTable(routeTableSearch, subnetSearch string, dryRun bool) err
 	terminal.Information("Found Route Table [" + rt.RouteTableID + "] named[" + rt.Name + "] in [" + rt.Region + "]!")
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to associate this Route Table to this Subnet?") {
+	if !prompt.Confirm("Are you sure you want to associate this Route Table to this Subnet?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -373,7 +400,7 @@ func AssociateRouteTable(routeTableSearch, subnetSearch string, dryRun bool) err
 }
 
 func associateRouteTable(rtId, subnetId, region string, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.AssociateRouteTableInput{
@@ -401,6 +428,7 @@ func DetachInternetGateway(gatewaySearch string, dryRun bool) error {
 	// Get the Internet Gateway
 	gatewayList, err := GetInternetGateways(gatewaySearch, false)
 	if err != nil {
+		ShowErrors(err)
 		return errors.New("Error while trying to find the internet gateway!")
 	}
 
@@ -423,7 +451,7 @@ func DetachInternetGateway(gatewaySearch string, dryRun bool) error {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to detach this Internet Gateway from this VPC?") {
+	if !prompt.Confirm("Are you sure you want to detach this Internet Gateway from this VPC?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -437,7 +465,7 @@ func DetachInternetGateway(gatewaySearch string, dryRun bool) error {
 }
 
 func detachInternetGateway(internetGateway InternetGateway, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(internetGateway.Region)}))
+	sess := newSession(internetGateway.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DetachInternetGatewayInput{
@@ -465,7 +493,8 @@ func DisassociateRouteTable(routeTableSearch, subnetSearch string, dryRun bool)
 	}
 
 	// Get the Subnet
-	subnetList, _ := GetSubnets(subnetSearch)
+	subnetList, errs := GetSubnets(subnetSearch)
+	ShowErrors(errs)
 	subCount := len(*subnetList)
 	if subCount == 0 {
 		return errors.New("No Subnets found for your search terms.")
@@ -512,7 +541,7 @@ Loop:
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to disassociate this Route Table from this Subnet?") {
+	if !prompt.Confirm("Are you sure you want to disassociate this Route Table from this Subnet?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -526,7 +555,7 @@ Loop:
 }
 
 func disassociateRouteTable(associationId string, rt RouteTable, subnet Subnet, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(subnet.Region)}))
+	sess := newSession(subnet.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DisassociateRouteTableInput{
@@ -553,6 +582,7 @@ func DeleteInternetGateway(gatewaySearch string, dryRun bool) error {
 	// Get the Internet Gateway
 	gatewayList, err := GetInternetGateways(gatewaySearch, false)
 	if err != nil {
+		ShowErrors(err)
 		return errors.New("Error while trying to find the internet gateway!")
 	}
 
@@ -570,7 +600,7 @@ func DeleteInternetGateway(gatewaySearch string, dryRun bool) error {
 	terminal.Information("Found Internet Gateway [" + gateway.InternetGatewayID + "] named [" + gateway.Name + "] in [" + gateway.Region + "]!")
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete this Internet Gateway?") {
+	if !prompt.Confirm("Are you sure you want to delete this Internet Gateway?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -584,7 +614,7 @@ func DeleteInternetGateway(gatewaySearch string, dryRun bool) error {
 }
 
 func deleteInternetGateway(internetGateway InternetGateway, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(internetGateway.Region)}))
+	sess := newSession(internetGateway.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DeleteInternetGatewayInput{
@@ -611,6 +641,7 @@ func DeleteRouteTable(routeTableSearch string, dryRun bool) error {
 	// Get the Route Table
 	rtList, err := GetRouteTables(routeTableSearch)
 	if err != nil {
+		ShowErrors(err)
 		return errors.New("Error while trying to find the route table!")
 	}
 
@@ -628,7 +659,7 @@ func DeleteRouteTable(routeTableSearch string, dryRun bool) error {
 	terminal.Information("Found Route Table [" + rt.RouteTableID + "] named [" + rt.Name + "] in [" + rt.Region + "]!")
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete this Route Table?") {
+	if !prompt.Confirm("Are you sure you want to delete this Route Table?") {
 		terminal.ErrorLine("Aborting!")
 		return nil
 	}
@@ -642,7 +673,7 @@ func DeleteRouteTable(routeTableSearch string, dryRun bool) error {
 }
 
 func deleteRouteTable(rt RouteTable, dryRun bool) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(rt.Region)}))
+	sess := newSession(rt.Region)
 	svc := ec2.New(sess)
 
 	params := &ec2.DeleteRouteTableInput{
@@ -662,33 +693,24 @@ func deleteRouteTable(rt RouteTable, dryRun bool) error {
 
 // GetInternetGateways returns a slice of Internet Gateways that match the provided search term
 func GetInternetGateways(search string, available bool) (*InternetGateways, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	igList := new(InternetGateways)
-	regions := GetRegionListWithoutIgnored()
-
-	for _, region := range regions {
-		wg.Add(1)
 
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionInternetGateways(*region.RegionName, igList, search, available)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering internet gateway list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("internet gateway", func(account Account, region string) error {
+		return getAccountRegionInternetGateways(account, region, igList, search, available)
+	})
 
 	return igList, errs
 }
 
 // GetRegionInternetGateways returns a list of a regions InternetGateways that match the provided search term
 func GetRegionInternetGateways(region string, igList *InternetGateways, search string, available bool) error {
+	return getAccountRegionInternetGateways(CurrentAccount(), region, igList, search, available)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionInternetGateways returns a list of the InternetGateways of a region of an account that match the provided search term
+func getAccountRegionInternetGateways(account Account, region string, igList *InternetGateways, search string, available bool) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{})
@@ -700,6 +722,7 @@ func GetRegionInternetGateways(region string, igList *InternetGateways, search s
 	igs := make(InternetGateways, len(result.InternetGateways))
 	for i, ig := range result.InternetGateways {
 		igs[i].Marshal(ig, region)
+		igs[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -776,7 +799,7 @@ func GetVpcRouteTables(search, vpcId, region string) (*RouteTables, error) {
 
 	rtList := new(RouteTables)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
@@ -822,33 +845,24 @@ func GetVpcRouteTables(search, vpcId, region string) (*RouteTables, error) {
 
 // GetRouteTables returns a slice of Route Tables that match the provided search term
 func GetRouteTables(search string) (*RouteTables, []error) {
-	var wg sync.WaitGroup
-	var errs []error
-
 	rtList := new(RouteTables)
-	regions := GetRegionListWithoutIgnored()
 
-	for _, region := range regions {
-		wg.Add(1)
-
-		go func(region *ec2.Region) {
-			defer wg.Done()
-			err := GetRegionRouteTables(*region.RegionName, rtList, search)
-			if err != nil {
-				terminal.ShowErrorMessage(fmt.Sprintf("Error gathering route tables list for region [%s]", *region.RegionName), err.Error())
-				errs = append(errs, err)
-			}
-		}(region)
-	}
-	wg.Wait()
+	errs := collectAccountRegions("route tables", func(account Account, region string) error {
+		return getAccountRegionRouteTables(account, region, rtList, search)
+	})
 
 	return rtList, errs
 }
 
 // GetRegionRouteTables returns a list of a regions RouteTables that match the provided search term
 func GetRegionRouteTables(region string, rtList *RouteTables, search string) error {
+	return getAccountRegionRouteTables(CurrentAccount(), region, rtList, search)
+}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+// getAccountRegionRouteTables returns a list of the RouteTables of a region of an account that match the provided search term
+func getAccountRegionRouteTables(account Account, region string, rtList *RouteTables, search string) error {
+
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeRouteTables(&ec2.DescribeRouteTablesInput{})
@@ -860,6 +874,7 @@ func GetRegionRouteTables(region string, rtList *RouteTables, search string) err
 	rts := make(RouteTables, len(result.RouteTables))
 	for i, rt := range result.RouteTables {
 		rts[i].Marshal(rt, region)
+		rts[i].Account = account.Name
 		/*
 			mainRt, err := GetVpcMainRouteTable(*rt.VpcId, region)
 			if err != nil {
@@ -921,8 +936,13 @@ func (v *Vpc) Marshal(vpc *ec2.Vpc, region string) {
 
 // GetRegionVpcs returns a list of a regions VPCs that match the provided search term
 func GetRegionVpcs(region string, vpcList *Vpcs, search string) error {
+	return getAccountRegionVpcs(CurrentAccount(), region, vpcList, search)
+}
+
+// getAccountRegionVpcs returns a list of the VPCs of a region of an account that match the provided search term
+func getAccountRegionVpcs(account Account, region string, vpcList *Vpcs, search string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := account.Session(region)
 	svc := ec2.New(sess)
 
 	result, err := svc.DescribeVpcs(&ec2.DescribeVpcsInput{})
@@ -934,6 +954,7 @@ func GetRegionVpcs(region string, vpcList *Vpcs, search string) error {
 	vpcs := make(Vpcs, len(result.Vpcs))
 	for i, vpc := range result.Vpcs {
 		vpcs[i].Marshal(vpc, region)
+		vpcs[i].Account = account.Name
 	}
 
 	if search != "" {
@@ -1051,7 +1072,7 @@ func CreateVpc(class, name, ip, region string, dryRun bool) error {
 
 	// TODO limit to one VPC of a class per region, so that we can target VPCs by class instead of name?
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Create the VPC
@@ -1085,7 +1106,7 @@ func CreateVpc(class, name, ip, region string, dryRun bool) error {
 	terminal.Delta("Adding VPC Tags...")
 
 	// Add Tags
-	err = SetEc2NameAndClassTags(&vpcId, name, class, region)
+	err = SetEc2ClassTags(&vpcId, name, class, cfg.Tags, region)
 	if err != nil {
 		return err
 	}
@@ -1107,7 +1128,7 @@ func CreateInternetGateway(name, region string, dryRun bool) (string, error) {
 		return "", errors.New("Region [" + region + "] is Invalid!")
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Create the Internet Gateway
@@ -1144,7 +1165,7 @@ func CreateNatGateway(name, allocationId, subnetId, region string, dryRun bool)
 		allocationId, _ = CreateAddress(region, "vpc", dryRun)
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.CreateNatGatewayInput{
@@ -1188,7 +1209,8 @@ func CreateRouteTable(name, vpcSearch string, dryRun bool) (string, error) {
 		terminal.Information("--dry-run flag is set, not making any actual changes!")
 	}
 
-	vpcList, _ := GetVpcs(vpcSearch)
+	vpcList, errs := GetVpcs(vpcSearch)
+	ShowErrors(errs)
 
 	vpcCount := len(*vpcList)
 	if vpcCount == 0 {
@@ -1206,7 +1228,7 @@ func CreateRouteTable(name, vpcSearch string, dryRun bool) (string, error) {
 
 func createRouteTable(name, vpcId, region string, dryRun bool) (string, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	// Create the Route Table
@@ -1234,7 +1256,7 @@ func createRouteTable(name, vpcId, region string, dryRun bool) (string, error) {
 
 func createRoute(routeTableId, region, destinationCidr, gatewayId, natGatewayId string, dryRun bool) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(region)}))
+	sess := newSession(region)
 	svc := ec2.New(sess)
 
 	params := &ec2.CreateRouteInput{
@@ -1288,7 +1310,9 @@ func DeleteVpcs(search, region string, dryRun bool) (err error) {
 	if region != "" {
 		err = GetRegionVpcs(region, vpcList, search)
 	} else {
-		vpcList, _ = GetVpcs(search)
+		var errs []error
+		vpcList, errs = GetVpcs(search)
+		ShowErrors(errs)
 	}
 
 	if err != nil {
@@ -1303,7 +1327,7 @@ func DeleteVpcs(search, region string, dryRun bool) (err error) {
 	}
 
 	// Confirm
-	if !terminal.PromptBool("Are you sure you want to delete these VPCs?") {
+	if !prompt.Confirm("Are you sure you want to delete these VPCs?") {
 		return errors.New("Aborting!")
 	}
 
@@ -1324,7 +1348,7 @@ func DeleteVpcs(search, region string, dryRun bool) (err error) {
 // private function without the confirmation terminal prompts
 func deleteVpcs(vpcList *Vpcs, dryRun bool) (err error) {
 	for _, vpc := range *vpcList {
-		sess := session.Must(session.NewSession(&aws.Config{Region: aws.String(vpc.Region)}))
+		sess := newSession(vpc.Region)
 		svc := ec2.New(sess)
 
 		params := &ec2.DeleteVpcInput{
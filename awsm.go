@@ -8,10 +8,16 @@ import (
 	"os"
 	"os/user"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/murdinc/awsm/api"
 	"github.com/murdinc/awsm/aws"
+	"github.com/murdinc/awsm/aws/regions"
 	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
 	"github.com/murdinc/cli"
 	"github.com/murdinc/terminal"
 )
@@ -28,6 +34,22 @@ func main() {
 	var previous bool // optional flag when getting autoscale version
 	var latest bool   // optional flag when getting scaling activities
 	var wait bool     // optional flag when creating snapshots
+	var count int     // optional flag when launching instances
+	var envName string
+	var profileName string
+	var accounts string
+	var regionNames string
+	var offline bool
+	var nonInteractive bool
+	var assumeYes bool
+	var debug bool
+	var logFile string
+	var logFormat string
+	var launchRegion string
+	var launchZones string
+	var waitTimeout time.Duration
+	var sshUser string
+	var updateClass bool
 
 	app := cli.NewApp()
 	app.Name = "awsm"
@@ -43,6 +65,131 @@ func main() {
 			Destination: &dryRun,
 			Usage:       "dry-run (Don't make any real changes)",
 		},
+		cli.StringFlag{
+			Name:        "env",
+			Destination: &envName,
+			EnvVar:      "AWSM_ENV",
+			Usage:       "environment from ~/.awsm/config to use (default: default)",
+		},
+		cli.StringFlag{
+			Name:        "profile",
+			Destination: &profileName,
+			EnvVar:      "AWSM_PROFILE",
+			Usage:       "profile from ~/.aws/credentials to use, overriding the environment profile",
+		},
+		cli.StringFlag{
+			Name:        "accounts",
+			Destination: &accounts,
+			EnvVar:      "AWSM_ACCOUNTS",
+			Usage:       "comma separated profiles of the accounts to list assets from, overriding the environment accounts",
+		},
+		cli.StringFlag{
+			Name:        "regions",
+			Destination: &regionNames,
+			EnvVar:      "AWSM_REGIONS",
+			Usage:       "comma separated regions to work in, overriding the environment regions",
+		},
+		cli.BoolFlag{
+			Name:        "offline",
+			Destination: &offline,
+			EnvVar:      "AWSM_OFFLINE",
+			Usage:       "use the cached regions and availability zones without contacting AWS",
+		},
+		cli.BoolFlag{
+			Name:        "non-interactive",
+			Destination: &nonInteractive,
+			EnvVar:      "AWSM_NON_INTERACTIVE",
+			Usage:       "never prompt, exit instead of asking a question (for automation and CI)",
+		},
+		cli.BoolFlag{
+			Name:        "yes, y",
+			Destination: &assumeYes,
+			Usage:       "answer yes to every confirmation, implies --non-interactive",
+		},
+		cli.BoolFlag{
+			Name:        "debug",
+			Destination: &debug,
+			EnvVar:      "AWSM_DEBUG",
+			Usage:       "debug logging, including the AWS requests and responses",
+		},
+		cli.StringFlag{
+			Name:        "log-file",
+			Destination: &logFile,
+			EnvVar:      "AWSM_LOG_FILE",
+			Usage:       "file to write the log to instead of stderr, overriding the environment log_file",
+		},
+		cli.StringFlag{
+			Name:        "log-format",
+			Destination: &logFormat,
+			EnvVar:      "AWSM_LOG_FORMAT",
+			Usage:       "log format, text or json, overriding the environment log_format",
+		},
+	}
+
+	app.Before = func(c *cli.Context) error {
+		env, err := settings.Load(envName)
+		if err != nil {
+			return err
+		}
+
+		if accounts != "" {
+			env.Accounts = settings.SplitList(accounts)
+		}
+
+		if regionNames != "" {
+			env.Regions = settings.SplitList(regionNames)
+		}
+
+		if offline {
+			env.Offline = true
+		}
+
+		if nonInteractive || assumeYes {
+			env.NonInteractive = true
+		}
+		env.AssumeYes = assumeYes
+
+		if debug {
+			env.Debug = true
+		}
+		if logFile != "" {
+			env.LogFile = logFile
+		}
+		if logFormat != "" {
+			env.LogFormat = logFormat
+		}
+
+		logLevel, err := logger.ParseLevel(env.LogLevel)
+		if err != nil {
+			return err
+		}
+		if env.Debug {
+			logLevel = logger.LevelDebug
+		}
+		err = logger.Configure(logger.Options{Level: logLevel, Format: env.LogFormat, File: env.LogFile, AWSDebug: env.Debug})
+		if err != nil {
+			return err
+		}
+		logger.Debug("Loaded the environment", "env", env.Name, "regions", strings.Join(env.Regions, ","), "accounts", strings.Join(env.Accounts, ","))
+
+		if profileName == "" {
+			profileName = env.Profile
+		}
+		if profileName != "" {
+			err = aws.UseProfile(profileName)
+			if err != nil {
+				return err
+			}
+			// The region catalog is cached per profile
+			env.Profile = profileName
+		}
+
+		// Validated after the profile is in use, as the list of valid regions comes from AWS
+		if len(env.Regions) > 0 {
+			return regions.ValidateRegions(env.Regions)
+		}
+
+		return nil
 	}
 
 	app.Commands = []cli.Command{
@@ -60,6 +207,7 @@ func main() {
 			Usage:  "Start the awsm api server",
 			Before: setupCheck,
 			Action: func(c *cli.Context) error {
+				api.Version = c.App.Version
 				return api.StartAPI(false)
 			},
 		},
@@ -68,6 +216,7 @@ func main() {
 			Usage:  "Launch the awsm Dashboard GUI",
 			Before: setupCheck,
 			Action: func(c *cli.Context) error {
+				api.Version = c.App.Version
 				return api.StartAPI(true)
 			},
 		},
@@ -186,6 +335,58 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "sshConfig",
+			Usage: "Write a Host entry for every running instance to ~/.ssh/config",
+			Arguments: []cli.Argument{
+				{
+					Name:        "search",
+					Description: "The keyword to search for",
+					Optional:    true,
+				},
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "user",
+					Destination: &sshUser,
+					Usage:       "user (The user to log in as, default: ssh_user of the environment)",
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.SSHConfig(c.NamedArg("search"), sshUser, dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "connect",
+			Usage: "Connect to a running instance with ssh",
+			Arguments: []cli.Argument{
+				{
+					Name:        "search",
+					Description: "The instance to connect to",
+					Optional:    false,
+				},
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "user",
+					Destination: &sshUser,
+					Usage:       "user (The user to log in as, default: ssh_user of the environment)",
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.Connect(c.NamedArg("search"), sshUser)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "copyImage",
 			Usage: "Copy a Machine Image to another region",
@@ -674,13 +875,20 @@ func main() {
 				},
 				{
 					Name:        "az",
-					Description: "The Availability Zone to create the volume in",
-					Optional:    false,
+					Description: "The Availability Zone to create the volume in, the one with the fewest volumes of the class if not set",
+					Optional:    true,
+				},
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "region",
+					Destination: &launchRegion,
+					Usage:       "region (Region to pick the availability zone in when none is given, default: the default region of the environment)",
 				},
 			},
 			Before: setupCheck,
 			Action: func(c *cli.Context) error {
-				err := aws.CreateVolume(c.NamedArg("class"), c.NamedArg("name"), c.NamedArg("az"), dryRun)
+				err := aws.CreateVolume(c.NamedArg("class"), c.NamedArg("name"), c.NamedArg("az"), launchRegion, dryRun)
 				if err != nil {
 					return err
 				}
@@ -815,6 +1023,30 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "deleteClass",
+			Usage: "Delete a Class",
+			Arguments: []cli.Argument{
+				{
+					Name:        "type",
+					Description: "The type of class (instances, volumes, securitygroups, etc)",
+					Optional:    false,
+				},
+				{
+					Name:        "name",
+					Description: "The name of the class to delete",
+					Optional:    false,
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := deleteClass(c.NamedArg("type"), c.NamedArg("name"), dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "deleteIAMInstanceProfiles",
 			Usage: "Delete IAM Instance Profiles",
@@ -1289,6 +1521,30 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "editClass",
+			Usage: "Create or edit a Class in your $EDITOR",
+			Arguments: []cli.Argument{
+				{
+					Name:        "type",
+					Description: "The type of class (instances, volumes, securitygroups, etc)",
+					Optional:    false,
+				},
+				{
+					Name:        "name",
+					Description: "The name of the class to create or edit",
+					Optional:    false,
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := editClass(c.NamedArg("type"), c.NamedArg("name"), dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "executeScalingPolicies",
 			Usage: "Execute Scaling Policies",
@@ -1368,9 +1624,7 @@ func main() {
 				}
 
 				instProfilesSlice := aws.IAMInstanceProfiles{instanceProfile}
-				instProfilesSlice.PrintTable()
-
-				return nil
+				return printList(&instProfilesSlice)
 			},
 		},
 		{
@@ -1421,9 +1675,7 @@ func main() {
 				}
 
 				userSlice := aws.IAMUsers{iam}
-				userSlice.PrintTable()
-
-				return nil
+				return printList(&userSlice)
 			},
 		},
 		{
@@ -1443,8 +1695,7 @@ func main() {
 					return cli.NewExitError("Error Listing Inventory!", 1)
 				}
 
-				inventory.PrintTable()
-				return nil
+				return printList(inventory)
 			},
 		},
 		{
@@ -1526,6 +1777,66 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "resizeInstances",
+			Usage: "Change the instance type of instances, stopping and starting them one at a time",
+			Arguments: []cli.Argument{
+				{
+					Name:        "search",
+					Description: "The search term for instances to resize",
+					Optional:    false,
+				},
+				{
+					Name:        "type",
+					Description: "The instance type to resize to",
+					Optional:    false,
+				},
+				{
+					Name:        "region",
+					Description: "The region of the instances (optional)",
+					Optional:    true,
+				},
+			},
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:        "update-class",
+					Destination: &updateClass,
+					Usage:       "update the instance type of the instance classes to match",
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.ResizeInstances(c.NamedArg("search"), c.NamedArg("type"), c.NamedArg("region"), updateClass, dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			Name:   "scheduler",
+			Usage:  "Run the scheduler, starting and stopping the instances and autoscale groups of classes with a schedule",
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.RunScheduler(dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			Name:   "bootstrap",
+			Usage:  "Attach the volumes and register the DNS name of the instance this is run on, from its class (for use in UserData)",
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.Bootstrap(dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "refreshVolume",
 			Usage: "Refreshe an EBS Volume on an EC2 Instance",
@@ -1583,7 +1894,7 @@ func main() {
 		},
 		{
 			Name:  "launchInstance",
-			Usage: "Launch an EC2 instance",
+			Usage: "Launch one or more EC2 instances",
 			Arguments: []cli.Argument{
 				{
 					Name:        "class",
@@ -1592,18 +1903,60 @@ func main() {
 				},
 				{
 					Name:        "sequence",
-					Description: "The sequence of the instance (1...100)",
-					Optional:    false,
+					Description: "The sequence of the instance (1...100), the next free one if not set",
+					Optional:    true,
 				},
 				{
 					Name:        "az",
-					Description: "The availability zone to launch the instance in (us-west-2a, us-east-1a, etc)",
-					Optional:    false,
+					Description: "The availability zone to launch the instance in (us-west-2a, us-east-1a, etc), picked automatically if not set",
+					Optional:    true,
+				},
+			},
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:        "count",
+					Value:       1,
+					Destination: &count,
+					Usage:       "count (Launch this many instances with the next free sequences, spread across availability zones)",
+				},
+				cli.StringFlag{
+					Name:        "region",
+					Destination: &launchRegion,
+					Usage:       "region (Region to launch in when no availability zone is given, default: the default region of the environment)",
+				},
+				cli.StringFlag{
+					Name:        "azs",
+					Destination: &launchZones,
+					Usage:       "azs (Comma separated availability zones to spread the instances across, default: all of them)",
+				},
+				cli.BoolFlag{
+					Name:        "wait",
+					Destination: &wait,
+					Usage:       "wait (Wait for the instances to be running, pass their status checks and register with SSM, then run the post-launch commands and DNS registration of the class)",
+				},
+				cli.DurationFlag{
+					Name:        "wait-timeout",
+					Value:       15 * time.Minute,
+					Destination: &waitTimeout,
+					Usage:       "wait-timeout (How long to wait for each instance to be ready)",
 				},
 			},
 			Before: setupCheck,
 			Action: func(c *cli.Context) error {
-				err := aws.LaunchInstance(c.NamedArg("class"), c.NamedArg("sequence"), c.NamedArg("az"), dryRun)
+				sequence, az := c.NamedArg("sequence"), c.NamedArg("az")
+
+				// A single instance with a hand picked sequence, and optionally availability zone
+				if sequence != "" && count <= 1 {
+					return aws.LaunchInstance(c.NamedArg("class"), sequence, az, launchRegion, wait, waitTimeout, dryRun)
+				}
+
+				if sequence != "" {
+					return cli.NewExitError("Sequences are picked automatically with --count, leave out the sequence and availability zone!", 1)
+				}
+
+				azList := settings.SplitList(launchZones)
+
+				err := aws.LaunchInstances(c.NamedArg("class"), count, launchRegion, azList, wait, waitTimeout, dryRun)
 				if err != nil {
 					return err
 				}
@@ -1624,10 +1977,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				addresses, errs := aws.GetAddresses(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Addresses!", 1)
 				}
-				addresses.PrintTable()
-				return nil
+				return printList(addresses)
 			},
 		},
 		{
@@ -1644,11 +1997,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				alarms, errs := aws.GetAlarms(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Alarms!", 1)
 				}
-				alarms.PrintTable()
-
-				return nil
+				return printList(alarms)
 			},
 		},
 		{
@@ -1665,11 +2017,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				groups, errs := aws.GetAutoScaleGroups(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Auto Scale Groups!", 1)
 				}
-				groups.PrintTable()
-
-				return nil
+				return printList(groups)
 			},
 		},
 		{
@@ -1686,10 +2037,28 @@ func main() {
 			Action: func(c *cli.Context) error {
 				groups, errs := aws.GetBuckets(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors([]error{errs})
 					return cli.NewExitError("Error Listing S3 Buckets!", 1)
 				}
-				groups.PrintTable()
-
+				return printList(groups)
+			},
+		},
+		{
+			Name:  "listClasses",
+			Usage: "List Classes",
+			Arguments: []cli.Argument{
+				{
+					Name:        "type",
+					Description: "The type of class (instances, volumes, securitygroups, etc)",
+					Optional:    false,
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := listClasses(c.NamedArg("type"))
+				if err != nil {
+					return err
+				}
 				return nil
 			},
 		},
@@ -1719,7 +2088,7 @@ func main() {
 				if details {
 					commandInvocations.PrintOutput()
 				} else {
-					commandInvocations.PrintTable()
+					return printList(commandInvocations)
 				}
 
 				return nil
@@ -1741,9 +2110,7 @@ func main() {
 				if errs != nil {
 					return cli.NewExitError("Error Listing Hosted Zones!", 1)
 				}
-				hostedZones.PrintTable()
-
-				return nil
+				return printList(hostedZones)
 			},
 		},
 
@@ -1761,11 +2128,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				iam, errs := aws.GetIAMInstanceProfiles(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors([]error{errs})
 					return cli.NewExitError("Error Listing IAM Instance Profiles!", 1)
 				}
-				iam.PrintTable()
-
-				return nil
+				return printList(iam)
 			},
 		},
 		{
@@ -1784,9 +2150,7 @@ func main() {
 				if errs != nil {
 					return cli.NewExitError("Error Listing IAM Policies!", 1)
 				}
-				iam.PrintTable()
-
-				return nil
+				return printList(iam)
 			},
 		},
 		{
@@ -1803,11 +2167,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				iam, errs := aws.GetIAMRoles(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors([]error{errs})
 					return cli.NewExitError("Error Listing IAM Roles!", 1)
 				}
-				iam.PrintTable()
-
-				return nil
+				return printList(iam)
 			},
 		},
 		{
@@ -1824,11 +2187,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				iam, errs := aws.GetIAMUsers(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors([]error{errs})
 					return cli.NewExitError("Error Listing IAM Users!", 1)
 				}
-				iam.PrintTable()
-
-				return nil
+				return printList(iam)
 			},
 		},
 		{
@@ -1845,11 +2207,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				images, errs := aws.GetImages(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Images!", 1)
 				}
-				images.PrintTable()
-
-				return nil
+				return printList(images)
 			},
 		},
 		{
@@ -1866,11 +2227,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				instances, errs := aws.GetInstances(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Instances!", 1)
 				}
-				instances.PrintTable()
-
-				return nil
+				return printList(instances)
 			},
 		},
 		{
@@ -1887,11 +2247,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				internetGateways, errs := aws.GetInternetGateways(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Internet Gateways!", 1)
 				}
-				internetGateways.PrintTable()
-
-				return nil
+				return printList(internetGateways)
 			},
 		},
 		{
@@ -1908,11 +2267,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				keyPairs, errs := aws.GetKeyPairs(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Key Pairs!", 1)
 				}
-				keyPairs.PrintTable()
-
-				return nil
+				return printList(keyPairs)
 			},
 		},
 		{
@@ -1929,11 +2287,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				launchConfigs, errs := aws.GetLaunchConfigurations(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Launch Configurations!", 1)
 				}
-				launchConfigs.PrintTable()
-
-				return nil
+				return printList(launchConfigs)
 			},
 		},
 		{
@@ -1950,11 +2307,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				loadBalancers, errs := aws.GetLoadBalancers(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Load Balancers!", 1)
 				}
-				loadBalancers.PrintTable()
-
-				return nil
+				return printList(loadBalancers)
 			},
 		},
 		{
@@ -1973,9 +2329,7 @@ func main() {
 				if errs != nil {
 					return cli.NewExitError("Error Listing Resource Records!", 1)
 				}
-				resourceRecords.PrintTable()
-
-				return nil
+				return printList(resourceRecords)
 			},
 		},
 		{
@@ -1992,11 +2346,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				internetGateways, errs := aws.GetRouteTables(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Route Tables!", 1)
 				}
-				internetGateways.PrintTable()
-
-				return nil
+				return printList(internetGateways)
 			},
 		},
 		{
@@ -2022,9 +2375,7 @@ func main() {
 				if err != nil {
 					return err
 				}
-				activities.PrintTable()
-
-				return nil
+				return printList(&activities)
 			},
 		},
 		{
@@ -2041,11 +2392,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				policies, errs := aws.GetScalingPolicies(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Auto Scaling Policies!", 1)
 				}
-				policies.PrintTable()
-
-				return nil
+				return printList(policies)
 			},
 		},
 		{
@@ -2062,11 +2412,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				groups, errs := aws.GetSecurityGroups(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Security Groups!", 1)
 				}
-				groups.PrintTable()
-
-				return nil
+				return printList(groups)
 			},
 		},
 		{
@@ -2083,11 +2432,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				snapshots, errs := aws.GetSnapshots(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Snapshots!", 1)
 				}
-				snapshots.PrintTable()
-
-				return nil
+				return printList(snapshots)
 			},
 		},
 		{
@@ -2106,9 +2454,7 @@ func main() {
 				if errs != nil {
 					return cli.NewExitError("Error Listing SSM Instances!", 1)
 				}
-				instances.PrintTable()
-
-				return nil
+				return printList(instances)
 			},
 		},
 		{
@@ -2125,11 +2471,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				subnets, errs := aws.GetSubnets(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Subnets!", 1)
 				}
-				subnets.PrintTable()
-
-				return nil
+				return printList(subnets)
 			},
 		},
 		{
@@ -2146,11 +2491,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				domains, errs := aws.GetSimpleDBDomains(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Simple DB Domains!", 1)
 				}
-				domains.PrintTable()
-
-				return nil
+				return printList(domains)
 			},
 		},
 		{
@@ -2167,11 +2511,10 @@ func main() {
 			Action: func(c *cli.Context) error {
 				volumes, errs := aws.GetVolumes(c.NamedArg("search"), false)
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing Volumes!", 1)
 				}
-				volumes.PrintTable()
-
-				return nil
+				return printList(volumes)
 			},
 		},
 		{
@@ -2188,10 +2531,22 @@ func main() {
 			Action: func(c *cli.Context) error {
 				vpcs, errs := aws.GetVpcs(c.NamedArg("search"))
 				if errs != nil {
+					aws.ShowErrors(errs)
 					return cli.NewExitError("Error Listing VPCs!", 1)
 				}
-				vpcs.PrintTable()
-
+				return printList(vpcs)
+			},
+		},
+		{
+			Name:   "refreshRegions",
+			Usage:  "Refresh the cached list of regions and availability zones",
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				catalog, err := regions.RefreshCatalog()
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				terminal.Information(fmt.Sprintf("Cached [%d] regions and [%d] availability zones!", len(catalog.Regions), len(catalog.AZs)))
 				return nil
 			},
 		},
@@ -2245,6 +2600,30 @@ func main() {
 				return err
 			},
 		},
+		{
+			Name:  "showClass",
+			Usage: "Show a Class",
+			Arguments: []cli.Argument{
+				{
+					Name:        "type",
+					Description: "The type of class (instances, volumes, securitygroups, etc)",
+					Optional:    false,
+				},
+				{
+					Name:        "name",
+					Description: "The name of the class to show",
+					Optional:    false,
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := showClass(c.NamedArg("type"), c.NamedArg("name"))
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "suspendProcesses",
 			Usage: "Suspend scaling processes on Autoscaling Groups",
@@ -2305,6 +2684,30 @@ func main() {
 				return err
 			},
 		},
+		{
+			Name:  "updateInstanceMetadata",
+			Usage: "Update the Instance Metadata Service options of instances to match their classes",
+			Arguments: []cli.Argument{
+				{
+					Name:        "search",
+					Description: "The search term of the instances to update",
+					Optional:    false,
+				},
+				{
+					Name:        "region",
+					Description: "The region to update the instances in (optional)",
+					Optional:    true,
+				},
+			},
+			Before: setupCheck,
+			Action: func(c *cli.Context) error {
+				err := aws.UpdateInstanceMetadata(c.NamedArg("search"), c.NamedArg("region"), dryRun)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
 		{
 			Name:  "updateLoadBalancers",
 			Usage: "Update Load Balancers",
@@ -2390,7 +2793,13 @@ func main() {
 		},
 	}
 
-	app.Run(os.Args)
+	// Exit errors exit with their own code, anything else still needs to fail
+	err := app.Run(os.Args)
+	logger.Close()
+	if err != nil {
+		terminal.ErrorLine(err.Error())
+		os.Exit(1)
+	}
 }
 
 func installAutocomplete() error {
@@ -2463,12 +2872,20 @@ func setupCheck(c *cli.Context) error {
 	found, accountId := aws.CheckCreds()
 	if !found {
 		terminal.ErrorLine("No Credentials Available, Aborting!")
-		os.Exit(0)
+		os.Exit(1)
+	}
+
+	// Show who we are, unless the output is meant to be parsed
+	if settings.Current().Output != "json" {
+		identity, err := aws.GetCallerIdentity()
+		if err == nil {
+			terminal.Information("Using AWS account [" + identity.Account + "] as [" + identity.Arn + "]")
+		}
 	}
 
 	// DB Check
 	if !config.CheckDB() {
-		create := terminal.BoxPromptBool("No awsm database found!", "Do you want to create one now?")
+		create := prompt.BoxConfirm("No awsm database found!", "Do you want to create one now?")
 		if !create {
 			terminal.Information("Ok, maybe next time.. ")
 			os.Exit(0)
@@ -2488,8 +2905,8 @@ func setupCheck(c *cli.Context) error {
 		}
 
 		var policyDocument string
-		region := "us-east-1" // TODO handle default region preference
-		dbArn := "arn:aws:sdb:" + region + ":" + accountId + ":domain/awsm"
+		env := settings.Current()
+		dbArn := "arn:aws:sdb:" + env.ClassStoreRegion + ":" + accountId + ":domain/" + env.ClassStoreDomain
 
 		t := template.New("")
 		t, err = t.Parse(awsmDBPolicy)
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/prompt"
+	"github.com/murdinc/awsm/settings"
+	"github.com/murdinc/cli"
+	"github.com/murdinc/terminal"
+	"github.com/olekukonko/tablewriter"
+)
+
+// listClasses prints a table of all classes of a type
+func listClasses(classType string) error {
+	classes, err := config.LoadAllClasses(classType)
+	if classes == nil {
+		return err
+	}
+
+	classMap := reflect.ValueOf(classes)
+	if err != nil || classMap.Len() == 0 {
+		terminal.ShowErrorMessage("Warning", "No ["+classType+"] Classes Found!")
+		return nil
+	}
+
+	if settings.Current().Output == "json" {
+		return printJSON(config.RedactSecrets(classes))
+	}
+
+	var names []string
+	for _, key := range classMap.MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+
+	header := []string{"Name"}
+	rows := make([][]string, len(names))
+
+	for i, name := range names {
+		keys, values := config.ExtractAwsmClass(classMap.MapIndex(reflect.ValueOf(name)).Interface())
+		if i == 0 {
+			header = append(header, keys...)
+		}
+		rows[i] = append([]string{name}, values...)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.AppendBulk(rows)
+	table.Render()
+
+	return nil
+}
+
+// showClass prints a single class as JSON
+func showClass(classType, className string) error {
+	class, err := config.LoadClassByName(classType, className)
+	if err != nil {
+		return err
+	}
+
+	classJSON, err := json.MarshalIndent(config.RedactSecrets(class), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(classJSON))
+
+	return nil
+}
+
+// editClass opens a class as JSON in $EDITOR, validates and diffs the result, and saves it after confirmation
+func editClass(classType, className string, dryRun bool) error {
+
+	if settings.Current().NonInteractive {
+		return cli.NewExitError("Unable to open an editor for ["+classType+"] class ["+className+"] in non-interactive mode!", prompt.ExitInteractionRequired)
+	}
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	class, err := config.LoadClassByName(classType, className)
+	if class == nil {
+		return err
+	}
+
+	if _, notFound := err.(config.NotFoundError); notFound {
+		// Loaders return an empty class when none is found, use it as the template for a new one
+		if !prompt.Confirm("No [" + classType + "] class named [" + className + "] was found, do you want to create it?") {
+			return errors.New("Aborting!")
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// Secrets stay redacted, the redacted placeholder keeps the stored value when saved
+	before, err := json.MarshalIndent(config.RedactSecrets(class), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile("", "awsm-"+classType+"-"+className+"-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	edited := before
+	for {
+		err = ioutil.WriteFile(tmpFile.Name(), edited, 0600)
+		if err != nil {
+			return err
+		}
+
+		err = runEditor(tmpFile.Name())
+		if err != nil {
+			return err
+		}
+
+		edited, err = ioutil.ReadFile(tmpFile.Name())
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(before)) {
+			terminal.Information("No changes made to [" + classType + "] class [" + className + "].")
+			return nil
+		}
+
+		err = validateClass(class, edited)
+		if err == nil {
+			break
+		}
+
+		terminal.ShowErrorMessage("Invalid ["+classType+"] class!", err.Error())
+		if !prompt.Confirm("Do you want to edit it again?") {
+			return errors.New("Aborting!")
+		}
+	}
+
+	terminal.Notice("Changes to [" + classType + "] class [" + className + "]:")
+	printDiff(string(before), string(edited))
+
+	if dryRun {
+		return nil
+	}
+
+	if !prompt.Confirm("Do you want to save these changes?") {
+		return errors.New("Aborting!")
+	}
+
+	_, err = config.SaveClass(classType, className, edited)
+	if err != nil {
+		return err
+	}
+
+	terminal.Information("Done!")
+
+	return nil
+}
+
+// deleteClass deletes a class after confirmation
+func deleteClass(classType, className string, dryRun bool) error {
+
+	// --dry-run flag
+	if dryRun {
+		terminal.Information("--dry-run flag is set, not making any actual changes!")
+	}
+
+	err := showClass(classType, className)
+	if err != nil {
+		return err
+	}
+
+	if !prompt.Confirm("Are you sure you want to delete the [" + classType + "] class [" + className + "]?") {
+		return errors.New("Aborting!")
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	err = config.DeleteClass(classType, className)
+	if err != nil {
+		return err
+	}
+
+	terminal.Delta("Deleted [" + classType + "] class [" + className + "]!")
+
+	return nil
+}
+
+// validateClass checks that the edited JSON is a valid class of the same type
+func validateClass(class interface{}, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(reflect.New(reflect.TypeOf(class)).Interface())
+}
+
+// runEditor opens a file in the users editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// printDiff prints a line diff of two strings
+func printDiff(before, after string) {
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+
+	// Longest common subsequence of lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Println("  " + a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Println("+ " + b[j])
+			j++
+		default:
+			fmt.Println("- " + a[i])
+			i++
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/murdinc/awsm/models"
+)
+
+// AssetOptions are the optional filters of an asset list
+type AssetOptions struct {
+	Accounts []string // only list assets of these accounts
+}
+
+// query returns the query string values of the options
+func (o AssetOptions) query() url.Values {
+	query := url.Values{}
+	if len(o.Accounts) > 0 {
+		query.Set("account", strings.Join(o.Accounts, ","))
+	}
+	return query
+}
+
+// GetAssets decodes a list of assets of a type (eg: instances) into out, which should be a pointer to a slice of the
+// matching models type (eg: *[]models.Instance)
+func (c *Client) GetAssets(ctx context.Context, assetType string, opts AssetOptions, out interface{}) error {
+	return c.do(ctx, "GET", "/assets/"+assetType, opts.query(), nil, "assets", out)
+}
+
+// GetAssetsByAccount decodes a list of assets of a type grouped by account into out, which should be a pointer to a
+// map of slices of the matching models type (eg: *map[string][]models.Instance)
+func (c *Client) GetAssetsByAccount(ctx context.Context, assetType string, opts AssetOptions, out interface{}) error {
+	query := opts.query()
+	query.Set("groupBy", "account")
+	return c.do(ctx, "GET", "/assets/"+assetType, query, nil, "assets", out)
+}
+
+// GetAddresses returns a list of Elastic IP Addresses
+func (c *Client) GetAddresses(ctx context.Context, opts AssetOptions) ([]models.Address, error) {
+	var assets []models.Address
+	err := c.GetAssets(ctx, "addresses", opts, &assets)
+	return assets, err
+}
+
+// GetAlarms returns a list of CloudWatch Alarms
+func (c *Client) GetAlarms(ctx context.Context, opts AssetOptions) ([]models.Alarm, error) {
+	var assets []models.Alarm
+	err := c.GetAssets(ctx, "alarms", opts, &assets)
+	return assets, err
+}
+
+// GetAutoScaleGroups returns a list of AutoScaling Groups
+func (c *Client) GetAutoScaleGroups(ctx context.Context, opts AssetOptions) ([]models.AutoScaleGroup, error) {
+	var assets []models.AutoScaleGroup
+	err := c.GetAssets(ctx, "autoscalegroups", opts, &assets)
+	return assets, err
+}
+
+// GetBuckets returns a list of S3 Buckets
+func (c *Client) GetBuckets(ctx context.Context, opts AssetOptions) ([]models.Bucket, error) {
+	var assets []models.Bucket
+	err := c.GetAssets(ctx, "buckets", opts, &assets)
+	return assets, err
+}
+
+// GetIAMInstanceProfiles returns a list of IAM Instance Profiles
+func (c *Client) GetIAMInstanceProfiles(ctx context.Context, opts AssetOptions) ([]models.IAMInstanceProfile, error) {
+	var assets []models.IAMInstanceProfile
+	err := c.GetAssets(ctx, "iaminstanceprofiles", opts, &assets)
+	return assets, err
+}
+
+// GetIAMRoles returns a list of IAM Roles
+func (c *Client) GetIAMRoles(ctx context.Context, opts AssetOptions) ([]models.IAMRole, error) {
+	var assets []models.IAMRole
+	err := c.GetAssets(ctx, "iamroles", opts, &assets)
+	return assets, err
+}
+
+// GetIAMUsers returns a list of IAM Users
+func (c *Client) GetIAMUsers(ctx context.Context, opts AssetOptions) ([]models.IAMUser, error) {
+	var assets []models.IAMUser
+	err := c.GetAssets(ctx, "iamusers", opts, &assets)
+	return assets, err
+}
+
+// GetImages returns a list of AMI Images
+func (c *Client) GetImages(ctx context.Context, opts AssetOptions) ([]models.Image, error) {
+	var assets []models.Image
+	err := c.GetAssets(ctx, "images", opts, &assets)
+	return assets, err
+}
+
+// GetInstances returns a list of EC2 Instances, only the running ones if running is true
+func (c *Client) GetInstances(ctx context.Context, running bool, opts AssetOptions) ([]models.Instance, error) {
+	assetType := "instances"
+	if running {
+		assetType = "instances-running"
+	}
+
+	var assets []models.Instance
+	err := c.GetAssets(ctx, assetType, opts, &assets)
+	return assets, err
+}
+
+// GetKeyPairs returns a list of KeyPairs
+func (c *Client) GetKeyPairs(ctx context.Context, opts AssetOptions) ([]models.KeyPair, error) {
+	var assets []models.KeyPair
+	err := c.GetAssets(ctx, "keypairs", opts, &assets)
+	return assets, err
+}
+
+// GetLaunchConfigurations returns a list of Launch Configurations
+func (c *Client) GetLaunchConfigurations(ctx context.Context, opts AssetOptions) ([]models.LaunchConfig, error) {
+	var assets []models.LaunchConfig
+	err := c.GetAssets(ctx, "launchconfigurations", opts, &assets)
+	return assets, err
+}
+
+// GetLoadBalancers returns a list of Elastic Load Balancers
+func (c *Client) GetLoadBalancers(ctx context.Context, opts AssetOptions) ([]models.LoadBalancer, error) {
+	var assets []models.LoadBalancer
+	err := c.GetAssets(ctx, "loadbalancers", opts, &assets)
+	return assets, err
+}
+
+// GetScalingPolicies returns a list of Scaling Policies
+func (c *Client) GetScalingPolicies(ctx context.Context, opts AssetOptions) ([]models.ScalingPolicy, error) {
+	var assets []models.ScalingPolicy
+	err := c.GetAssets(ctx, "scalingpolicies", opts, &assets)
+	return assets, err
+}
+
+// GetSecurityGroups returns a list of Security Groups
+func (c *Client) GetSecurityGroups(ctx context.Context, opts AssetOptions) ([]models.SecurityGroup, error) {
+	var assets []models.SecurityGroup
+	err := c.GetAssets(ctx, "securitygroups", opts, &assets)
+	return assets, err
+}
+
+// GetSimpleDBDomains returns a list of SimpleDB Domains
+func (c *Client) GetSimpleDBDomains(ctx context.Context, opts AssetOptions) ([]models.SimpleDBDomain, error) {
+	var assets []models.SimpleDBDomain
+	err := c.GetAssets(ctx, "simpledbdomains", opts, &assets)
+	return assets, err
+}
+
+// GetSnapshots returns a list of EBS Snapshots
+func (c *Client) GetSnapshots(ctx context.Context, opts AssetOptions) ([]models.Snapshot, error) {
+	var assets []models.Snapshot
+	err := c.GetAssets(ctx, "snapshots", opts, &assets)
+	return assets, err
+}
+
+// GetSubnets returns a list of VPC Subnets
+func (c *Client) GetSubnets(ctx context.Context, opts AssetOptions) ([]models.Subnet, error) {
+	var assets []models.Subnet
+	err := c.GetAssets(ctx, "subnets", opts, &assets)
+	return assets, err
+}
+
+// GetVolumes returns a list of EBS Volumes
+func (c *Client) GetVolumes(ctx context.Context, opts AssetOptions) ([]models.Volume, error) {
+	var assets []models.Volume
+	err := c.GetAssets(ctx, "volumes", opts, &assets)
+	return assets, err
+}
+
+// GetVpcs returns a list of VPCs
+func (c *Client) GetVpcs(ctx context.Context, opts AssetOptions) ([]models.Vpc, error) {
+	var assets []models.Vpc
+	err := c.GetAssets(ctx, "vpcs", opts, &assets)
+	return assets, err
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/murdinc/awsm/config"
+)
+
+// ConflictError is returned by PutClass when the class has been changed since the revision it was based on
+type ConflictError struct {
+	*APIError
+	Revision int             // the current revision of the class
+	Current  json.RawMessage // the current class, to be merged and resubmitted
+}
+
+// Export represents all classes, as exported by the awsm API
+type Export struct {
+	Vpcs                 config.VpcClasses                 `json:"vpcs"`
+	Subnets              config.SubnetClasses              `json:"subnets"`
+	Instances            config.InstanceClasses            `json:"instances"`
+	Volumes              config.VolumeClasses              `json:"volumes"`
+	Snapshots            config.SnapshotClasses            `json:"snapshots"`
+	Images               config.ImageClasses               `json:"images"`
+	AutoScaleGroups      config.AutoscaleGroupClasses      `json:"autoscalegroups"`
+	LaunchConfigurations config.LaunchConfigurationClasses `json:"launchconfigurations"`
+	LoadBalancers        config.LoadBalancerClasses        `json:"loadbalancers"`
+	ScalingPolicies      config.ScalingPolicyClasses       `json:"scalingpolicies"`
+	Alarms               config.AlarmClasses               `json:"alarms"`
+	SecurityGroups       config.SecurityGroupClasses       `json:"securitygroups"`
+	KeyPairs             config.KeyPairClasses             `json:"keypairs"`
+	Widgets              config.Widgets                    `json:"widgets"`
+}
+
+// classQuery asks for the secrets of classes if the client has an admin token
+func (c *Client) classQuery() url.Values {
+	query := url.Values{}
+	if c.AdminToken != "" {
+		query.Set("secrets", "true")
+	}
+	return query
+}
+
+// ExportClasses returns all classes
+func (c *Client) ExportClasses(ctx context.Context) (*Export, error) {
+	export := new(Export)
+	err := c.do(ctx, "GET", "/classes/export", c.classQuery(), nil, "classes", export)
+	return export, err
+}
+
+// GetClasses decodes all classes of a type into out, which should be a pointer to the matching config type
+// (eg: *config.InstanceClasses for instances)
+func (c *Client) GetClasses(ctx context.Context, classType string, out interface{}) error {
+	return c.do(ctx, "GET", "/classes/"+classType, c.classQuery(), nil, "classes", out)
+}
+
+// GetClass decodes a single class into out, which should be a pointer to the matching config type
+// (eg: *config.InstanceClass for instances)
+func (c *Client) GetClass(ctx context.Context, classType, className string, out interface{}) error {
+	return c.do(ctx, "GET", "/classes/"+classType+"/name/"+url.PathEscape(className), c.classQuery(), nil, "class", out)
+}
+
+// GetClassNames returns the names of all classes of a type
+func (c *Client) GetClassNames(ctx context.Context, classType string) ([]string, error) {
+	var names []string
+	err := c.do(ctx, "GET", "/classes/"+classType+"/names", nil, nil, "classNames", &names)
+	return names, err
+}
+
+// GetClassOptions returns the options for the fields of a class type, like the names of the classes it can refer to
+func (c *Client) GetClassOptions(ctx context.Context, classType string) (map[string][]string, error) {
+	var options map[string][]string
+	err := c.do(ctx, "GET", "/classes/"+classType+"/options", nil, nil, "classOptions", &options)
+	return options, err
+}
+
+// PutClass saves a class and decodes the saved class into out, if out is not nil. A *ConflictError is returned if
+// the class has been changed since the revision it was loaded at.
+func (c *Client) PutClass(ctx context.Context, classType, className string, class interface{}, out interface{}) error {
+	return c.do(ctx, "PUT", "/classes/"+classType+"/name/"+url.PathEscape(className), nil, class, "class", out)
+}
+
+// DeleteClass deletes a class
+func (c *Client) DeleteClass(ctx context.Context, classType, className string) error {
+	return c.do(ctx, "DELETE", "/classes/"+classType+"/name/"+url.PathEscape(className), nil, nil, "", nil)
+}
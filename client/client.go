@@ -0,0 +1,116 @@
+// Package client is a Go client for the awsm API server, see `awsm api`
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a client for the awsm API server
+type Client struct {
+	BaseURL    string // eg: http://localhost:8081
+	HTTPClient *http.Client
+	AdminToken string // if set, classes are requested with their secrets decrypted (see AWSM_ADMIN_TOKEN)
+}
+
+// New returns a new Client for the awsm API server at baseURL
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// APIError represents the errors of a `success: false` response
+type APIError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("The awsm API request failed with status [%d]!", e.StatusCode)
+	}
+	return strings.Join(e.Errors, " ")
+}
+
+// envelope is the response wrapper of every awsm API endpoint
+type envelope map[string]json.RawMessage
+
+// do sends a request and decodes the field named key of the response envelope into out, if out is not nil and the
+// request succeeded. Failed responses leave out untouched, a conflicting class is in ConflictError.Current instead.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, key string, out interface{}) error {
+	u := c.BaseURL + "/api" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.AdminToken != "" {
+		req.Header.Set("X-Awsm-Admin-Token", c.AdminToken)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	err = json.NewDecoder(resp.Body).Decode(&env)
+	if err != nil {
+		return errors.New("Unable to decode the awsm API response from [" + u + "]: " + err.Error())
+	}
+
+	var success bool
+	json.Unmarshal(env["success"], &success)
+	if success {
+		if out != nil && len(env[key]) > 0 {
+			err = json.Unmarshal(env[key], out)
+			if err != nil {
+				return errors.New("Unable to decode [" + key + "] from the awsm API response: " + err.Error())
+			}
+		}
+		return nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	json.Unmarshal(env["errors"], &apiErr.Errors)
+
+	if resp.StatusCode == http.StatusConflict {
+		conflict := &ConflictError{APIError: apiErr, Current: env["class"]}
+		json.Unmarshal(env["revision"], &conflict.Revision)
+		return conflict
+	}
+
+	return apiErr
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/murdinc/awsm/config"
+	"github.com/murdinc/awsm/models"
+)
+
+// GetWidgets returns all dashboard widgets
+func (c *Client) GetWidgets(ctx context.Context) (config.Widgets, error) {
+	var widgets config.Widgets
+	err := c.do(ctx, "GET", "/dashboard/widgets", nil, nil, "widgets", &widgets)
+	return widgets, err
+}
+
+// GetWidgetNames returns the names of all dashboard widgets
+func (c *Client) GetWidgetNames(ctx context.Context) ([]string, error) {
+	var names []string
+	err := c.do(ctx, "GET", "/dashboard/widgets/names", nil, nil, "widgetNames", &names)
+	return names, err
+}
+
+// GetWidget returns a single dashboard widget
+func (c *Client) GetWidget(ctx context.Context, widgetName string) (config.Widget, error) {
+	var widget config.Widget
+	err := c.do(ctx, "GET", "/dashboard/widgets/name/"+url.PathEscape(widgetName), nil, nil, "widget", &widget)
+	return widget, err
+}
+
+// GetWidgetOptions returns the options for dashboard widgets, like the available widget types
+func (c *Client) GetWidgetOptions(ctx context.Context) (map[string][]string, error) {
+	var options map[string][]string
+	err := c.do(ctx, "GET", "/dashboard/widgets/options", nil, nil, "options", &options)
+	return options, err
+}
+
+// PutWidget saves a dashboard widget and returns it as saved
+func (c *Client) PutWidget(ctx context.Context, widgetName string, widget config.Widget) (config.Widget, error) {
+	var saved config.Widget
+	err := c.do(ctx, "PUT", "/dashboard/widgets/name/"+url.PathEscape(widgetName), nil, widget, "widget", &saved)
+	return saved, err
+}
+
+// DeleteWidget deletes a dashboard widget
+func (c *Client) DeleteWidget(ctx context.Context, widgetName string) error {
+	return c.do(ctx, "DELETE", "/dashboard/widgets/name/"+url.PathEscape(widgetName), nil, nil, "", nil)
+}
+
+// GetEvents returns the current AWS service health events
+func (c *Client) GetEvents(ctx context.Context) ([]models.Event, error) {
+	var events []models.Event
+	err := c.do(ctx, "GET", "/dashboard/widgets/events", nil, nil, "events", &events)
+	return events, err
+}
+
+// GetFeed returns the items of the RSS feed of a dashboard widget
+func (c *Client) GetFeed(ctx context.Context, feedName string) (config.FeedItems, error) {
+	var items config.FeedItems
+	err := c.do(ctx, "GET", "/dashboard/widgets/feed/"+url.PathEscape(feedName), nil, nil, "feed", &items)
+	return items, err
+}
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -26,6 +26,8 @@ type AlarmClass struct {
 	ComparisonOperator      string   `json:"comparisonOperator" awsmClass:"Comparison Operator"`
 	ActionsEnabled          bool     `json:"actionsEnabled" awsmClass:"Actions Enabled"`
 	Unit                    string   `json:"unit" awsmClass:"Unit"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultAlarms returns the defauly Alarm Classes
@@ -70,7 +72,7 @@ func SaveAlarmClass(className string, data []byte) (class AlarmClass, err error)
 		return
 	}
 
-	err = Insert("alarms", AlarmClasses{className: class})
+	class.Revision, err = Update("alarms", className, class)
 	return
 }
 
@@ -82,8 +84,8 @@ func LoadAlarmClass(name string) (AlarmClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllAlarmClasses loads all Alarm Classes
@@ -94,64 +96,21 @@ func LoadAllAlarmClasses() (AlarmClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts the items from simpledb into an AlarmClass struct
-func (c AlarmClasses) Marshal(items []*simpledb.Item) {
+func (c AlarmClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "alarms/", "", -1)
 		cfg := new(AlarmClass)
-
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "AlarmDescription":
-				cfg.AlarmDescription = val
-
-			case "OKActions":
-				cfg.OKActions = append(cfg.OKActions, val)
-
-			case "AlarmActions":
-				cfg.AlarmActions = append(cfg.AlarmActions, val)
-
-			case "InsufficientDataActions":
-				cfg.InsufficientDataActions = append(cfg.InsufficientDataActions, val)
-
-			case "MetricName":
-				cfg.MetricName = val
-
-			case "Namespace":
-				cfg.Namespace = val
-
-			case "Statistic":
-				cfg.Statistic = val
-
-			case "Period":
-				cfg.Period, _ = strconv.Atoi(val)
-
-			case "EvaluationPeriods":
-				cfg.EvaluationPeriods, _ = strconv.Atoi(val)
-
-			case "Threshold":
-				cfg.Threshold, _ = strconv.ParseFloat(val, 64)
-
-			case "ComparisonOperator":
-				cfg.ComparisonOperator = val
-
-			case "ActionsEnabled":
-				cfg.ActionsEnabled, _ = strconv.ParseBool(val)
-
-			case "Unit":
-				cfg.Unit = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Alarm class [" + name + "]: " + err.Error())
 		}
-
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -25,6 +25,16 @@ type AutoscaleGroupClass struct {
 	TerminationPolicies      []string `json:"terminationPolicies" awsmClass:"Termination Policies"`
 	LoadBalancerNames        []string `json:"loadBalancerNames" awsmClass:"Load Balancer Names"`
 	Alarms                   []string `json:"alarms" awsmClass:"Alarms"`
+
+	// Schedule, with awsm scheduler, stopping sets the capacity to zero and starting restores it
+	ScheduleStart    string `json:"scheduleStart" awsmClass:"Schedule Start"`       // cron expression, eg: 0 8 * * mon-fri
+	ScheduleStop     string `json:"scheduleStop" awsmClass:"Schedule Stop"`         // cron expression, eg: 0 19 * * mon-fri
+	ScheduleTimezone string `json:"scheduleTimezone" awsmClass:"Schedule Timezone"` // eg: America/Los_Angeles, UTC if empty
+
+	// Custom Tags, applied to the AutoScale Group and propagated to its Instances, reconciled by updateAutoScaleGroups
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultAutoscaleGroupClasses returns the default Autoscale Group Classes
@@ -56,7 +66,12 @@ func SaveAutoscalingGroupClass(className string, data []byte) (class AutoscaleGr
 		return
 	}
 
-	err = Insert("autoscalegroups", AutoscaleGroupClasses{className: class})
+	err = validateSchedule(class.ScheduleStart, class.ScheduleStop, class.ScheduleTimezone)
+	if err != nil {
+		return
+	}
+
+	class.Revision, err = Update("autoscalegroups", className, class)
 	return
 }
 
@@ -68,8 +83,8 @@ func LoadAutoscalingGroupClass(name string) (AutoscaleGroupClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllAutoscalingGroupClasses loads all Autoscaling Group Classes
@@ -80,59 +95,21 @@ func LoadAllAutoscalingGroupClasses() (AutoscaleGroupClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts the items from simpledb into an AutoscaleGroupClass struct
-func (c AutoscaleGroupClasses) Marshal(items []*simpledb.Item) {
+func (c AutoscaleGroupClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "autoscalegroups/", "", -1)
 		cfg := new(AutoscaleGroupClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "LaunchConfigurationClass":
-				cfg.LaunchConfigurationClass = val
-
-			case "AvailabilityZones":
-				cfg.AvailabilityZones = append(cfg.AvailabilityZones, val)
-
-			case "DesiredCapacity":
-				cfg.DesiredCapacity, _ = strconv.Atoi(val)
-
-			case "MinSize":
-				cfg.MinSize, _ = strconv.Atoi(val)
-
-			case "MaxSize":
-				cfg.MaxSize, _ = strconv.Atoi(val)
-
-			case "DefaultCooldown":
-				cfg.DefaultCooldown, _ = strconv.Atoi(val)
-
-			case "SubnetClass":
-				cfg.SubnetClass = val
-
-			case "HealthCheckType":
-				cfg.HealthCheckType = val
-
-			case "HealthCheckGracePeriod":
-				cfg.HealthCheckGracePeriod, _ = strconv.Atoi(val)
-
-			case "TerminationPolicies":
-				cfg.TerminationPolicies = append(cfg.TerminationPolicies, val)
-
-			case "LoadBalancerNames":
-				cfg.LoadBalancerNames = append(cfg.LoadBalancerNames, val)
-
-			case "Alarms":
-				cfg.Alarms = append(cfg.Alarms, val)
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode AutoScale Group class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,187 +2,238 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/simpledb"
 	"github.com/murdinc/awsm/aws/regions"
-	"github.com/satori/go.uuid"
+	"github.com/murdinc/awsm/logger"
 )
 
 // DeleteClass deletes a class from SimpleDB
 func DeleteClass(classType, className string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	itemName := classType + "/" + className
 
 	params := &simpledb.DeleteAttributesInput{
-		DomainName: aws.String("awsm"),
+		DomainName: aws.String(storeDomain()),
 		ItemName:   aws.String(itemName),
 	}
 
-	//terminal.Delta("Deleting [" + itemName + "] Configuration...")
+	// Delete the child items (Security Group Grants, Load Balancer Listeners)
+	if class, _ := LoadClassByName(classType, className); class != nil {
+		for _, childType := range childItemTypes(reflect.TypeOf(class)) {
+			DeleteItemsByType(itemName + "/" + childType.name)
+		}
+	}
+
+	logger.Debug("Deleting class", "item", itemName)
 	_, err := svc.DeleteAttributes(params)
 	if err != nil {
 		return err
 	}
 
-	//terminal.Information("Done!")
-
 	return nil
 }
 
 // Insert inserts Classes into SimpleDB
 func Insert(classType string, classInterface interface{}) error {
 
-	var itemName string
+	classes := reflect.ValueOf(classInterface)
+	if classes.Kind() != reflect.Map || classes.Type().Key().Kind() != reflect.String || classes.Type().Elem().Kind() != reflect.Struct {
+		return errors.New("Insert does not support [" + classes.Type().String() + "]! No configurations of this type are being installed!")
+	}
+
 	itemsMap := make(map[string][]*simpledb.ReplaceableAttribute)
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	// Build Attributes
-	switch classType {
-	case "vpcs":
-		for class, config := range classInterface.(VpcClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+	for _, class := range classes.MapKeys() {
+		itemName := classType + "/" + class.String()
 
-	case "subnets":
-		for class, config := range classInterface.(SubnetClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+		// Blind inserts still bump the stored revision, so that edits based on the previous revision are rejected
+		revision, err := storedRevision(classType, class.String())
+		if err != nil {
+			return err
 		}
+		config := withRevision(classes.MapIndex(class).Interface(), revision+1)
 
-	case "instances":
-		for class, config := range classInterface.(InstanceClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+		// Delete the existing child items (Security Group Grants, Load Balancer Listeners)
+		for _, childType := range childItemTypes(classes.Type().Elem()) {
+			DeleteItemsByType(itemName + "/" + childType.name)
 		}
 
-	case "volumes":
-		for class, config := range classInterface.(VolumeClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+		for name, attributes := range buildItems(itemName, classType, config) {
+			itemsMap[name] = append(itemsMap[name], attributes...)
 		}
+	}
 
-	case "snapshots":
-		for class, config := range classInterface.(SnapshotClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+	items := make([]*simpledb.ReplaceableItem, 0, len(itemsMap))
 
-	case "images":
-		for class, config := range classInterface.(ImageClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+	for item, attributes := range itemsMap {
 
-	case "autoscalegroups":
-		for class, config := range classInterface.(AutoscaleGroupClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+		i := &simpledb.ReplaceableItem{
+			Attributes: attributes,
+			Name:       aws.String(item),
 		}
+		items = append(items, i)
 
-	case "launchconfigurations":
-		for class, config := range classInterface.(LaunchConfigurationClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+	}
 
-	case "loadbalancers":
-		for class, config := range classInterface.(LoadBalancerClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+	params := &simpledb.BatchPutAttributesInput{
+		DomainName: aws.String(storeDomain()),
+		Items:      items,
+	}
+	logger.Debug("Inserting classes", "classType", classType, "items", len(items))
+	_, err := svc.BatchPutAttributes(params)
 
-			// Delete the existing listeners
-			DeleteItemsByType(classType + "/" + class + "/listeners")
+	if err != nil {
+		return err
+	}
 
-			// Load Balancer Listeners
-			for _, listener := range config.LoadBalancerListeners {
-				itemName = classType + "/" + class + "/listeners/" + uuid.Must(uuid.NewV4()).String()
-				itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(listener, classType+"/"+class+"/listeners")...)
-			}
-		}
+	return nil
 
-	case "scalingpolicies":
-		for class, config := range classInterface.(ScalingPolicyClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+}
 
-	case "alarms":
-		for class, config := range classInterface.(AlarmClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
-		}
+// ConflictError is returned by Update when a class has been changed since the revision the update was based on
+type ConflictError struct {
+	ClassType string
+	ClassName string
+	Revision  int
+}
 
-	case "securitygroups":
-		for class, config := range classInterface.(SecurityGroupClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("The [%s] class [%s] has been changed by someone else and is now at revision [%d]!", e.ClassType, e.ClassName, e.Revision)
+}
 
-			// Delete the existing grants
-			DeleteItemsByType(classType + "/" + class + "/grants")
+// Update inserts a single class into SimpleDB only if it is still at the revision it was based on, and returns its new revision
+func Update(classType, className string, class interface{}) (int, error) {
 
-			// Security Group Grants
-			for _, grant := range config.SecurityGroupGrants {
-				itemName = classType + "/" + class + "/grants/" + uuid.Must(uuid.NewV4()).String()
-				itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(grant, classType+"/"+class+"/grants")...)
-			}
-		}
+	sess := storeSession()
+	svc := simpledb.New(sess)
 
-	case "keypairs":
-		for class, config := range classInterface.(KeyPairClasses) {
-			itemName = classType + "/" + class
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+	itemName := classType + "/" + className
+	revision := classRevision(class)
+
+	itemsMap := buildItems(itemName, classType, withRevision(class, revision+1))
+
+	// Classes that have never been saved (or were saved before revisions existed) have no Revision attribute
+	expected := &simpledb.UpdateCondition{Name: aws.String("Revision")}
+	if revision > 0 {
+		expected.Value = aws.String(fmt.Sprint(revision))
+	} else {
+		expected.Exists = aws.Bool(false)
+	}
+
+	_, err := svc.PutAttributes(&simpledb.PutAttributesInput{
+		DomainName: aws.String(storeDomain()),
+		ItemName:   aws.String(itemName),
+		Attributes: itemsMap[itemName],
+		Expected:   expected,
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && (awsErr.Code() == "ConditionalCheckFailed" || awsErr.Code() == "AttributeDoesNotExist") {
+			return revision, ConflictError{ClassType: classType, ClassName: className, Revision: currentRevision(classType, className)}
 		}
+		return revision, err
+	}
 
-	case "widgets":
-		for widget, config := range classInterface.(Widgets) {
-			itemName = classType + "/" + widget
-			itemsMap[itemName] = append(itemsMap[itemName], BuildAttributes(config, classType)...)
+	// Empty slices and maps have no attributes to replace the stored ones with, so they are deleted instead
+	if missing := missingAttributes(reflect.TypeOf(class), itemsMap[itemName]); len(missing) > 0 {
+		_, err = svc.DeleteAttributes(&simpledb.DeleteAttributesInput{
+			DomainName: aws.String(storeDomain()),
+			ItemName:   aws.String(itemName),
+			Attributes: missing,
+			Expected:   &simpledb.UpdateCondition{Name: aws.String("Revision"), Value: aws.String(fmt.Sprint(revision + 1))},
+		})
+		if err != nil {
+			return revision + 1, err
 		}
+	}
 
-	default:
-		return errors.New("Insert does not have switch for [" + classType + "]! No configurations of this type are being installed!")
+	delete(itemsMap, itemName)
 
+	// Replace the child items (Security Group Grants, Load Balancer Listeners)
+	for _, childType := range childItemTypes(reflect.TypeOf(class)) {
+		DeleteItemsByType(itemName + "/" + childType.name)
 	}
 
-	items := make([]*simpledb.ReplaceableItem, len(itemsMap))
+	if len(itemsMap) > 0 {
+		items := make([]*simpledb.ReplaceableItem, 0, len(itemsMap))
+		for item, attributes := range itemsMap {
+			items = append(items, &simpledb.ReplaceableItem{
+				Attributes: attributes,
+				Name:       aws.String(item),
+			})
+		}
 
-	for item, attributes := range itemsMap {
+		_, err = svc.BatchPutAttributes(&simpledb.BatchPutAttributesInput{
+			DomainName: aws.String(storeDomain()),
+			Items:      items,
+		})
+		if err != nil {
+			return revision + 1, err
+		}
+	}
 
-		//terminal.Delta("Building Configuration for [" + item + "]...")
+	return revision + 1, nil
+}
 
-		i := &simpledb.ReplaceableItem{
-			Attributes: attributes,
-			Name:       aws.String(item),
-		}
-		items = append(items, i)
+// currentRevision returns the stored revision of a class, or 0 if it can not be read
+func currentRevision(classType, className string) int {
+	revision, _ := storedRevision(classType, className)
+	return revision
+}
 
+// storedRevision returns the stored revision of a class, 0 if the class is not stored yet
+func storedRevision(classType, className string) (int, error) {
+	item, err := GetItemByName(classType, className)
+	if _, ok := err.(NotFoundError); ok {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
 	}
 
-	params := &simpledb.BatchPutAttributesInput{
-		DomainName: aws.String("awsm"),
-		Items:      items,
+	for _, attribute := range item.Attributes {
+		if aws.StringValue(attribute.Name) == "Revision" {
+			revision, _ := strconv.Atoi(aws.StringValue(attribute.Value))
+			return revision, nil
+		}
 	}
-	//terminal.Delta("Installing [" + classType + "] Configurations...")
-	_, err := svc.BatchPutAttributes(params)
 
-	if err != nil {
-		return err
+	return 0, nil
+}
+
+// classRevision returns the Revision field of a class struct
+func classRevision(class interface{}) int {
+	field := reflect.ValueOf(class).FieldByName("Revision")
+	if !field.IsValid() || field.Kind() != reflect.Int {
+		return 0
 	}
+	return int(field.Int())
+}
 
-	//terminal.Information("Done!")
+// withRevision returns a copy of a class struct with its Revision field set
+func withRevision(class interface{}, revision int) interface{} {
+	val := reflect.New(reflect.TypeOf(class)).Elem()
+	val.Set(reflect.ValueOf(class))
 
-	return nil
+	field := val.FieldByName("Revision")
+	if field.IsValid() && field.Kind() == reflect.Int {
+		field.SetInt(int64(revision))
+	}
 
+	return val.Interface()
 }
 
 // Export exports all configurations
@@ -316,6 +367,58 @@ func LoadClassByName(classType, className string) (configs interface{}, err erro
 	return configs, err
 }
 
+// SaveClass unmarshals a byte slice into a class by its type and name and inserts it into the db
+func SaveClass(classType, className string, data []byte) (class interface{}, err error) {
+
+	switch classType {
+
+	case "vpcs":
+		return SaveVpcClass(className, data)
+
+	case "subnets":
+		return SaveSubnetClass(className, data)
+
+	case "instances":
+		return SaveInstanceClass(className, data)
+
+	case "volumes":
+		return SaveVolumeClass(className, data)
+
+	case "snapshots":
+		return SaveSnapshotClass(className, data)
+
+	case "images":
+		return SaveImageClass(className, data)
+
+	case "autoscalegroups":
+		return SaveAutoscalingGroupClass(className, data)
+
+	case "launchconfigurations":
+		return SaveLaunchConfigurationClass(className, data)
+
+	case "loadbalancers":
+		return SaveLoadBalancerClass(className, data)
+
+	case "scalingpolicies":
+		return SaveScalingPolicyClass(className, data)
+
+	case "alarms":
+		return SaveAlarmClass(className, data)
+
+	case "securitygroups":
+		return SaveSecurityGroupClass(className, data)
+
+	case "keypairs":
+		return SaveKeyPairClass(className, data)
+
+	default:
+		err = errors.New("SaveClass does not have switch for [" + classType + "]! No class configuration of this type is being saved!")
+
+	}
+
+	return class, err
+}
+
 // LoadAllClassOptions loads all class options by a type
 func LoadAllClassOptions(classType string) (options map[string]interface{}, err error) {
 
@@ -375,10 +478,10 @@ func LoadAllClassOptions(classType string) (options map[string]interface{}, err
 
 			switch key {
 			case "regions":
-				options[key] = regions.GetRegionNameList()
+				options[key], _ = regions.GetRegionNameList()
 
 			case "zones":
-				options[key] = regions.GetAZNameList()
+				options[key], _ = regions.GetAZNameList()
 
 			default:
 				options[key], _ = LoadAllClassNames(key)
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/satori/go.uuid"
+)
+
+// Classes are stored in SimpleDB as one item per class, with one attribute per field, named after the field.
+// - Slices are stored as multi-valued attributes
+// - Maps of strings (like Tags) are stored as multi-valued attributes of JSON encoded ["key","value"] pairs, since keys
+//   may contain "=". Pairs stored as key=value by earlier versions are still read.
+// - Nested structs are flattened into the attributes of their parent
+// - Slices of structs tagged `awsmItems:"<name>"` are stored as child items named <item>/<name>/<uuid>
+// - Fields tagged `awsm:"ignore"` are not stored, fields tagged `awsm:"id"` receive the uuid of their child item
+
+const timeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// BuildAttributes builds SimpleDB item attributes from class structs
+func BuildAttributes(class interface{}, classType string) []*simpledb.ReplaceableAttribute {
+
+	attributes := encodeFields(reflect.ValueOf(class))
+
+	attributes = append(attributes, &simpledb.ReplaceableAttribute{
+		Name:    aws.String("classType"),
+		Value:   aws.String(classType),
+		Replace: aws.Bool(true),
+	})
+
+	return attributes
+}
+
+// encodeFields builds SimpleDB item attributes from the fields of a struct, flattening nested structs
+func encodeFields(val reflect.Value) (attributes []*simpledb.ReplaceableAttribute) {
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := val.Field(i)
+
+		if field.PkgPath != "" || skipField(field) {
+			continue
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct && field.Type != timeType:
+			attributes = append(attributes, encodeFields(fieldVal)...)
+
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8:
+			if field.Type.Elem().Kind() == reflect.Struct && field.Type.Elem() != timeType {
+				// Stored as child items, see buildItems
+				continue
+			}
+			for s := 0; s < fieldVal.Len(); s++ {
+				attributes = append(attributes, &simpledb.ReplaceableAttribute{
+					Name:    aws.String(field.Name),
+					Value:   aws.String(encodeValue(fieldVal.Index(s))),
+					Replace: aws.Bool(true),
+				})
+			}
+
+		case field.Type.Kind() == reflect.Map:
+			keys := fieldVal.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, key := range keys {
+				attributes = append(attributes, &simpledb.ReplaceableAttribute{
+					Name:    aws.String(field.Name),
+					Value:   aws.String(encodePair(key.String(), encodeValue(fieldVal.MapIndex(key)))),
+					Replace: aws.Bool(true),
+				})
+			}
+
+		default:
+			attributes = append(attributes, &simpledb.ReplaceableAttribute{
+				Name:    aws.String(field.Name),
+				Value:   aws.String(encodeValue(fieldVal)),
+				Replace: aws.Bool(true),
+			})
+		}
+	}
+
+	return attributes
+}
+
+// attributeNames returns the names of the attributes the fields of a struct are stored as, flattening nested structs
+func attributeNames(typ reflect.Type) (names []string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.PkgPath != "" || skipField(field) {
+			continue
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct && field.Type != timeType:
+			names = append(names, attributeNames(field.Type)...)
+
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct && field.Type.Elem() != timeType:
+			// Stored as child items, see buildItems
+
+		default:
+			names = append(names, field.Name)
+		}
+	}
+
+	return names
+}
+
+// missingAttributes returns the attributes of a struct that an encoding has no values for, like empty slices and maps
+func missingAttributes(typ reflect.Type, attributes []*simpledb.ReplaceableAttribute) []*simpledb.DeletableAttribute {
+	present := make(map[string]bool)
+	for _, attribute := range attributes {
+		present[aws.StringValue(attribute.Name)] = true
+	}
+
+	var missing []*simpledb.DeletableAttribute
+	for _, name := range attributeNames(typ) {
+		if !present[name] {
+			missing = append(missing, &simpledb.DeletableAttribute{Name: aws.String(name)})
+		}
+	}
+
+	return missing
+}
+
+// encodeValue formats a single scalar value for SimpleDB
+func encodeValue(val reflect.Value) string {
+	if val.Type() == timeType {
+		return val.Interface().(time.Time).UTC().String()
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String()
+	case reflect.Slice: // []byte
+		return string(val.Bytes())
+	}
+
+	return fmt.Sprint(val.Interface())
+}
+
+// encodePair encodes a map entry as a JSON array of its key and value
+func encodePair(key, value string) string {
+	pair, _ := json.Marshal([]string{key, value})
+	return string(pair)
+}
+
+// decodePair decodes a map entry stored by encodePair, or as key=value by earlier versions
+func decodePair(value string) (key, elem string, err error) {
+	var pair []string
+	if strings.HasPrefix(value, "[") && json.Unmarshal([]byte(value), &pair) == nil && len(pair) == 2 {
+		return pair[0], pair[1], nil
+	}
+
+	legacy := strings.SplitN(value, "=", 2)
+	if len(legacy) != 2 {
+		return "", "", errors.New("expected a [\"key\",\"value\"] pair, not [" + value + "]")
+	}
+	return legacy[0], legacy[1], nil
+}
+
+// skipField returns true if a field should never be stored as an attribute
+func skipField(field reflect.StructField) bool {
+	tag := field.Tag.Get("awsm")
+	return tag == "ignore" || tag == "id"
+}
+
+// buildItems builds the SimpleDB item of a class, along with the child items of its slices of structs
+func buildItems(itemName, classType string, class interface{}) map[string][]*simpledb.ReplaceableAttribute {
+	itemsMap := make(map[string][]*simpledb.ReplaceableAttribute)
+	itemsMap[itemName] = BuildAttributes(class, classType)
+
+	val := reflect.ValueOf(class)
+	typ := val.Type()
+
+	for _, childType := range childItemTypes(typ) {
+		children := val.FieldByName(childType.field)
+		for i := 0; i < children.Len(); i++ {
+			childName := itemName + "/" + childType.name + "/" + uuid.Must(uuid.NewV4()).String()
+			itemsMap[childName] = BuildAttributes(children.Index(i).Interface(), itemName+"/"+childType.name)
+		}
+	}
+
+	return itemsMap
+}
+
+// childItemType is a slice of structs stored as child items
+type childItemType struct {
+	field string
+	name  string
+}
+
+// childItemTypes returns the fields of a class that are stored as child items
+func childItemTypes(typ reflect.Type) (childTypes []childItemType) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := field.Tag.Get("awsmItems")
+		if name != "" && field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			childTypes = append(childTypes, childItemType{field: field.Name, name: name})
+		}
+	}
+	return
+}
+
+// DecodeItem puts the attributes of a SimpleDB item, and any of its child items, into the class struct that out points to
+func DecodeItem(item *simpledb.Item, out interface{}) error {
+	return decodeItem(item, out, selectItemsByType)
+}
+
+// decodeItem is DecodeItem with the lookup of the child items of a type passed in
+func decodeItem(item *simpledb.Item, out interface{}, getItems func(classType string) ([]*simpledb.Item, error)) error {
+	val := reflect.ValueOf(out)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return errors.New("DecodeItem expects a pointer to a struct, not [" + val.Type().String() + "]!")
+	}
+	val = val.Elem()
+
+	err := decodeAttributes(item.Attributes, val)
+	if err != nil {
+		return err
+	}
+
+	itemName := aws.StringValue(item.Name)
+
+	for _, childType := range childItemTypes(val.Type()) {
+		childPrefix := itemName + "/" + childType.name
+		childItems, err := getItems(childPrefix)
+		if err != nil {
+			return errors.New("Unable to load the [" + childType.name + "] of [" + itemName + "]: " + err.Error())
+		}
+
+		children := val.FieldByName(childType.field)
+		children.Set(reflect.MakeSlice(children.Type(), len(childItems), len(childItems)))
+
+		for i, childItem := range childItems {
+			child := children.Index(i)
+			err := decodeAttributes(childItem.Attributes, child)
+			if err != nil {
+				return err
+			}
+			setItemID(child, strings.TrimPrefix(aws.StringValue(childItem.Name), childPrefix+"/"))
+		}
+	}
+
+	return nil
+}
+
+// decodeAttributes sets the fields of a struct from SimpleDB attributes
+func decodeAttributes(attributes []*simpledb.Attribute, val reflect.Value) error {
+	fields := attributeFields(val.Type(), nil)
+
+	for _, attribute := range attributes {
+		index, ok := fields[aws.StringValue(attribute.Name)]
+		if !ok {
+			// classType, or a field that no longer exists
+			continue
+		}
+
+		field := val.FieldByIndex(index)
+		err := decodeValue(field, aws.StringValue(attribute.Value))
+		if err != nil {
+			return errors.New("Unable to decode attribute [" + aws.StringValue(attribute.Name) + "]: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// attributeFields maps attribute names to the index of their field, including the fields of nested structs
+func attributeFields(typ reflect.Type, parent []int) map[string][]int {
+	fields := make(map[string][]int)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || skipField(field) {
+			continue
+		}
+
+		index := append(append([]int{}, parent...), i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			for name, nestedIndex := range attributeFields(field.Type, index) {
+				fields[name] = nestedIndex
+			}
+			continue
+		}
+
+		fields[field.Name] = index
+	}
+
+	return fields
+}
+
+// decodeValue sets a single field from a SimpleDB attribute value, appending to slices
+func decodeValue(field reflect.Value, value string) error {
+	if field.Type() == timeType {
+		t, err := time.Parse(timeLayout, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			field.SetBytes([]byte(value))
+			return nil
+		}
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		err := decodeValue(elem, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.Append(field, elem))
+
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String {
+			return errors.New("unsupported type [" + field.Type().String() + "]")
+		}
+
+		key, elemValue, err := decodePair(value)
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		err = decodeValue(elem, elemValue)
+		if err != nil {
+			return err
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(key).Convert(field.Type().Key()), elem)
+
+	default:
+		return errors.New("unsupported type [" + field.Type().String() + "]")
+	}
+
+	return nil
+}
+
+// setItemID sets the field tagged `awsm:"id"` of a child item struct
+func setItemID(val reflect.Value, id string) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("awsm") == "id" && typ.Field(i).Type.Kind() == reflect.String {
+			val.Field(i).SetString(id)
+		}
+	}
+}
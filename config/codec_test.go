@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/simpledb"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		classType string
+		class     interface{}
+	}{
+		{"instances", InstanceClass{}},
+		{"volumes", VolumeClass{}},
+		{"snapshots", SnapshotClass{}},
+		{"images", ImageClass{}},
+		{"vpcs", VpcClass{}},
+		{"subnets", SubnetClass{}},
+		{"securitygroups", SecurityGroupClass{}},
+		{"loadbalancers", LoadBalancerClass{}},
+		{"autoscalegroups", AutoscaleGroupClass{}},
+		{"launchconfigurations", LaunchConfigurationClass{}},
+		{"scalingpolicies", ScalingPolicyClass{}},
+		{"alarms", AlarmClass{}},
+		{"keypairs", KeyPairClass{}},
+		{"widgets", Widget{}},
+		{"feeditems", FeedItem{}},
+	}
+
+	for _, test := range tests {
+		typ := reflect.TypeOf(test.class)
+
+		in := reflect.New(typ).Elem()
+		fillValue(in, 1)
+
+		itemName := test.classType + "/test"
+		item, getItems := encodeItems(buildItems(itemName, test.classType, in.Interface()), itemName)
+
+		out := reflect.New(typ)
+		err := decodeItem(item, out.Interface(), getItems)
+		if err != nil {
+			t.Errorf("%s: decode failed: %s", typ.Name(), err)
+			continue
+		}
+
+		checkItemIDs(t, typ.Name(), out.Elem())
+		sortChildItems(in)
+		sortChildItems(out.Elem())
+
+		if !reflect.DeepEqual(in.Interface(), out.Elem().Interface()) {
+			t.Errorf("%s: round trip mismatch\n  in: %+v\n out: %+v", typ.Name(), in.Interface(), out.Elem().Interface())
+		}
+	}
+}
+
+func TestMapPairs(t *testing.T) {
+	in := VolumeClass{Tags: map[string]string{"a=b": "c=d", "[e]": "f"}}
+
+	item, getItems := encodeItems(buildItems("volumes/test", "volumes", in), "volumes/test")
+
+	var out VolumeClass
+	err := decodeItem(item, &out, getItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in.Tags, out.Tags) {
+		t.Errorf("tags mismatch\n  in: %v\n out: %v", in.Tags, out.Tags)
+	}
+
+	// Pairs stored as key=value by earlier versions
+	legacy := &simpledb.Item{
+		Name: aws.String("volumes/legacy"),
+		Attributes: []*simpledb.Attribute{
+			{Name: aws.String("Tags"), Value: aws.String("Name=web=1")},
+			{Name: aws.String("Tags"), Value: aws.String("[env]=prod")},
+		},
+	}
+
+	out = VolumeClass{}
+	err = decodeItem(legacy, &out, getItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"Name": "web=1", "[env]": "prod"}; !reflect.DeepEqual(out.Tags, want) {
+		t.Errorf("legacy tags are %v, expected %v", out.Tags, want)
+	}
+}
+
+func TestMissingAttributes(t *testing.T) {
+	typ := reflect.TypeOf(InstanceClass{})
+
+	// A filled class has a value for every attribute
+	in := reflect.New(typ).Elem()
+	fillValue(in, 1)
+	if missing := missingAttributes(typ, BuildAttributes(in.Interface(), "instances")); len(missing) > 0 {
+		t.Errorf("expected no missing attributes for a filled class, got %d, the first is [%s]", len(missing), aws.StringValue(missing[0].Name))
+	}
+
+	// Empty slices and maps have none
+	in.FieldByName("SecurityGroups").Set(reflect.Zero(in.FieldByName("SecurityGroups").Type()))
+	in.FieldByName("Tags").Set(reflect.Zero(in.FieldByName("Tags").Type()))
+
+	var names []string
+	for _, attribute := range missingAttributes(typ, BuildAttributes(in.Interface(), "instances")) {
+		names = append(names, aws.StringValue(attribute.Name))
+	}
+	sort.Strings(names)
+
+	if want := []string{"SecurityGroups", "Tags"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("missing attributes are %v, expected %v", names, want)
+	}
+}
+
+func TestMarshalDecodeError(t *testing.T) {
+	item := &simpledb.Item{
+		Name: aws.String("instances/broken"),
+		Attributes: []*simpledb.Attribute{
+			{Name: aws.String("Monitoring"), Value: aws.String("not-a-bool")},
+		},
+	}
+
+	cfgs := make(InstanceClasses)
+	err := cfgs.Marshal([]*simpledb.Item{item})
+	if err == nil {
+		t.Fatal("expected an error for an attribute that can not be decoded")
+	}
+	if !strings.Contains(err.Error(), "[broken]") || !strings.Contains(err.Error(), "[Monitoring]") {
+		t.Errorf("error does not name the class and attribute: %s", err)
+	}
+}
+
+func TestDecodeChildItemsError(t *testing.T) {
+	item := &simpledb.Item{Name: aws.String("securitygroups/test")}
+	getItems := func(classType string) ([]*simpledb.Item, error) {
+		return nil, fmt.Errorf("throttled")
+	}
+
+	err := decodeItem(item, new(SecurityGroupClass), getItems)
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("expected the child item lookup error, got: %v", err)
+	}
+}
+
+// encodeItems turns built attributes into the items SimpleDB would return, along with a lookup of the child items by type
+func encodeItems(itemsMap map[string][]*simpledb.ReplaceableAttribute, itemName string) (*simpledb.Item, func(string) ([]*simpledb.Item, error)) {
+	var item *simpledb.Item
+	children := make(map[string][]*simpledb.Item)
+
+	for name, attributes := range itemsMap {
+		i := &simpledb.Item{Name: aws.String(name)}
+		for _, attribute := range attributes {
+			i.Attributes = append(i.Attributes, &simpledb.Attribute{Name: attribute.Name, Value: attribute.Value})
+		}
+
+		if name == itemName {
+			item = i
+			continue
+		}
+		prefix := name[:strings.LastIndex(name, "/")]
+		children[prefix] = append(children[prefix], i)
+	}
+
+	return item, func(classType string) ([]*simpledb.Item, error) {
+		return children[classType], nil
+	}
+}
+
+// fillValue sets every stored field of a value to something other than its zero value
+func fillValue(val reflect.Value, seed int) {
+	if val.Type() == timeType {
+		val.Set(reflect.ValueOf(time.Date(2017, 3, seed%28+1, 12, 30, 15, 500, time.UTC)))
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath != "" || skipField(typ.Field(i)) {
+				continue
+			}
+			fillValue(val.Field(i), seed*10+i)
+		}
+
+	case reflect.String:
+		val.SetString(fmt.Sprintf("value-%d", seed))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val.SetInt(int64(seed % 100))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val.SetUint(uint64(seed % 100))
+
+	case reflect.Float32, reflect.Float64:
+		val.SetFloat(float64(seed) + 0.25)
+
+	case reflect.Bool:
+		val.SetBool(true)
+
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			val.SetBytes([]byte(fmt.Sprintf("bytes-%d", seed)))
+			return
+		}
+		val.Set(reflect.MakeSlice(val.Type(), 2, 2))
+		for i := 0; i < 2; i++ {
+			fillValue(val.Index(i), seed*10+i)
+		}
+
+	case reflect.Map:
+		val.Set(reflect.MakeMap(val.Type()))
+		for i := 0; i < 2; i++ {
+			elem := reflect.New(val.Type().Elem()).Elem()
+			fillValue(elem, seed*10+i)
+			val.SetMapIndex(reflect.ValueOf(fmt.Sprintf("key-%d", i)).Convert(val.Type().Key()), elem)
+		}
+	}
+}
+
+// checkItemIDs checks that decoded child items received the id of their item, then clears it for comparison
+func checkItemIDs(t *testing.T, name string, val reflect.Value) {
+	for _, childType := range childItemTypes(val.Type()) {
+		children := val.FieldByName(childType.field)
+		for i := 0; i < children.Len(); i++ {
+			child := children.Index(i)
+			for f := 0; f < child.NumField(); f++ {
+				if child.Type().Field(f).Tag.Get("awsm") != "id" {
+					continue
+				}
+				if child.Field(f).String() == "" {
+					t.Errorf("%s: %s child item %d has no id", name, childType.name, i)
+				}
+				child.Field(f).SetString("")
+			}
+		}
+	}
+}
+
+// sortChildItems sorts the child items of a class, since SimpleDB does not keep their order
+func sortChildItems(val reflect.Value) {
+	for _, childType := range childItemTypes(val.Type()) {
+		children := val.FieldByName(childType.field)
+		sort.Slice(children.Interface(), func(i, j int) bool {
+			return fmt.Sprintf("%+v", children.Index(i).Interface()) < fmt.Sprintf("%+v", children.Index(j).Interface())
+		})
+	}
+}
@@ -3,22 +3,37 @@ package config
 import (
 	"errors"
 	"fmt"
-	"reflect"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/logger"
+	"github.com/murdinc/awsm/settings"
 )
 
+// storeSession returns a session for the class store of the current environment
+func storeSession() *session.Session {
+	env := settings.Current()
+	return sessions.NewWithOptions(session.Options{
+		Config:  aws.Config{Region: aws.String(env.ClassStoreRegion)},
+		Profile: env.ClassStoreProfile,
+	})
+}
+
+// storeDomain returns the SimpleDB domain of the class store of the current environment
+func storeDomain() string {
+	return settings.Current().ClassStoreDomain
+}
+
 // CheckDB checks for an awsm database
 func CheckDB() bool {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	params := &simpledb.DomainMetadataInput{
-		DomainName: aws.String("awsm"), // Required
+		DomainName: aws.String(storeDomain()), // Required
 	}
 	_, err := svc.DomainMetadata(params)
 
@@ -30,14 +45,24 @@ func CheckDB() bool {
 	return true
 }
 
+// NotFoundError is returned when a class is not in the database
+type NotFoundError struct {
+	ClassType string
+	ClassName string
+}
+
+func (e NotFoundError) Error() string {
+	return "Unable to find the [" + e.ClassName + "] class in the database!"
+}
+
 // GetItemByName gets a SimpleDB item by its type and name
 func GetItemByName(classType, className string) (*simpledb.Item, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	params := &simpledb.GetAttributesInput{
-		DomainName:     aws.String("awsm"),
+		DomainName:     aws.String(storeDomain()),
 		ItemName:       aws.String(classType + "/" + className),
 		ConsistentRead: aws.Bool(true),
 	}
@@ -48,7 +73,7 @@ func GetItemByName(classType, className string) (*simpledb.Item, error) {
 	}
 
 	if len(resp.Attributes) < 1 {
-		return &simpledb.Item{}, errors.New("Unable to find the [" + className + "] class in the database!")
+		return &simpledb.Item{}, NotFoundError{ClassType: classType, ClassName: className}
 	}
 
 	item := &simpledb.Item{
@@ -62,11 +87,26 @@ func GetItemByName(classType, className string) (*simpledb.Item, error) {
 // GetItemsByType returns all SimpleDB items by class type
 func GetItemsByType(classType string) ([]*simpledb.Item, error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	items, err := selectItemsByType(classType)
+	if err != nil {
+		return []*simpledb.Item{}, err
+	}
+
+	if len(items) < 1 {
+		return []*simpledb.Item{}, errors.New("Unable to find the [" + classType + "] class in the database!")
+	}
+
+	return items, nil
+}
+
+// selectItemsByType returns the items of a type from SimpleDB, which may be none
+func selectItemsByType(classType string) ([]*simpledb.Item, error) {
+
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	params := &simpledb.SelectInput{
-		SelectExpression: aws.String(fmt.Sprintf("select * from awsm where classType = '%s'", classType)),
+		SelectExpression: aws.String(fmt.Sprintf("select * from `%s` where classType = '%s'", storeDomain(), classType)),
 		ConsistentRead:   aws.Bool(true),
 		//NextToken:        aws.String("String"),
 	}
@@ -77,17 +117,13 @@ func GetItemsByType(classType string) ([]*simpledb.Item, error) {
 		return []*simpledb.Item{}, err
 	}
 
-	if len(resp.Items) < 1 {
-		return []*simpledb.Item{}, errors.New("Unable to find the [" + classType + "] class in the database!")
-	}
-
 	return resp.Items, nil
 }
 
 // DeleteItemsByType batch deletes classes from SimpleDB
 func DeleteItemsByType(classType string) error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	existingItems, err := GetItemsByType(classType)
@@ -96,7 +132,7 @@ func DeleteItemsByType(classType string) error {
 	}
 
 	params := &simpledb.BatchDeleteAttributesInput{
-		DomainName: aws.String("awsm"),
+		DomainName: aws.String(storeDomain()),
 		//Items:      deleteList,
 	}
 
@@ -105,28 +141,25 @@ func DeleteItemsByType(classType string) error {
 			Name: item.Name,
 		})
 
-		//itemName := aws.StringValue(item.Name)
-		//terminal.Delta("Deleting [" + classType + "/" + itemName + "] Configuration...")
 	}
 
+	logger.Debug("Deleting items", "classType", classType, "items", len(params.Items))
 	_, err = svc.BatchDeleteAttributes(params)
 	if err != nil {
 		return err
 	}
 
-	//terminal.Information("Done!")
-
 	return nil
 }
 
 // CreateAwsmDatabase creates an awsm SimpleDB Domain
 func CreateAwsmDatabase() error {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	params := &simpledb.CreateDomainInput{
-		DomainName: aws.String("awsm"),
+		DomainName: aws.String(storeDomain()),
 	}
 	_, err := svc.CreateDomain(params)
 
@@ -152,90 +185,3 @@ func CreateAwsmDatabase() error {
 
 	return nil
 }
-
-// BuildAttributes builds SimpleDB item attributes from class structs
-func BuildAttributes(class interface{}, classType string) []*simpledb.ReplaceableAttribute {
-
-	typ := reflect.TypeOf(class)
-	val := reflect.ValueOf(class)
-
-	var attributes []*simpledb.ReplaceableAttribute
-
-	for i := 0; i < typ.NumField(); i++ {
-		name := typ.Field(i).Name
-
-		// Ignore if tagged ignore
-		if typ.Field(i).Tag.Get("awsm") == "ignore" {
-			continue
-		}
-
-		switch val.Field(i).Interface().(type) {
-		case int:
-			attributes = append(attributes, &simpledb.ReplaceableAttribute{
-				Name:    aws.String(name),
-				Value:   aws.String(fmt.Sprint(val.Field(i).Int())),
-				Replace: aws.Bool(true),
-			})
-
-		case float64:
-			attributes = append(attributes, &simpledb.ReplaceableAttribute{
-				Name:    aws.String(name),
-				Value:   aws.String(fmt.Sprint(val.Field(i).Float())),
-				Replace: aws.Bool(true),
-			})
-
-		case string:
-			attributes = append(attributes, &simpledb.ReplaceableAttribute{
-				Name:    aws.String(name),
-				Value:   aws.String(val.Field(i).String()),
-				Replace: aws.Bool(true),
-			})
-
-		case []string, []byte:
-			for s := 0; s < val.Field(i).Len(); s++ {
-				attributes = append(attributes, &simpledb.ReplaceableAttribute{
-					Name:    aws.String(name),
-					Value:   aws.String(val.Field(i).Index(s).String()),
-					Replace: aws.Bool(true),
-				})
-			}
-
-		case bool:
-			attributes = append(attributes, &simpledb.ReplaceableAttribute{
-				Name:    aws.String(name),
-				Value:   aws.String(fmt.Sprint(val.Field(i).Bool())),
-				Replace: aws.Bool(true),
-			})
-
-		case time.Time:
-			t := val.Field(i).Interface().(time.Time).UTC().String()
-			attributes = append(attributes, &simpledb.ReplaceableAttribute{
-				Name:    aws.String(name),
-				Value:   aws.String(t),
-				Replace: aws.Bool(true),
-			})
-
-		case []SecurityGroupGrant, []LoadBalancerListener:
-			// Handled in config/classes.go, for now
-
-		case LoadBalancerHealthCheck:
-			attributes = append(attributes, BuildAttributes(val.Field(i).Interface().(LoadBalancerHealthCheck), classType)...)
-
-		case LoadBalancerAttributes:
-			attributes = append(attributes, BuildAttributes(val.Field(i).Interface().(LoadBalancerAttributes), classType)...)
-
-		default:
-			println("BuildAttributes does not have a switch for type:")
-			println(val.Field(i).Type().String())
-
-		}
-	}
-
-	attributes = append(attributes, &simpledb.ReplaceableAttribute{
-		Name:    aws.String("classType"),
-		Value:   aws.String(classType),
-		Replace: aws.Bool(true),
-	})
-
-	return attributes
-}
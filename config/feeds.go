@@ -1,12 +1,12 @@
 package config
 
 import (
+	"errors"
 	"sort"
 	"time"
 
 	"github.com/SlyMarbo/rss"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/simpledb"
 )
 
@@ -38,7 +38,7 @@ func (f FeedItems) Swap(i, j int) {
 // SaveScalingPolicyClass reads and unmarshals a byte slice and inserts it into the db
 func SaveFeed(feedName string, latest FeedItems, max int) (feed FeedItems, err error) {
 
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	existing, _ := LoadAllFeedItems(feedName)
@@ -85,7 +85,7 @@ Loop:
 	}
 
 	params := &simpledb.BatchPutAttributesInput{
-		DomainName: aws.String("awsm"),
+		DomainName: aws.String(storeDomain()),
 		Items:      items,
 	}
 
@@ -121,39 +121,22 @@ func LoadAllFeedItems(feedName string) (FeedItems, error) {
 		return fi, err
 	}
 
-	fi.Marshal(items)
+	err = fi.Marshal(items)
 
 	//println(fi[0].Date.String())
-	return fi, nil
+	return fi, err
 }
 
 // Marshal puts items from SimpleDB into a Scaling Policy Class
-func (f FeedItems) Marshal(items []*simpledb.Item) {
+func (f FeedItems) Marshal(items []*simpledb.Item) error {
 	for i, item := range items {
 		cfg := new(FeedItem)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "ID":
-				cfg.ID = val
-
-			case "Link":
-				cfg.Link = val
-
-			case "Date":
-				cfg.Date, _ = time.Parse("2006-01-02 15:04:05 +0000 UTC", val)
-
-			case "Title":
-				cfg.Title = val
-
-			case "Summary":
-				cfg.Summary = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Feed item [" + aws.StringValue(item.Name) + "]: " + err.Error())
 		}
 		f[i] = *cfg
 	}
+
+	return nil
 }
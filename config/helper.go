@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/murdinc/awsm/logger"
 )
 
 // ExtractAwsmClass extracts the tagged keys and values from an awsm class config struct for displaying on the frontend
@@ -42,6 +45,17 @@ func ExtractAwsmClass(in interface{}) (keys, values []string) {
 					}
 				}
 
+			case "map[string]string":
+				tags := inValue.Field(k).Interface().(map[string]string)
+				keys := make([]string, 0, len(tags))
+				for key := range tags {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					sVal += fmt.Sprintf("%s=%s\n\n", key, tags[key])
+				}
+
 			case "[]config.SecurityGroupGrant":
 				grants := inValue.Field(k).Interface().([]SecurityGroupGrant)
 				for _, grant := range grants {
@@ -78,7 +92,7 @@ func ExtractAwsmClass(in interface{}) (keys, values []string) {
 				}
 
 			default:
-				fmt.Printf("ExtractAwsmClass does not have a switch for type: %#v\n", inValue.Field(k).Type().String())
+				logger.Warn("ExtractAwsmClass does not have a switch for this type", "type", inValue.Field(k).Type().String())
 
 			}
 
@@ -144,7 +158,7 @@ func ExtractAwsmWidget(in interface{}) (keys, values []string) {
 				}
 
 			default:
-				fmt.Printf("ExtractAwsmClass does not have a switch for type: %#v\n", inValue.Field(k).Type().String())
+				logger.Warn("ExtractAwsmClass does not have a switch for this type", "type", inValue.Field(k).Type().String())
 
 			}
 
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -19,6 +19,11 @@ type ImageClass struct {
 	Propagate        bool     `json:"propagate" awsmClass:"Propagate"`
 	PropagateRegions []string `json:"propagateRegions" awsmClass:"Propagate Regions"`
 	Version          int      `json:"version" awsmClass:"Version"`
+
+	// Custom Tags, applied to the Image and its copies
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultImageClasses returns the default Image classes
@@ -44,7 +49,7 @@ func SaveImageClass(className string, data []byte) (class ImageClass, err error)
 		return
 	}
 
-	err = Insert("images", ImageClasses{className: class})
+	class.Revision, err = Update("images", className, class)
 	return
 }
 
@@ -55,8 +60,8 @@ func LoadImageClass(name string) (ImageClass, error) {
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllImageClasses returns all Image classes
@@ -67,44 +72,23 @@ func LoadAllImageClasses() (ImageClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into Image Classes
-func (c ImageClasses) Marshal(items []*simpledb.Item) {
+func (c ImageClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "images/", "", -1)
 		cfg := new(ImageClass)
-
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Version":
-				cfg.Version, _ = strconv.Atoi(val)
-
-			case "Propagate":
-				cfg.Propagate, _ = strconv.ParseBool(val)
-
-			case "PropagateRegions":
-				cfg.PropagateRegions = append(cfg.PropagateRegions, val)
-
-			case "Rotate":
-				cfg.Rotate, _ = strconv.ParseBool(val)
-
-			case "Retain":
-				cfg.Retain, _ = strconv.Atoi(val)
-
-			case "Instance":
-				cfg.Instance = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Image class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
 
 // SetInstance updates the source instance of an Image
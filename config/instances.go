@@ -2,10 +2,12 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/murdinc/awsm/schedule"
 )
 
 // InstanceClasses is a map if Instance classes
@@ -26,6 +28,38 @@ type InstanceClass struct {
 	ShutdownBehavior   string   `json:"shutdownBehavior" awsmClass:"Shutdown Behaviour"`
 	IAMInstanceProfile string   `json:"iamInstanceProfile" awsmClass:"IAM Instance Profile"`
 	UserData           string   `json:"userData"`
+
+	// Spot
+	Spot                     bool   `json:"spot" awsmClass:"Spot"`
+	SpotMaxPrice             string `json:"spotMaxPrice" awsmClass:"Spot Max Price"`                         // per hour, the On-Demand price if empty
+	SpotInterruptionBehavior string `json:"spotInterruptionBehavior" awsmClass:"Spot Interruption Behavior"` // terminate (default), stop or hibernate
+	SpotPersistent           bool   `json:"spotPersistent" awsmClass:"Spot Persistent"`                      // a persistent Spot request instead of a one-time one, needs the stop or hibernate interruption behavior
+
+	// Placement / Capacity
+	PlacementGroup      string `json:"placementGroup" awsmClass:"Placement Group"`
+	Tenancy             string `json:"tenancy" awsmClass:"Tenancy"`                          // default, dedicated or host
+	Affinity            string `json:"affinity" awsmClass:"Host Affinity"`                   // default or host, for the host tenancy
+	CapacityReservation string `json:"capacityReservation" awsmClass:"Capacity Reservation"` // open, none or the id of a Capacity Reservation to target
+
+	// Instance Metadata Service, left at the AWS defaults if empty
+	MetadataHTTPTokens string `json:"metadataHttpTokens" awsmClass:"Metadata HTTP Tokens"` // optional (IMDSv1 and IMDSv2) or required (IMDSv2 only)
+	MetadataHopLimit   int    `json:"metadataHopLimit" awsmClass:"Metadata Hop Limit"`     // 1 to 64, the hops a metadata response can travel
+	MetadataEndpoint   string `json:"metadataEndpoint" awsmClass:"Metadata Endpoint"`      // enabled or disabled
+
+	// Post-Launch, with launchInstance --wait
+	PostLaunchCommands []string `json:"postLaunchCommands" awsmClass:"Post-Launch Commands"` // SSM commands, run in order
+	DNSName            string   `json:"dnsName" awsmClass:"DNS Name"`                        // eg: ${var.class}${var.sequence}.example.com
+	DNSPrivate         bool     `json:"dnsPrivate" awsmClass:"DNS Private IP"`               // register the private IP address, even if there is a public one
+
+	// Schedule, with awsm scheduler
+	ScheduleStart    string `json:"scheduleStart" awsmClass:"Schedule Start"`       // cron expression, eg: 0 8 * * mon-fri
+	ScheduleStop     string `json:"scheduleStop" awsmClass:"Schedule Stop"`         // cron expression, eg: 0 19 * * mon-fri
+	ScheduleTimezone string `json:"scheduleTimezone" awsmClass:"Schedule Timezone"` // eg: America/Los_Angeles, UTC if empty
+
+	// Custom Tags, applied to the Instance and its Volumes, and to the Instances of AutoScale Groups through Launch Configurations
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultInstanceClasses returns the default Instance classes
@@ -54,10 +88,53 @@ func SaveInstanceClass(className string, data []byte) (class InstanceClass, err
 		return
 	}
 
-	err = Insert("instances", InstanceClasses{className: class})
+	err = validateSchedule(class.ScheduleStart, class.ScheduleStop, class.ScheduleTimezone)
+	if err != nil {
+		return
+	}
+
+	class.Revision, err = Update("instances", className, class)
 	return
 }
 
+// validateSchedule checks the start and stop schedules and time zone of a class before it is saved
+func validateSchedule(start, stop, timezone string) error {
+	if start == "" && stop == "" {
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return errors.New("Invalid time zone [" + timezone + "]!")
+			}
+		}
+		return nil
+	}
+
+	if start != "" {
+		if _, err := schedule.Parse(start, timezone); err != nil {
+			return errors.New("The start schedule is invalid: " + err.Error())
+		}
+	}
+	if stop != "" {
+		if _, err := schedule.Parse(stop, timezone); err != nil {
+			return errors.New("The stop schedule is invalid: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SetInstanceType updates the instance type of an Instance class
+func (c *InstanceClass) SetInstanceType(name string, instanceType string) error {
+	c.InstanceType = instanceType
+
+	revision, err := Update("instances", name, *c)
+	if err != nil {
+		return err
+	}
+
+	c.Revision = revision
+	return nil
+}
+
 // LoadInstanceClass returns an Instance class by its name
 func LoadInstanceClass(name string) (InstanceClass, error) {
 	cfgs := make(InstanceClasses)
@@ -65,8 +142,8 @@ func LoadInstanceClass(name string) (InstanceClass, error) {
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllInstanceClasses returns all Instance classes
@@ -77,62 +154,21 @@ func LoadAllInstanceClasses() (InstanceClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into an Instance class
-func (c InstanceClasses) Marshal(items []*simpledb.Item) {
+func (c InstanceClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "instances/", "", -1)
 		cfg := new(InstanceClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "InstanceType":
-				cfg.InstanceType = val
-
-			case "SecurityGroups":
-				cfg.SecurityGroups = append(cfg.SecurityGroups, val)
-
-			case "EBSVolumes":
-				cfg.EBSVolumes = append(cfg.EBSVolumes, val)
-
-			case "Subnet":
-				cfg.Subnet = val
-
-			case "Vpc":
-				cfg.Vpc = val
-
-			case "PublicIPAddress":
-				cfg.PublicIPAddress, _ = strconv.ParseBool(val)
-
-			case "AMI":
-				cfg.AMI = val
-
-			case "KeyName":
-				cfg.KeyName = val
-
-			case "EbsOptimized":
-				cfg.EbsOptimized, _ = strconv.ParseBool(val)
-
-			case "Monitoring":
-				cfg.Monitoring, _ = strconv.ParseBool(val)
-
-			case "ShutdownBehavior":
-				cfg.ShutdownBehavior = val
-
-			case "UserData":
-				cfg.UserData = val
-
-			case "IAMInstanceProfile":
-				cfg.IAMInstanceProfile = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Instance class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,6 +2,9 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -14,11 +17,17 @@ type KeyPairClasses map[string]KeyPairClass
 type KeyPairClass struct {
 	Description string `json:"description" awsmClass:"Description"`
 	PublicKey   string `json:"publicKey" awsmClass:"Public Key"`
-	PrivateKey1 string `json:"-"`
+	PrivateKey1 string `json:"-"` // split up private keys stored before PrivateKeyParts, only read
 	PrivateKey2 string `json:"-"`
 	PrivateKey3 string `json:"-"`
 	PrivateKey4 string `json:"-"`
-	PrivateKey  string `json:"privateKey" awsm:"ignore"`
+	PrivateKey  string `json:"privateKey" awsm:"ignore" awsmSecret:"true"`
+
+	// The sealed private key, split into as many parts as it takes to fit SimpleDB attribute values, each prefixed with
+	// its index since multi-valued attributes are unordered
+	PrivateKeyParts []string `json:"-"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultKeyPairClasses returns the default KeyPair classes
@@ -55,16 +64,33 @@ func SaveKeyPairClass(className string, data []byte) (class KeyPairClass, err er
 		class.PrivateKey3 = privateKey[privateKeyLen*2 : privateKeyLen*3]
 		class.PrivateKey4 = privateKey[privateKeyLen*3:]
 	} else {*/
-	privateKey := class.PrivateKey
-	privateKeyLen := len(privateKey) / 4
 
-	class.PrivateKey1 = privateKey[:privateKeyLen]
-	class.PrivateKey2 = privateKey[privateKeyLen : privateKeyLen*2]
-	class.PrivateKey3 = privateKey[privateKeyLen*2 : privateKeyLen*3]
-	class.PrivateKey4 = privateKey[privateKeyLen*3:]
+	// The dashboard only ever receives redacted private keys, keep the stored one
+	if class.PrivateKey == RedactedSecret {
+		var existing KeyPairClass
+		existing, err = LoadKeyPairClass(className)
+		if err != nil {
+			return
+		}
+		class.PrivateKey = existing.PrivateKey
+	}
+
+	// Encrypt the private key before it is split up and stored
+	sealed, err := SealSecrets(class)
+	if err != nil {
+		return
+	}
+	class = sealed.(KeyPairClass)
+
+	class.PrivateKeyParts, err = splitPrivateKey(class.PrivateKey)
+	if err != nil {
+		err = errors.New("Private key for [" + className + "] is too large to be stored!")
+		return
+	}
+	class.PrivateKey1, class.PrivateKey2, class.PrivateKey3, class.PrivateKey4 = "", "", "", ""
 	/*}*/
 
-	err = Insert("keypairs", KeyPairClasses{className: class})
+	class.Revision, err = Update("keypairs", className, class)
 
 	if err != nil {
 		println(err)
@@ -79,8 +105,8 @@ func LoadKeyPairClass(name string) (KeyPairClass, error) {
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllKeyPairClasses returns all Image classes
@@ -91,42 +117,66 @@ func LoadAllKeyPairClasses() (KeyPairClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into Image Classes
-func (c KeyPairClasses) Marshal(items []*simpledb.Item) {
+func (c KeyPairClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "keypairs/", "", -1)
 		cfg := new(KeyPairClass)
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode KeyPair class [" + name + "]: " + err.Error())
+		}
 
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Description":
-				cfg.Description = val
+		// Still encrypted, see OpenSecrets
+		if len(cfg.PrivateKeyParts) > 0 {
+			cfg.PrivateKey = joinPrivateKey(cfg.PrivateKeyParts)
+		} else {
+			cfg.PrivateKey = cfg.PrivateKey1 + cfg.PrivateKey2 + cfg.PrivateKey3 + cfg.PrivateKey4
+		}
+		c[name] = *cfg
+	}
 
-			case "PublicKey":
-				cfg.PublicKey = val
+	return nil
+}
 
-			case "PrivateKey1":
-				cfg.PrivateKey1 = val
+// privateKeyPartSize is the size of each part of a stored private key, leaving room for its index under the SimpleDB
+// limit of 1024 bytes per attribute value
+const privateKeyPartSize = 1000
+
+// splitPrivateKey splits a private key into parts that each fit in a SimpleDB attribute value, prefixed with their index
+func splitPrivateKey(privateKey string) ([]string, error) {
+	var parts []string
+	for i := 0; i < len(privateKey); i += privateKeyPartSize {
+		end := i + privateKeyPartSize
+		if end > len(privateKey) {
+			end = len(privateKey)
+		}
+		parts = append(parts, fmt.Sprintf("%03d:%s", len(parts), privateKey[i:end]))
+	}
 
-			case "PrivateKey2":
-				cfg.PrivateKey2 = val
+	// SimpleDB items are limited to 256 attributes
+	if len(parts) > 200 {
+		return nil, errors.New("too many parts")
+	}
 
-			case "PrivateKey3":
-				cfg.PrivateKey3 = val
+	return parts, nil
+}
 
-			case "PrivateKey4":
-				cfg.PrivateKey4 = val
+// joinPrivateKey puts the parts of a stored private key back together, in the order of their index
+func joinPrivateKey(parts []string) string {
+	sorted := append([]string{}, parts...)
+	sort.Strings(sorted)
 
-			}
+	var privateKey string
+	for _, part := range sorted {
+		if i := strings.Index(part, ":"); i >= 0 {
+			privateKey += part[i+1:]
 		}
-		c[name] = *cfg
 	}
+
+	return privateKey
 }
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -18,6 +18,11 @@ type LaunchConfigurationClass struct {
 	Retain        int      `json:"retain" awsmClass:"Retain"`
 	Rotate        bool     `json:"rotate" awsmClass:"Rotate"`
 	Regions       []string `json:"regions" awsmClass:"Regions"`
+
+	// Custom Tags, propagated to the Instances of the AutoScale Groups using the Launch Configuration
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultLaunchConfigurationClasses returns the default Launch Configuration Classes
@@ -42,7 +47,7 @@ func SaveLaunchConfigurationClass(className string, data []byte) (class LaunchCo
 		return
 	}
 
-	err = Insert("launchconfigurations", LaunchConfigurationClasses{className: class})
+	class.Revision, err = Update("launchconfigurations", className, class)
 	return
 }
 
@@ -53,8 +58,8 @@ func LoadLaunchConfigurationClass(name string) (LaunchConfigurationClass, error)
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllLaunchConfigurationClasses returns all Launch Configuration Classes
@@ -65,40 +70,23 @@ func LoadAllLaunchConfigurationClasses() (LaunchConfigurationClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a class config
-func (c LaunchConfigurationClasses) Marshal(items []*simpledb.Item) {
+func (c LaunchConfigurationClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "launchconfigurations/", "", -1)
 		cfg := new(LaunchConfigurationClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Version":
-				cfg.Version, _ = strconv.Atoi(val)
-
-			case "InstanceClass":
-				cfg.InstanceClass = val
-
-			case "Regions":
-				cfg.Regions = append(cfg.Regions, val)
-
-			case "Retain":
-				cfg.Retain, _ = strconv.Atoi(val)
-
-			case "Rotate":
-				cfg.Rotate, _ = strconv.ParseBool(val)
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Launch Configuration class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
 
 // SetVersion updates the version of a Launch Configuration
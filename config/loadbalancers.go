@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -20,18 +20,23 @@ type LoadBalancerClass struct {
 	AvailabilityZones []string `json:"availabilityZones" awsmClass:"Availability Zone"`
 
 	// Listeners
-	LoadBalancerListeners []LoadBalancerListener `json:"loadBalancerListeners" hash:"ignore" awsmClass:"Listeners"`
+	LoadBalancerListeners []LoadBalancerListener `json:"loadBalancerListeners" hash:"ignore" awsmClass:"Listeners" awsmItems:"listeners"`
 
 	// Health Checks
 	LoadBalancerHealthCheck LoadBalancerHealthCheck `json:"loadBalancerHealthCheck" hash:"ignore" awsmClass:"Health Check"`
 
 	// Attributes
 	LoadBalancerAttributes LoadBalancerAttributes `json:"loadBalancerAttributes" hash:"ignore" awsmClass:"Attributes"`
+
+	// Custom Tags, applied to the Load Balancer and reconciled by updateLoadBalancers
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // LoadBalancerListener is a single Load Balancer Listener
 type LoadBalancerListener struct {
-	ID               string `json:"id" hash:"ignore" awsm:"ignore"` // Needed?
+	ID               string `json:"id" hash:"ignore" awsm:"id"` // Needed?
 	InstancePort     int    `json:"instancePort"`
 	LoadBalancerPort int    `json:"loadBalancerPort"`
 	Protocol         string `json:"protocol"`
@@ -100,7 +105,7 @@ func SaveLoadBalancerClass(className string, data []byte) (class LoadBalancerCla
 		return
 	}
 
-	err = Insert("loadbalancers", LoadBalancerClasses{className: class})
+	class.Revision, err = Update("loadbalancers", className, class)
 	return
 }
 
@@ -112,8 +117,8 @@ func LoadLoadBalancerClass(name string) (LoadBalancerClass, error) {
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllLoadBalancerClasses loads all Load Balancer Classes
@@ -124,113 +129,21 @@ func LoadAllLoadBalancerClasses() (LoadBalancerClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Load Balancer Class
-func (c LoadBalancerClasses) Marshal(items []*simpledb.Item) {
+func (c LoadBalancerClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "loadbalancers/", "", -1)
 		cfg := new(LoadBalancerClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Scheme":
-				cfg.Scheme = val
-
-			case "SecurityGroups":
-				cfg.SecurityGroups = append(cfg.SecurityGroups, val)
-
-			case "Vpc":
-				cfg.Vpc = val
-
-			case "Subnets":
-				cfg.Subnets = append(cfg.Subnets, val)
-
-			case "AvailabilityZones":
-				cfg.AvailabilityZones = append(cfg.AvailabilityZones, val)
-
-			// Get the health check
-			case "HealthCheckTarget":
-				cfg.LoadBalancerHealthCheck.HealthCheckTarget = val
-
-			case "HealthCheckTimeout":
-				cfg.LoadBalancerHealthCheck.HealthCheckTimeout, _ = strconv.Atoi(val)
-
-			case "HealthCheckInterval":
-				cfg.LoadBalancerHealthCheck.HealthCheckInterval, _ = strconv.Atoi(val)
-
-			case "HealthCheckUnhealthyThreshold":
-				cfg.LoadBalancerHealthCheck.HealthCheckUnhealthyThreshold, _ = strconv.Atoi(val)
-
-			case "HealthCheckHealthyThreshold":
-				cfg.LoadBalancerHealthCheck.HealthCheckHealthyThreshold, _ = strconv.Atoi(val)
-
-			case "ConnectionDrainingEnabled":
-				cfg.LoadBalancerAttributes.ConnectionDrainingEnabled, _ = strconv.ParseBool(val)
-
-			// Get the attributes
-			case "ConnectionDrainingTimeout":
-				cfg.LoadBalancerAttributes.ConnectionDrainingTimeout, _ = strconv.Atoi(val)
-
-			case "IdleTimeout":
-				cfg.LoadBalancerAttributes.IdleTimeout, _ = strconv.Atoi(val)
-
-			case "CrossZoneLoadBalancingEnabled":
-				cfg.LoadBalancerAttributes.CrossZoneLoadBalancingEnabled, _ = strconv.ParseBool(val)
-
-			case "AccessLogEnabled":
-				cfg.LoadBalancerAttributes.AccessLogEnabled, _ = strconv.ParseBool(val)
-
-			case "AccessLogEmitInterval":
-				cfg.LoadBalancerAttributes.AccessLogEmitInterval, _ = strconv.Atoi(val)
-
-			case "AccessLogS3BucketName":
-				cfg.LoadBalancerAttributes.AccessLogS3BucketName = val
-
-			case "AccessLogS3BucketPrefix":
-				cfg.LoadBalancerAttributes.AccessLogS3BucketPrefix = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Load Balancer class [" + name + "]: " + err.Error())
 		}
-
-		// Get the listeners
-		listeners, _ := GetItemsByType("loadbalancers/" + name + "/listeners")
-		cfg.LoadBalancerListeners = make([]LoadBalancerListener, len(listeners))
-		for i, listener := range listeners {
-
-			cfg.LoadBalancerListeners[i].ID = strings.Replace(*listener.Name, "loadbalancers/"+name+"/listeners/", "", -1) // Needed?
-
-			for _, attribute := range listener.Attributes {
-
-				val := *attribute.Value
-
-				switch *attribute.Name {
-
-				case "InstancePort":
-					cfg.LoadBalancerListeners[i].InstancePort, _ = strconv.Atoi(val)
-
-				case "LoadBalancerPort":
-					cfg.LoadBalancerListeners[i].LoadBalancerPort, _ = strconv.Atoi(val)
-
-				case "Protocol":
-					cfg.LoadBalancerListeners[i].Protocol = val
-
-				case "InstanceProtocol":
-					cfg.LoadBalancerListeners[i].InstanceProtocol = val
-
-				case "SSLCertificateID":
-					cfg.LoadBalancerListeners[i].SSLCertificateID = val
-
-				}
-			}
-
-		}
-
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -16,6 +16,8 @@ type ScalingPolicyClass struct {
 	ScalingAdjustment int    `json:"scalingAdjustment" awsmClass:"Scaling Adjustment"`
 	AdjustmentType    string `json:"adjustmentType" awsmClass:"Adjustment Type"`
 	Cooldown          int    `json:"cooldown" awsmClass:"Cooldown"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultScalingPolicyClasses returns the defauly Scaling Policy Classes
@@ -44,7 +46,7 @@ func SaveScalingPolicyClass(className string, data []byte) (class ScalingPolicyC
 		return
 	}
 
-	err = Insert("scalingpolicies", ScalingPolicyClasses{className: class})
+	class.Revision, err = Update("scalingpolicies", className, class)
 	return
 }
 
@@ -56,8 +58,8 @@ func LoadScalingPolicyClass(name string) (ScalingPolicyClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllScalingPolicyClasses loads all Scaling Policies Classes
@@ -68,32 +70,21 @@ func LoadAllScalingPolicyClasses() (ScalingPolicyClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Scaling Policy Class
-func (c ScalingPolicyClasses) Marshal(items []*simpledb.Item) {
+func (c ScalingPolicyClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "scalingpolicies/", "", -1)
 		cfg := new(ScalingPolicyClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "ScalingAdjustment":
-				cfg.ScalingAdjustment, _ = strconv.Atoi(val)
-
-			case "AdjustmentType":
-				cfg.AdjustmentType = val
-
-			case "Cooldown":
-				cfg.Cooldown, _ = strconv.Atoi(val)
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Scaling Policy class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -0,0 +1,411 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/murdinc/awsm/aws/sessions"
+	"github.com/murdinc/awsm/settings"
+)
+
+// Fields tagged `awsmSecret:"true"` are envelope-encrypted before being inserted into SimpleDB.
+// Each value is encrypted with its own AES-256-GCM data key, and that data key is in turn
+// encrypted by a SecretKeyProvider (a KMS key, or a local key file).
+
+// RedactedSecret replaces secret values in API and export output
+const RedactedSecret = "**********"
+
+const sealedSecretPrefix = "awsm:secret:v1:"
+
+// SecretKeyProvider generates and decrypts the data keys used to encrypt secret class fields. GenerateDataKey also
+// returns the id of the key that encrypted the data key, if the provider needs it to decrypt it again.
+type SecretKeyProvider interface {
+	Name() string
+	GenerateDataKey() (plainKey, encryptedKey []byte, keyID string, err error)
+	DecryptDataKey(encryptedKey []byte) ([]byte, error)
+}
+
+// GetSecretKeyProvider returns the configured SecretKeyProvider. The KMS key in AWSM_KMS_KEY_ID
+// is used if set, otherwise a local key file at AWSM_SECRET_KEY_FILE (default ~/.awsm/secret.key)
+func GetSecretKeyProvider() SecretKeyProvider {
+	if keyID := os.Getenv("AWSM_KMS_KEY_ID"); keyID != "" {
+		return &KMSKeyProvider{KeyID: keyID}
+	}
+	return &FileKeyProvider{Path: os.Getenv("AWSM_SECRET_KEY_FILE")}
+}
+
+// getSecretKeyProviderByName returns the provider that sealed a secret, with the id of the key it was sealed with
+func getSecretKeyProviderByName(name, keyID string) (SecretKeyProvider, error) {
+	switch name {
+	case "kms":
+		// Secrets sealed before the key ARN was stored with them are decrypted in the region of the configured key
+		if keyID == "" {
+			keyID = os.Getenv("AWSM_KMS_KEY_ID")
+		}
+		return &KMSKeyProvider{KeyID: keyID}, nil
+	case "file":
+		return &FileKeyProvider{Path: os.Getenv("AWSM_SECRET_KEY_FILE")}, nil
+	}
+	return nil, errors.New("Unknown secret key provider [" + name + "]!")
+}
+
+// KMSKeyProvider encrypts data keys with an AWS KMS key
+type KMSKeyProvider struct {
+	KeyID string
+}
+
+// Name returns the provider name stored alongside sealed secrets
+func (k *KMSKeyProvider) Name() string {
+	return "kms"
+}
+
+// region returns the region of the KMS key, parsed from its ARN if possible
+func (k *KMSKeyProvider) region() string {
+	parts := strings.Split(k.KeyID, ":")
+	if len(parts) > 3 && parts[0] == "arn" && parts[2] == "kms" {
+		return parts[3]
+	}
+	return settings.Current().DefaultRegion
+}
+
+// GenerateDataKey generates a new AES-256 data key with KMS, returning the ARN of the KMS key
+func (k *KMSKeyProvider) GenerateDataKey() ([]byte, []byte, string, error) {
+	sess := sessions.New(&aws.Config{Region: aws.String(k.region())})
+	svc := kms.New(sess)
+
+	resp, err := svc.GenerateDataKey(&kms.GenerateDataKeyInput{
+		KeyId:   aws.String(k.KeyID),
+		KeySpec: aws.String("AES_256"),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return nil, nil, "", errors.New(awsErr.Message())
+		}
+		return nil, nil, "", err
+	}
+
+	return resp.Plaintext, resp.CiphertextBlob, aws.StringValue(resp.KeyId), nil
+}
+
+// DecryptDataKey decrypts a data key with KMS
+func (k *KMSKeyProvider) DecryptDataKey(encryptedKey []byte) ([]byte, error) {
+	sess := sessions.New(&aws.Config{Region: aws.String(k.region())})
+	svc := kms.New(sess)
+
+	params := &kms.DecryptInput{
+		CiphertextBlob: encryptedKey,
+	}
+	if strings.HasPrefix(k.KeyID, "arn:") {
+		params.KeyId = aws.String(k.KeyID)
+	}
+
+	resp, err := svc.Decrypt(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			return nil, errors.New(awsErr.Message())
+		}
+		return nil, err
+	}
+
+	return resp.Plaintext, nil
+}
+
+// FileKeyProvider encrypts data keys with a master key read from a local file
+type FileKeyProvider struct {
+	Path string
+}
+
+// Name returns the provider name stored alongside sealed secrets
+func (f *FileKeyProvider) Name() string {
+	return "file"
+}
+
+// path returns the location of the master key file
+func (f *FileKeyProvider) path() string {
+	if f.Path != "" {
+		return f.Path
+	}
+	currentUser, _ := user.Current()
+	sep := string(os.PathSeparator)
+	return currentUser.HomeDir + sep + ".awsm" + sep + "secret.key"
+}
+
+// masterKey reads the master key, optionally creating a new one if it does not exist yet
+func (f *FileKeyProvider) masterKey(create bool) ([]byte, error) {
+	keyPath := f.path()
+
+	key, err := ioutil.ReadFile(keyPath)
+	if os.IsNotExist(err) && create {
+		key = make([]byte, 32)
+		if _, err = io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+
+		if err = os.MkdirAll(keyPath[:strings.LastIndex(keyPath, string(os.PathSeparator))+1], 0700); err != nil {
+			return nil, err
+		}
+
+		return key, ioutil.WriteFile(keyPath, key, 0600)
+	}
+	if err != nil {
+		return nil, errors.New("Unable to read the awsm secret key file [" + keyPath + "]: " + err.Error())
+	}
+
+	if len(key) != 32 {
+		return nil, errors.New("The awsm secret key file [" + keyPath + "] is not a 256 bit key!")
+	}
+
+	return key, nil
+}
+
+// GenerateDataKey generates a new data key and encrypts it with the master key
+func (f *FileKeyProvider) GenerateDataKey() ([]byte, []byte, string, error) {
+	master, err := f.masterKey(true)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, "", err
+	}
+
+	encryptedKey, err := gcmSeal(master, dataKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return dataKey, encryptedKey, "", nil
+}
+
+// DecryptDataKey decrypts a data key with the master key
+func (f *FileKeyProvider) DecryptDataKey(encryptedKey []byte) ([]byte, error) {
+	master, err := f.masterKey(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcmOpen(master, encryptedKey)
+}
+
+// gcmSeal encrypts plaintext with AES-GCM, prefixing the random nonce
+func gcmSeal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// gcmOpen decrypts ciphertext created by gcmSeal
+func gcmOpen(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("Encrypted secret is too short!")
+	}
+
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+// IsSealedSecret returns true if the provided value has been encrypted by SealSecret
+func IsSealedSecret(value string) bool {
+	return strings.HasPrefix(value, sealedSecretPrefix)
+}
+
+// SealSecret envelope-encrypts a single value with the configured SecretKeyProvider
+func SealSecret(value string) (string, error) {
+	if value == "" || IsSealedSecret(value) {
+		return value, nil
+	}
+
+	provider := GetSecretKeyProvider()
+
+	dataKey, encryptedKey, keyID, err := provider.GenerateDataKey()
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := gcmSeal(dataKey, []byte(value))
+	if err != nil {
+		return "", err
+	}
+
+	// <provider>[:<key id>]:<encrypted data key>:<ciphertext>, the key id (a KMS key ARN) may contain colons itself
+	sealer := provider.Name()
+	if keyID != "" {
+		sealer += ":" + keyID
+	}
+
+	return sealedSecretPrefix + sealer + ":" + base64.StdEncoding.EncodeToString(encryptedKey) + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// OpenSecret decrypts a value sealed by SealSecret. Values that were never sealed are returned as-is.
+func OpenSecret(value string) (string, error) {
+	if !IsSealedSecret(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, sealedSecretPrefix), ":")
+	if len(parts) < 3 {
+		return "", errors.New("Encrypted secret is malformed!")
+	}
+
+	keyID := strings.Join(parts[1:len(parts)-2], ":")
+	provider, err := getSecretKeyProviderByName(parts[0], keyID)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(parts[len(parts)-2])
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := provider.DecryptDataKey(encryptedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := gcmOpen(dataKey, ciphertext)
+	if err != nil {
+		return "", errors.New("Unable to decrypt secret: " + err.Error())
+	}
+
+	return string(plaintext), nil
+}
+
+// SealSecrets encrypts every secret field of a class, or of every class in a map of classes
+func SealSecrets(classes interface{}) (interface{}, error) {
+	return transformSecrets(classes, SealSecret)
+}
+
+// OpenSecrets decrypts every secret field of a class, or of every class in a map of classes
+func OpenSecrets(classes interface{}) (interface{}, error) {
+	return transformSecrets(classes, OpenSecret)
+}
+
+// RedactSecrets replaces every non-empty secret field of a class, or of every class in a map of classes
+func RedactSecrets(classes interface{}) interface{} {
+	redacted, _ := transformSecrets(classes, func(value string) (string, error) {
+		if value == "" {
+			return value, nil
+		}
+		return RedactedSecret, nil
+	})
+	return redacted
+}
+
+// transformSecrets returns a copy of the passed value with fn applied to all secret fields. Maps are updated in place.
+func transformSecrets(in interface{}, fn func(string) (string, error)) (interface{}, error) {
+	if in == nil {
+		return in, nil
+	}
+
+	val := reflect.New(reflect.TypeOf(in)).Elem()
+	val.Set(reflect.ValueOf(in))
+
+	err := walkSecrets(val, fn)
+
+	return val.Interface(), err
+}
+
+// walkSecrets recursively applies fn to all secret fields of an addressable value
+func walkSecrets(val reflect.Value, fn func(string) (string, error)) error {
+	switch val.Kind() {
+
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		if val.Kind() == reflect.Ptr {
+			return walkSecrets(val.Elem(), fn)
+		}
+
+		elem := reflect.New(val.Elem().Type()).Elem()
+		elem.Set(val.Elem())
+		if err := walkSecrets(elem, fn); err != nil {
+			return err
+		}
+		if val.CanSet() {
+			val.Set(elem)
+		}
+
+	case reflect.Map:
+		for _, key := range val.MapKeys() {
+			elem := reflect.New(val.Type().Elem()).Elem()
+			elem.Set(val.MapIndex(key))
+			if err := walkSecrets(elem, fn); err != nil {
+				return err
+			}
+			val.SetMapIndex(key, elem)
+		}
+
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			if err := walkSecrets(val.Index(i), fn); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := val.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+
+			if typ.Field(i).Tag.Get("awsmSecret") == "true" && field.Kind() == reflect.String {
+				s, err := fn(field.String())
+				if err != nil {
+					return errors.New("Error processing secret field [" + typ.Field(i).Name + "]: " + err.Error())
+				}
+				field.SetString(s)
+				continue
+			}
+
+			if err := walkSecrets(field, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
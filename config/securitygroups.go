@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -14,12 +14,17 @@ type SecurityGroupClasses map[string]SecurityGroupClass
 // SecurityGroupClass is a single Security Group Class
 type SecurityGroupClass struct {
 	Description         string               `json:"description" awsmClass:"Description"`
-	SecurityGroupGrants []SecurityGroupGrant `json:"securityGroupGrants"  awsmClass:"Grants"`
+	SecurityGroupGrants []SecurityGroupGrant `json:"securityGroupGrants" awsmClass:"Grants" awsmItems:"grants"`
+
+	// Custom Tags, applied to the Security Group and reconciled by updateSecurityGroups
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // SecurityGroupGrant is a Security Group Grant
 type SecurityGroupGrant struct {
-	ID                       string   `json:"id" hash:"ignore" awsm:"ignore"`
+	ID                       string   `json:"id" hash:"ignore" awsm:"id"`
 	Note                     string   `json:"note" hash:"ignore"`
 	Type                     string   `json:"type"` // ingress / egress
 	FromPort                 int      `json:"fromPort"`
@@ -65,7 +70,7 @@ func SaveSecurityGroupClass(className string, data []byte) (class SecurityGroupC
 		return
 	}
 
-	err = Insert("securitygroups", SecurityGroupClasses{className: class})
+	class.Revision, err = Update("securitygroups", className, class)
 	return
 }
 
@@ -77,7 +82,10 @@ func LoadSecurityGroupClass(name string, splitGrants bool) (SecurityGroupClass,
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	if err != nil {
+		return cfgs[name], err
+	}
 	cfg := cfgs[name]
 
 	if splitGrants {
@@ -116,67 +124,21 @@ func LoadAllSecurityGroupClasses() (SecurityGroupClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Security Group Class
-func (c SecurityGroupClasses) Marshal(items []*simpledb.Item) {
+func (c SecurityGroupClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "securitygroups/", "", -1)
 		cfg := new(SecurityGroupClass)
-
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Description":
-				cfg.Description = val
-			}
-
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Security Group class [" + name + "]: " + err.Error())
 		}
-
-		// Get the grants
-		grants, _ := GetItemsByType("securitygroups/" + name + "/grants")
-		cfg.SecurityGroupGrants = make([]SecurityGroupGrant, len(grants))
-		for i, grant := range grants {
-
-			cfg.SecurityGroupGrants[i].ID = strings.Replace(*grant.Name, "securitygroups/"+name+"/grants/", "", -1)
-
-			for _, attribute := range grant.Attributes {
-
-				val := *attribute.Value
-
-				switch *attribute.Name {
-
-				case "Note":
-					cfg.SecurityGroupGrants[i].Note = val
-
-				case "Type":
-					cfg.SecurityGroupGrants[i].Type = val
-
-				case "FromPort":
-					cfg.SecurityGroupGrants[i].FromPort, _ = strconv.Atoi(val)
-
-				case "ToPort":
-					cfg.SecurityGroupGrants[i].ToPort, _ = strconv.Atoi(val)
-
-				case "IPProtocol":
-					cfg.SecurityGroupGrants[i].IPProtocol = val
-
-				case "CidrIPs":
-					cfg.SecurityGroupGrants[i].CidrIPs = append(cfg.SecurityGroupGrants[i].CidrIPs, val)
-
-				case "SourceSecurityGroupNames":
-					cfg.SecurityGroupGrants[i].SourceSecurityGroupNames = append(cfg.SecurityGroupGrants[i].SourceSecurityGroupNames, val)
-
-				}
-			}
-
-		}
-
 		c[name] = *cfg
 	}
+
+	return nil
 }
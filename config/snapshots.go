@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -22,6 +22,11 @@ type SnapshotClass struct {
 	Version             int      `json:"version" awsmClass:"Version"`
 	PreSnapshotCommand  string   `json:"preSnapshotCommand"`
 	PostSnapshotCommand string   `json:"postSnapshotCommand"`
+
+	// Custom Tags, applied to the Snapshot and its copies
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultSnapshotClasses returns the default Snapshot Classes
@@ -58,7 +63,7 @@ func SaveSnapshotClass(className string, data []byte) (class SnapshotClass, err
 		return
 	}
 
-	err = Insert("snapshots", SnapshotClasses{className: class})
+	class.Revision, err = Update("snapshots", className, class)
 	return
 }
 
@@ -70,8 +75,8 @@ func LoadSnapshotClass(name string) (SnapshotClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllSnapshotClasses loads all Snapshot Classes
@@ -82,52 +87,23 @@ func LoadAllSnapshotClasses() (SnapshotClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Snapshot Class
-func (c SnapshotClasses) Marshal(items []*simpledb.Item) {
+func (c SnapshotClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "snapshots/", "", -1)
 		cfg := new(SnapshotClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "Version":
-				cfg.Version, _ = strconv.Atoi(val)
-
-			case "Description":
-				cfg.Description = val
-
-			case "Propagate":
-				cfg.Propagate, _ = strconv.ParseBool(val)
-
-			case "PropagateRegions":
-				cfg.PropagateRegions = append(cfg.PropagateRegions, val)
-
-			case "Retain":
-				cfg.Retain, _ = strconv.Atoi(val)
-
-			case "Rotate":
-				cfg.Rotate, _ = strconv.ParseBool(val)
-
-			case "Volume":
-				cfg.Volume = val
-
-			case "PreSnapshotCommand":
-				cfg.PreSnapshotCommand = val
-
-			case "PostSnapshotCommand":
-				cfg.PostSnapshotCommand = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Snapshot class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
 
 // SetVolume updates the source volume of an Snapshot
@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -24,6 +24,11 @@ type SubnetClass struct {
 	CreateNatGateway              bool `json:"createNatGateway" awsmClass:"Create NAT Gateway"`
 	AddNatGatewayToMainRouteTable bool `json:"addNatGatewayToMainRouteTable" awsmClass:"Add NAT Gateway To Main Route Table"`
 	AddNatGatewayToNewRouteTable  bool `json:"addNatGatewayToNewRouteTable" awsmClass:"Add NAT Gateway To New Route Table"`
+
+	// Custom Tags, applied to the Subnet
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultSubnetClasses returns the defauly Subnet Classes
@@ -52,7 +57,7 @@ func SaveSubnetClass(className string, data []byte) (class SubnetClass, err erro
 		return
 	}
 
-	err = Insert("subnets", SubnetClasses{className: class})
+	class.Revision, err = Update("subnets", className, class)
 	return
 }
 
@@ -64,8 +69,8 @@ func LoadSubnetClass(name string) (SubnetClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllSubnetClasses loads all Subnet Classes
@@ -76,44 +81,21 @@ func LoadAllSubnetClasses() (SubnetClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Subnet Class
-func (c SubnetClasses) Marshal(items []*simpledb.Item) {
+func (c SubnetClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "subnets/", "", -1)
 		cfg := new(SubnetClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "CIDR":
-				cfg.CIDR = val
-
-			case "CreateInternetGateway":
-				cfg.CreateInternetGateway, _ = strconv.ParseBool(val)
-
-			case "AddInternetGatewayToMainRouteTable":
-				cfg.AddInternetGatewayToMainRouteTable, _ = strconv.ParseBool(val)
-
-			case "AddInternetGatewayToNewRouteTable":
-				cfg.AddInternetGatewayToNewRouteTable, _ = strconv.ParseBool(val)
-
-			case "CreateNatGateway":
-				cfg.CreateNatGateway, _ = strconv.ParseBool(val)
-
-			case "AddNatGatewayToMainRouteTable":
-				cfg.AddNatGatewayToMainRouteTable, _ = strconv.ParseBool(val)
-
-			case "AddNatGatewayToNewRouteTable":
-				cfg.AddNatGatewayToNewRouteTable, _ = strconv.ParseBool(val)
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Subnet class [" + name + "]: " + err.Error())
 		}
 		c[name] = *cfg
 	}
+
+	return nil
 }
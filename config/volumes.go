@@ -2,7 +2,7 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -23,6 +23,11 @@ type VolumeClass struct {
 	Encrypted           bool   `json:"encrypted" awsmClass:"Encrypted"`
 	AttachCommand       string `json:"attachCommand"`
 	DetachCommand       string `json:"detachCommand"`
+
+	// Custom Tags, applied to the Volume
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultVolumeClasses returns the default Volume Classes
@@ -73,7 +78,7 @@ func SaveVolumeClass(className string, data []byte) (class VolumeClass, err erro
 		class.Iops = 0
 	}
 
-	err = Insert("volumes", VolumeClasses{className: class})
+	class.Revision, err = Update("volumes", className, class)
 	return
 }
 
@@ -85,8 +90,8 @@ func LoadVolumeClass(name string) (VolumeClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllVolumeClasses loads all Volume Classes
@@ -97,54 +102,21 @@ func LoadAllVolumeClasses() (VolumeClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB int a Volume Class
-func (c VolumeClasses) Marshal(items []*simpledb.Item) {
+func (c VolumeClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "volumes/", "", -1)
 		cfg := new(VolumeClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "DeviceName":
-				cfg.DeviceName = val
-
-			case "VolumeSize":
-				cfg.VolumeSize, _ = strconv.Atoi(val)
-
-			case "DeleteOnTermination":
-				cfg.DeleteOnTermination, _ = strconv.ParseBool(val)
-
-			case "MountPoint":
-				cfg.MountPoint = val
-
-			case "Snapshot":
-				cfg.Snapshot = val
-
-			case "VolumeType":
-				cfg.VolumeType = val
-
-			case "Iops":
-				cfg.Iops, _ = strconv.Atoi(val)
-
-			case "Encrypted":
-				cfg.Encrypted, _ = strconv.ParseBool(val)
-
-			case "AttachCommand":
-				cfg.AttachCommand = val
-
-			case "DetachCommand":
-				cfg.DetachCommand = val
-
-			}
-
-			c[name] = *cfg
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Volume class [" + name + "]: " + err.Error())
 		}
+		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/simpledb"
@@ -14,6 +15,12 @@ type VpcClasses map[string]VpcClass
 type VpcClass struct {
 	CIDR    string `json:"cidr" awsmClass:"CIDR"`
 	Tenancy string `json:"tenancy" awsmClass:"Tenancy"`
+	Bastion string `json:"bastion" awsmClass:"Bastion"` // Name of the Instance that sshConfig and connect reach the other Instances of the VPC through
+
+	// Custom Tags, applied to the VPC
+	Tags map[string]string `json:"tags" awsmClass:"Tags"`
+
+	Revision int `json:"revision"`
 }
 
 // DefaultVpcClasses returns the default Vpc Classes
@@ -35,7 +42,7 @@ func SaveVpcClass(className string, data []byte) (class VpcClass, err error) {
 		return
 	}
 
-	err = Insert("vpcs", VpcClasses{className: class})
+	class.Revision, err = Update("vpcs", className, class)
 	return
 }
 
@@ -47,8 +54,8 @@ func LoadVpcClass(name string) (VpcClass, error) {
 		return cfgs[name], err
 	}
 
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllVpcClasses loads all Vpc Classes
@@ -59,30 +66,21 @@ func LoadAllVpcClasses() (VpcClasses, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into a Vpc Class
-func (c VpcClasses) Marshal(items []*simpledb.Item) {
+func (c VpcClasses) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "vpcs/", "", -1)
 		cfg := new(VpcClass)
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "CIDR":
-				cfg.CIDR = val
-
-			case "Tenancy":
-				cfg.Tenancy = val
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode VPC class [" + name + "]: " + err.Error())
 		}
-
 		c[name] = *cfg
 	}
+
+	return nil
 }
@@ -2,12 +2,12 @@ package config
 
 import (
 	"encoding/json"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/murdinc/awsm/logger"
 )
 
 // Widgets is a map of Widgets
@@ -81,24 +81,22 @@ func SaveWidget(widgetName string, data []byte) (widget Widget, err error) {
 
 // DeleteWidget deletes a widget from SimpleDB
 func DeleteWidget(widgetName string) error {
-	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})) // TODO handle default region preference
+	sess := storeSession()
 	svc := simpledb.New(sess)
 
 	itemName := "widgets/" + widgetName
 
 	params := &simpledb.DeleteAttributesInput{
-		DomainName: aws.String("awsm"),
+		DomainName: aws.String(storeDomain()),
 		ItemName:   aws.String(itemName),
 	}
 
-	/*terminal.Delta("Deleting Widget item [" + itemName + "]...")*/
+	logger.Debug("Deleting widget", "item", itemName)
 	_, err := svc.DeleteAttributes(params)
 	if err != nil {
 		return err
 	}
 
-	/*terminal.Information("Done!")*/
-
 	return nil
 }
 
@@ -109,8 +107,8 @@ func LoadWidget(name string) (Widget, error) {
 	if err != nil {
 		return cfgs[name], err
 	}
-	cfgs.Marshal([]*simpledb.Item{item})
-	return cfgs[name], nil
+	err = cfgs.Marshal([]*simpledb.Item{item})
+	return cfgs[name], err
 }
 
 // LoadAllWidgets returns all Image classes
@@ -121,44 +119,23 @@ func LoadAllWidgets() (Widgets, error) {
 		return cfgs, err
 	}
 
-	cfgs.Marshal(items)
-	return cfgs, nil
+	err = cfgs.Marshal(items)
+	return cfgs, err
 }
 
 // Marshal puts items from SimpleDB into Widgets
-func (d Widgets) Marshal(items []*simpledb.Item) {
+func (d Widgets) Marshal(items []*simpledb.Item) error {
 	for _, item := range items {
 		name := strings.Replace(*item.Name, "widgets/", "", -1)
 		cfg := new(Widget)
-
-		for _, attribute := range item.Attributes {
-
-			val := *attribute.Value
-
-			switch *attribute.Name {
-
-			case "WidgetType":
-				cfg.WidgetType = val
-
-			case "Title":
-				cfg.Title = val
-
-			case "RssURL":
-				cfg.RssURL = val
-
-			case "Index":
-				cfg.Index, _ = strconv.Atoi(val)
-
-			case "Count":
-				cfg.Count, _ = strconv.Atoi(val)
-
-			case "Enabled":
-				cfg.Enabled, _ = strconv.ParseBool(val)
-
-			}
+		err := DecodeItem(item, cfg)
+		if err != nil {
+			return errors.New("Unable to decode Widget class [" + name + "]: " + err.Error())
 		}
 		d[name] = *cfg
 	}
+
+	return nil
 }
 
 // LoadAllWidgetNames loads all widget names
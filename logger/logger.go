@@ -0,0 +1,273 @@
+// Package logger is the leveled, structured logger for awsm diagnostics, kept apart from the terminal output of the CLI
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+const (
+	// LevelDebug is for tracing what awsm and the AWS SDK are doing
+	LevelDebug Level = iota
+	// LevelInfo is for normal events, like API requests
+	LevelInfo
+	// LevelWarn is for problems awsm worked around
+	LevelWarn
+	// LevelError is for problems awsm could not work around
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level of a name (debug, info, warn or error)
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return LevelWarn, errors.New("Invalid log level [" + name + "], expected debug, info, warn or error!")
+}
+
+// Options configure the logger
+type Options struct {
+	Level    Level
+	Format   string // text (default) or json
+	File     string // appended to instead of writing to stderr, if set
+	AWSDebug bool   // log the AWS SDK requests and responses, see AWSConfig
+}
+
+type logger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	file     *os.File
+	level    Level
+	json     bool
+	awsDebug bool
+}
+
+var std = &logger{out: os.Stderr, level: LevelWarn}
+
+// Configure sets the level, format and output of the logger
+func Configure(opts Options) error {
+	var jsonFormat bool
+	switch opts.Format {
+	case "", "text":
+	case "json":
+		jsonFormat = true
+	default:
+		return errors.New("Invalid log format [" + opts.Format + "], expected text or json!")
+	}
+
+	var out io.Writer = os.Stderr
+	var file *os.File
+	if opts.File != "" {
+		err := os.MkdirAll(filepath.Dir(opts.File), 0700)
+		if err == nil {
+			file, err = os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		}
+		if err != nil {
+			return errors.New("Unable to open the log file [" + opts.File + "]: " + err.Error())
+		}
+		out = file
+	}
+
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	if std.file != nil {
+		std.file.Close()
+	}
+
+	std.out = out
+	std.file = file
+	std.level = opts.Level
+	std.json = jsonFormat
+	std.awsDebug = opts.AWSDebug
+
+	return nil
+}
+
+// Close closes the log file, if there is one
+func Close() {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+
+	if std.file != nil {
+		std.file.Close()
+		std.file = nil
+		std.out = os.Stderr
+	}
+}
+
+// Enabled returns true if entries of a level are being logged
+func Enabled(level Level) bool {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	return level >= std.level
+}
+
+// Debug logs a message with alternating keys and values, eg: Debug("Loaded the region catalog", "regions", 16)
+func Debug(msg string, keyvals ...interface{}) {
+	std.log(LevelDebug, msg, keyvals)
+}
+
+// Info logs a message with alternating keys and values
+func Info(msg string, keyvals ...interface{}) {
+	std.log(LevelInfo, msg, keyvals)
+}
+
+// Warn logs a message with alternating keys and values
+func Warn(msg string, keyvals ...interface{}) {
+	std.log(LevelWarn, msg, keyvals)
+}
+
+// Error logs a message with alternating keys and values
+func Error(msg string, keyvals ...interface{}) {
+	std.log(LevelError, msg, keyvals)
+}
+
+// AWSConfig adds request and response logging to an AWS config when AWS debugging is on. Bodies are not logged, as
+// they can hold secrets, and the signature and session token headers are redacted.
+func AWSConfig(cfg *aws.Config) *aws.Config {
+	std.mu.Lock()
+	awsDebug := std.awsDebug
+	std.mu.Unlock()
+
+	if !awsDebug {
+		return cfg
+	}
+
+	cfg.LogLevel = aws.LogLevel(aws.LogDebugWithRequestRetries | aws.LogDebugWithRequestErrors)
+	cfg.Logger = aws.LoggerFunc(func(args ...interface{}) {
+		Debug("AWS SDK", "detail", redactCredentials(strings.TrimSpace(fmt.Sprint(args...))))
+	})
+
+	return cfg
+}
+
+// credentialHeaders matches the headers of a dumped request that carry credentials
+var credentialHeaders = regexp.MustCompile(`(?im)^((?:Authorization|X-Amz-Security-Token):)[^\r\n]*`)
+
+// credentialParams matches the query parameters of a presigned request that carry credentials
+var credentialParams = regexp.MustCompile(`(?i)((?:X-Amz-Security-Token|X-Amz-Signature|X-Amz-Credential)=)[^&\s]*`)
+
+// redactCredentials replaces the values of credential headers in SDK debug output
+func redactCredentials(detail string) string {
+	detail = credentialHeaders.ReplaceAllString(detail, "$1 [redacted]")
+	return credentialParams.ReplaceAllString(detail, "${1}redacted")
+}
+
+func (l *logger) log(level Level, msg string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals[:len(keyvals)-1], "extra", keyvals[len(keyvals)-1])
+	}
+
+	// Leave out nil values, like the error of a call that succeeded
+	pairs := make([]interface{}, 0, len(keyvals))
+	for i := 0; i < len(keyvals); i += 2 {
+		if keyvals[i+1] != nil {
+			pairs = append(pairs, keyvals[i], keyvals[i+1])
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	var line []byte
+	if l.json {
+		line = jsonLine(now, level, msg, pairs)
+	} else {
+		line = textLine(now, level, msg, pairs)
+	}
+
+	l.out.Write(line)
+}
+
+// textLine formats an entry as `time level message key=value`
+func textLine(now string, level Level, msg string, keyvals []interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(now)
+	b.WriteString(" ")
+	b.WriteString(fmt.Sprintf("%-5s", strings.ToUpper(level.String())))
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		val := fmt.Sprint(value(keyvals[i+1]))
+		if val == "" || strings.ContainsAny(val, " \t\n\r\"=") {
+			val = strconv.Quote(val)
+		}
+		b.WriteString(" ")
+		b.WriteString(fmt.Sprint(keyvals[i]))
+		b.WriteString("=")
+		b.WriteString(val)
+	}
+
+	b.WriteString("\n")
+	return []byte(b.String())
+}
+
+// jsonLine formats an entry as a JSON object, with the time, level and message first
+func jsonLine(now string, level Level, msg string, keyvals []interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"time":` + strconv.Quote(now) + `,"level":` + strconv.Quote(level.String()) + `,"msg":`)
+	msgJSON, _ := json.Marshal(msg)
+	b.Write(msgJSON)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key, _ := json.Marshal(fmt.Sprint(keyvals[i]))
+		val, err := json.Marshal(value(keyvals[i+1]))
+		if err != nil {
+			val, _ = json.Marshal(fmt.Sprint(keyvals[i+1]))
+		}
+		b.WriteString(",")
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(val)
+	}
+
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// value returns the loggable form of a value, errors and durations as strings
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
@@ -2,6 +2,7 @@ package models
 
 // Address represents an Elastic IP Address
 type Address struct {
+	Account                 string `json:"account" awsmTable:"Account,omitempty"`
 	AllocationID            string `json:"allocationID" awsmTable:"Allocation ID"`
 	PublicIP                string `json:"publicIP" awsmTable:"Public IP"`
 	PrivateIP               string `json:"privateIP" awsmTable:"Private IP"`
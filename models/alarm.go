@@ -2,6 +2,7 @@ package models
 
 // Alarm represents a CloudWatch Alarm
 type Alarm struct {
+	Account     string   `json:"account" awsmTable:"Account,omitempty"`
 	Name        string   `json:"name" awsmTable:"Name"`
 	Arn         string   `json:"arn"`
 	Description string   `json:"description" awsmTable:"Description"`
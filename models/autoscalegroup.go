@@ -4,6 +4,7 @@ import "time"
 
 // AutoScaleGroup represents and AutoScale Group
 type AutoScaleGroup struct {
+	Account                string   `json:"account" awsmTable:"Account,omitempty"`
 	Name                   string   `json:"name" awsmTable:"Name"`
 	Class                  string   `json:"class" awsmTable:"Class"`
 	HealthCheckType        string   `json:"healthCheckType" awsmTable:"Health Check Type"`
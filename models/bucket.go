@@ -4,6 +4,7 @@ import "time"
 
 // Bucket represents an S3 Bucket
 type Bucket struct {
+	Account      string    `json:"account" awsmTable:"Account,omitempty"`
 	Name         string    `json:"name" awsmTable:"Name"`
 	CreationDate time.Time `json:"createDate" awsmTable:"Created"`
 }
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,7 +27,7 @@ func ExtractAwsmTableLinks(index int, in interface{}, header *[]string, rows *[]
 	(*links)[index] = make(map[string]string)
 
 	for k := 0; k < fields; k++ {
-		sTag := t.Field(k).Tag.Get("awsmTable")
+		sTag, omitEmpty := parseAwsmTableTag(t.Field(k).Tag.Get("awsmTable"))
 
 		var sVal string
 
@@ -50,7 +51,7 @@ func ExtractAwsmTableLinks(index int, in interface{}, header *[]string, rows *[]
 			// TODO other types?
 		}
 
-		if sTag != "" {
+		if sTag != "" && !(omitEmpty && sVal == "") {
 			// Head
 			if index == 0 {
 				*header = append(*header, sTag)
@@ -83,7 +84,7 @@ func ExtractAwsmTable(index int, in interface{}, header *[]string, rows *[][]str
 	fields := s.NumField()
 
 	for k := 0; k < fields; k++ {
-		sTag := t.Field(k).Tag.Get("awsmTable")
+		sTag, omitEmpty := parseAwsmTableTag(t.Field(k).Tag.Get("awsmTable"))
 
 		if sTag != "" {
 
@@ -128,6 +129,10 @@ func ExtractAwsmTable(index int, in interface{}, header *[]string, rows *[][]str
 				// TODO other types?
 			}
 
+			if omitEmpty && sVal == "" {
+				continue
+			}
+
 			// Head
 			if index == 0 {
 				*header = append(*header, sTag)
@@ -137,3 +142,79 @@ func ExtractAwsmTable(index int, in interface{}, header *[]string, rows *[][]str
 		}
 	}
 }
+
+// parseAwsmTableTag returns the column name of an awsmTable tag, and whether the column is left out when empty.
+// Columns tagged omitempty must be set on every row or none, such as the Account of multi-account lists.
+func parseAwsmTableTag(tag string) (string, bool) {
+	if strings.HasSuffix(tag, ",omitempty") {
+		return strings.TrimSuffix(tag, ",omitempty"), true
+	}
+	return tag, false
+}
+
+// SortByAccount stably sorts a list of assets by their Account field, keeping the assets of each account together
+func SortByAccount(list interface{}) {
+	val := reflect.Indirect(reflect.ValueOf(list))
+	if !hasAccountField(val) {
+		return
+	}
+
+	sort.SliceStable(val.Interface(), func(i, j int) bool {
+		return val.Index(i).FieldByName("Account").String() < val.Index(j).FieldByName("Account").String()
+	})
+}
+
+// FilterByAccount returns the assets of a list that belong to one of the provided accounts
+func FilterByAccount(list interface{}, accounts []string) interface{} {
+	val := reflect.Indirect(reflect.ValueOf(list))
+	if !hasAccountField(val) {
+		return list
+	}
+
+	filtered := reflect.MakeSlice(val.Type(), 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		account := val.Index(i).FieldByName("Account").String()
+		for _, a := range accounts {
+			if a == account {
+				filtered = reflect.Append(filtered, val.Index(i))
+				break
+			}
+		}
+	}
+
+	return filtered.Interface()
+}
+
+// GroupByAccount returns the assets of a list grouped by their Account field
+func GroupByAccount(list interface{}) map[string]interface{} {
+	groups := make(map[string]interface{})
+
+	val := reflect.Indirect(reflect.ValueOf(list))
+	if !hasAccountField(val) {
+		return groups
+	}
+
+	grouped := make(map[string]reflect.Value)
+	for i := 0; i < val.Len(); i++ {
+		account := val.Index(i).FieldByName("Account").String()
+		if _, ok := grouped[account]; !ok {
+			grouped[account] = reflect.MakeSlice(val.Type(), 0, 0)
+		}
+		grouped[account] = reflect.Append(grouped[account], val.Index(i))
+	}
+
+	for account, assets := range grouped {
+		groups[account] = assets.Interface()
+	}
+
+	return groups
+}
+
+// hasAccountField returns true if the value is a slice of structs with an Account field
+func hasAccountField(val reflect.Value) bool {
+	if val.Kind() != reflect.Slice || val.Type().Elem().Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := val.Type().Elem().FieldByName("Account")
+	return ok
+}
@@ -4,6 +4,7 @@ import "time"
 
 // IAMUser represents an Identity and Access Management (IAM) User
 type IAMUser struct {
+	Account          string    `json:"account" awsmTable:"Account,omitempty"`
 	UserName         string    `json:"userName" awsmTable:"User Name"`
 	UserID           string    `json:"userID" awsmTable:"User ID"`
 	CreateDate       time.Time `json:"createDate" awsmTable:"Created"`
@@ -13,6 +14,7 @@ type IAMUser struct {
 
 // IAMRole represents an Identity and Access Management (IAM) Role
 type IAMRole struct {
+	Account                  string    `json:"account" awsmTable:"Account,omitempty"`
 	RoleName                 string    `json:"roleName" awsmTable:"Role Name"`
 	RoleID                   string    `json:"roleID" awsmTable:"Role ID"`
 	CreateDate               time.Time `json:"createDate" awsmTable:"Created"`
@@ -22,6 +24,7 @@ type IAMRole struct {
 
 // IAMInstanceProfile represents an Identity and Access Management (IAM) Profile
 type IAMInstanceProfile struct {
+	Account     string    `json:"account" awsmTable:"Account,omitempty"`
 	ProfileName string    `json:"profileName" awsmTable:"Profile Name"`
 	ProfileID   string    `json:"profileID" awsmTable:"Profile ID"`
 	CreateDate  time.Time `json:"createDate" awsmTable:"Created"`
@@ -4,6 +4,7 @@ import "time"
 
 // Image represents an Elastic Block Storage (EBS) Volume
 type Image struct {
+	Account      string    `json:"account" awsmTable:"Account,omitempty"`
 	Name         string    `json:"name" awsmTable:"Name"`
 	Class        string    `json:"class" awsmTable:"Class"`
 	CreationDate time.Time `json:"creationDate" awsmTable:"Created"`
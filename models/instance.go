@@ -2,8 +2,10 @@ package models
 
 // Instance represents an Elastic Computer Cloud (EC2) Instance
 type Instance struct {
+	Account                string `json:"account" awsmTable:"Account,omitempty"`
 	Name                   string `json:"name" awsmTable:"Name"`
 	Class                  string `json:"class" awsmTable:"Class"`
+	Sequence               string `json:"sequence"`
 	PrivateIP              string `json:"privateIP" awsmTable:"Private IP"`
 	PublicIP               string `json:"publicIP" awsmTable:"Public IP"`
 	InstanceID             string `json:"instanceID" awsmTable:"Instance ID"`
@@ -12,6 +14,8 @@ type Instance struct {
 	Root                   string `json:"root" awsmTable:"Root"`
 	Size                   string `json:"size" awsmTable:"Size"`
 	Virtualization         string `json:"virtualization"`
+	Architecture           string `json:"architecture"`
+	EnaSupport             bool   `json:"enaSupport"`
 	State                  string `json:"state" awsmTable:"State"`
 	KeyPair                string `json:"keyPair" awsmTable:"KeyPair"`
 	AvailabilityZone       string `json:"availabilityZone" awsmTable:"Availability Zone"`
@@ -22,6 +26,10 @@ type Instance struct {
 	IAMUser                string `json:"iamUser"`
 	IamInstanceProfileArn  string `json:"iamInstanceProfileArn"`
 	IamInstanceProfileName string `json:"iamInstanceProfileName" awsmTable:"IAM Instance Profile"`
+	IMDS                   string `json:"imds" awsmTable:"IMDS"`
+	MetadataHTTPTokens     string `json:"metadataHttpTokens"`
+	MetadataHopLimit       int    `json:"metadataHopLimit"`
+	MetadataEndpoint       string `json:"metadataEndpoint"`
 	ShutdownBehavior       string `json:"shutdownBehavior"`
 	EbsOptimized           bool   `json:"ebsOptimized"`
 	Monitoring             bool   `json:"monitoring"`
@@ -2,6 +2,7 @@ package models
 
 // KeyPair represents an SSH KeyPair
 type KeyPair struct {
+	Account        string `json:"account" awsmTable:"Account,omitempty"`
 	KeyName        string `json:"keyName" awsmTable:"Key Name"`
 	KeyFingerprint string `json:"keyFingerprint" awsmTable:"Key Fingerprint"`
 	Region         string `json:"region" awsmTable:"Region"`
@@ -4,6 +4,7 @@ import "time"
 
 // LaunchConfig represents an AutoScaling Launch Configuration
 type LaunchConfig struct {
+	Account        string    `json:"account" awsmTable:"Account,omitempty"`
 	Name           string    `json:"name" awsmTable:"Name"`
 	ImageName      string    `json:"imageName" awsmTable:"Image Name"`
 	ImageID        string    `json:"imageID" awsmTable:"Image ID"`
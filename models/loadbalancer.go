@@ -8,6 +8,7 @@ import (
 
 // LoadBalancer represents an EC2 Load Balancer
 type LoadBalancer struct {
+	Account                 string                         `json:"account" awsmTable:"Account,omitempty"`
 	Name                    string                         `json:"name" awsmTable:"Name"`
 	Class                   string                         `json:"class" awsmTable:"Class"`
 	Region                  string                         `json:"region" awsmTable:"Region"`
@@ -24,4 +25,5 @@ type LoadBalancer struct {
 	LoadBalancerListeners   []config.LoadBalancerListener  `json:"loadBalancerListeners"`
 	LoadBalancerHealthCheck config.LoadBalancerHealthCheck `json:"loadBalancerHealthCheck"`
 	LoadBalancerAttributes  config.LoadBalancerAttributes  `json:"loadBalancerAttributes"`
+	Tags                    map[string]string              `json:"tags"`
 }
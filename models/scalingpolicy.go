@@ -2,6 +2,7 @@ package models
 
 // ScalingPolicy represents an AutoScaling Group Scaling Policy
 type ScalingPolicy struct {
+	Account            string   `json:"account" awsmTable:"Account,omitempty"`
 	Name               string   `json:"name" awsmTable:"Name"`
 	Arn                string   `json:"arn"`
 	AdjustmentType     string   `json:"adjustmentType" awsmTable:"Adjustment Type"`
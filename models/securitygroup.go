@@ -4,6 +4,7 @@ import "github.com/murdinc/awsm/config"
 
 // SecurityGroup represents a Security Group
 type SecurityGroup struct {
+	Account             string                      `json:"account" awsmTable:"Account,omitempty"`
 	Name                string                      `json:"name" awsmTable:"Name"`
 	Class               string                      `json:"class" awsmTable:"Class"`
 	GroupID             string                      `json:"groupID" awsmTable:"Group ID"`
@@ -12,4 +13,5 @@ type SecurityGroup struct {
 	VpcID               string                      `json:"vpcID" awsmTable:"VPC ID"`
 	Region              string                      `json:"region" awsmTable:"Region"`
 	SecurityGroupGrants []config.SecurityGroupGrant `json:"securityGroupGrants"`
+	Tags                map[string]string           `json:"tags"`
 }
@@ -2,6 +2,7 @@ package models
 
 // SimpleDBDomain represents a SimpleDB Domain
 type SimpleDBDomain struct {
-	Name   string `json:"name" awsmTable:"Name"`
-	Region string `json:"region" awsmTable:"Region"`
+	Account string `json:"account" awsmTable:"Account,omitempty"`
+	Name    string `json:"name" awsmTable:"Name"`
+	Region  string `json:"region" awsmTable:"Region"`
 }
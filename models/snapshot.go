@@ -4,6 +4,7 @@ import "time"
 
 // Snapshot represents a EBS Snapshot
 type Snapshot struct {
+	Account     string    `json:"account" awsmTable:"Account,omitempty"`
 	Name        string    `json:"name" awsmTable:"Name"`
 	Class       string    `json:"class" awsmTable:"Class"`
 	Description string    `json:"description"`
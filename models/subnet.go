@@ -2,6 +2,7 @@ package models
 
 // Subnet represents a VPC Subnet
 type Subnet struct {
+	Account          string `json:"account" awsmTable:"Account,omitempty"`
 	Name             string `json:"name" awsmTable:"Name"`
 	Class            string `json:"class" awsmTable:"Class"`
 	SubnetID         string `json:"subnetID" awsmTable:"Subnet ID"`
@@ -4,6 +4,7 @@ import "time"
 
 // Volume represents an EBS Volume
 type Volume struct {
+	Account          string    `json:"account" awsmTable:"Account,omitempty"`
 	Name             string    `json:"name" awsmTable:"Name"`
 	Class            string    `json:"class" awsmTable:"Class"`
 	VolumeID         string    `json:"volumeID" awsmTable:"Volume ID"`
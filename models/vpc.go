@@ -2,6 +2,7 @@ package models
 
 // Vpc represents a Virtual Private Cloud
 type Vpc struct {
+	Account   string `json:"account" awsmTable:"Account,omitempty"`
 	Name      string `json:"name" awsmTable:"Name"`
 	Class     string `json:"class" awsmTable:"Class"`
 	VpcID     string `json:"vpcID" awsmTable:"VPC ID"`
@@ -15,6 +16,7 @@ type Vpc struct {
 
 // InternetGateway represents an Internet Gateway
 type InternetGateway struct {
+	Account           string `json:"account" awsmTable:"Account,omitempty"`
 	Name              string `json:"name" awsmTable:"Name"`
 	State             string `json:"state" awsmTable:"State"`
 	Attachment        string `json:"attachment" awsmTable:"Attachment"`
@@ -24,6 +26,7 @@ type InternetGateway struct {
 
 // RouteTable represents a Route Table
 type RouteTable struct {
+	Account      string                  `json:"account" awsmTable:"Account,omitempty"`
 	Name         string                  `json:"name" awsmTable:"Name"`
 	RouteTableID string                  `json:"routeTableID" awsmTable:"Route Table ID"`
 	Associations []RouteTableAssociation `json:"associations" awsmTable:"Associations"`
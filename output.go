@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/murdinc/awsm/models"
+	"github.com/murdinc/awsm/settings"
+)
+
+// tablePrinter is a list of assets that can be printed as a table
+type tablePrinter interface {
+	PrintTable()
+}
+
+// printList prints a list of assets in the output format of the current environment
+func printList(list tablePrinter) error {
+	// Keep the assets of each account together
+	models.SortByAccount(list)
+
+	if settings.Current().Output == "json" {
+		return printJSON(list)
+	}
+
+	list.PrintTable()
+	return nil
+}
+
+// printJSON prints any value as indented JSON
+func printJSON(v interface{}) error {
+	j, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(j))
+	return nil
+}
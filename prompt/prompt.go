@@ -0,0 +1,59 @@
+package prompt
+
+import (
+	"os"
+
+	"github.com/murdinc/awsm/settings"
+	"github.com/murdinc/terminal"
+)
+
+// ExitInteractionRequired is the exit code used when a prompt can't be answered in non-interactive mode
+const ExitInteractionRequired = 3
+
+// Confirm asks a yes or no question. With --yes the answer is always yes, and in non-interactive mode
+// without --yes awsm exits instead of waiting for an answer.
+func Confirm(question string) bool {
+	env := settings.Current()
+
+	if env.AssumeYes {
+		terminal.Notice(question + " Yes (--yes)")
+		return true
+	}
+
+	if env.NonInteractive {
+		required(question)
+	}
+
+	return terminal.PromptBool(question)
+}
+
+// BoxConfirm asks a yes or no question under a title, the same way as Confirm
+func BoxConfirm(title, question string) bool {
+	env := settings.Current()
+
+	if env.AssumeYes {
+		terminal.Notice(title + " " + question + " Yes (--yes)")
+		return true
+	}
+
+	if env.NonInteractive {
+		required(title + " " + question)
+	}
+
+	return terminal.BoxPromptBool(title, question)
+}
+
+// String asks for an answer. In non-interactive mode awsm exits instead of waiting for one.
+func String(question string) string {
+	if settings.Current().NonInteractive {
+		required(question)
+	}
+
+	return terminal.PromptString(question)
+}
+
+// required exits awsm, as a question can't be answered in non-interactive mode
+func required(question string) {
+	terminal.ErrorLine("Unable to ask [" + question + "] in non-interactive mode, aborting!")
+	os.Exit(ExitInteractionRequired)
+}
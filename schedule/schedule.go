@@ -0,0 +1,205 @@
+// Package schedule parses cron expressions and finds the times they fire at in a time zone
+package schedule
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression: minute hour day-of-month month day-of-week, eg: 0 19 * * mon-fri
+type Schedule struct {
+	Expression string
+	Location   *time.Location
+
+	minutes  uint64 // bit sets of the values each field matches
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	anyDay     bool // day-of-month is *
+	anyWeekday bool // day-of-week is *
+}
+
+// field is the range of values of a cron field, and the names that can be used for them
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// Parse parses a cron expression in a time zone (eg: America/Los_Angeles), UTC if empty
+func Parse(expression, timezone string) (*Schedule, error) {
+	location := time.UTC
+	if timezone != "" {
+		var err error
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, errors.New("Invalid time zone [" + timezone + "]!")
+		}
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.New("Invalid schedule [" + expression + "], expected minute hour day-of-month month day-of-week!")
+	}
+
+	s := &Schedule{
+		Expression: expression,
+		Location:   location,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+
+	var err error
+	for i, f := range []struct {
+		bits  *uint64
+		field field
+	}{
+		{&s.minutes, minuteField},
+		{&s.hours, hourField},
+		{&s.days, dayField},
+		{&s.months, monthField},
+		{&s.weekdays, weekdayField},
+	} {
+		*f.bits, err = parseField(fields[i], f.field)
+		if err != nil {
+			return nil, errors.New("Invalid schedule [" + expression + "]: " + err.Error())
+		}
+	}
+
+	// 7 is Sunday too
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+
+	return s, nil
+}
+
+// parseField parses a single cron field, made of comma separated values, ranges and steps, eg: 1-5, */15, 0,30
+func parseField(expression string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expression, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return 0, errors.New("invalid step [" + part + "] in the " + f.name + " field")
+			}
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, errors.New("invalid range [" + rangeExpr + "] in the " + f.name + " field")
+			}
+		default:
+			var err error
+			if start, err = f.value(rangeExpr); err != nil {
+				return 0, err
+			}
+			end = start
+			if step > 1 {
+				end = f.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single value of a field, by number or name
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, errors.New("invalid value [" + s + "] in the " + f.name + " field")
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the schedule fires at, or the zero time if it never does (eg: 0 0 30 feb *)
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.Location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, s.Location)
+
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.Location))
+			continue
+		}
+		if !s.matchDay(t) {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.Location))
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.Location))
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// advance returns next, or t plus an hour if next falls in a daylight saving time gap and was moved back to t or earlier
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Hour)
+}
+
+// matchDay returns true if the day of a time matches the schedule. Like cron, when both the day of month and day of
+// week are restricted, a day matching either of them matches.
+func (s *Schedule) matchDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
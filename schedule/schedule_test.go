@@ -0,0 +1,200 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+const timezone = "America/New_York"
+
+func location(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Skipf("time zone database unavailable: %s", err)
+	}
+	return loc
+}
+
+func TestNext(t *testing.T) {
+	loc := location(t)
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       []time.Time
+	}{
+		{
+			name:       "day of month or day of week",
+			expression: "0 9 1 * mon",
+			from:       time.Date(2017, 3, 27, 10, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 4, 1, 9, 0, 0, 0, loc), // a Saturday, by day of month
+				time.Date(2017, 4, 3, 9, 0, 0, 0, loc), // a Monday, by day of week
+				time.Date(2017, 4, 10, 9, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:       "7 is sunday",
+			expression: "0 9 * * 7",
+			from:       time.Date(2017, 3, 1, 10, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 3, 5, 9, 0, 0, 0, loc),
+				time.Date(2017, 3, 12, 9, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:       "step on a single value",
+			expression: "5/20 * * * *",
+			from:       time.Date(2017, 3, 1, 10, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 3, 1, 10, 5, 0, 0, loc),
+				time.Date(2017, 3, 1, 10, 25, 0, 0, loc),
+				time.Date(2017, 3, 1, 10, 45, 0, 0, loc),
+				time.Date(2017, 3, 1, 11, 5, 0, 0, loc),
+			},
+		},
+		{
+			name:       "hourly across the daylight saving time gap",
+			expression: "0 * * * *",
+			from:       time.Date(2017, 3, 12, 1, 30, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 3, 12, 3, 0, 0, 0, loc),
+				time.Date(2017, 3, 12, 4, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:       "a time inside the daylight saving time gap",
+			expression: "30 2 * * *",
+			from:       time.Date(2017, 3, 11, 3, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 3, 13, 2, 30, 0, 0, loc),
+			},
+		},
+		{
+			name:       "names and ranges",
+			expression: "0 19 * jan-feb mon-fri",
+			from:       time.Date(2017, 2, 24, 20, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2017, 2, 27, 19, 0, 0, 0, loc),
+				time.Date(2017, 2, 28, 19, 0, 0, 0, loc),
+				time.Date(2018, 1, 1, 19, 0, 0, 0, loc),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := Parse(test.expression, timezone)
+		if err != nil {
+			t.Errorf("%s: parse failed: %s", test.name, err)
+			continue
+		}
+
+		next := test.from
+		for i, want := range test.want {
+			next = s.Next(next)
+			if !next.Equal(want) {
+				t.Errorf("%s: time %d is %s, expected %s", test.name, i+1, next, want)
+				break
+			}
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 feb *", timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next := s.Next(time.Date(2017, 1, 1, 0, 0, 0, 0, location(t))); !next.IsZero() {
+		t.Errorf("expected the zero time for a schedule that never fires, got %s", next)
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	s, err := Parse("0 8 * * *", timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 12:00 UTC is 08:00 in New York during daylight saving time, so the next is the following day
+	next := s.Next(time.Date(2017, 7, 4, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2017, 7, 5, 12, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("next is %s, expected %s", next, want)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	loc := location(t)
+
+	// 02:00 doesn't exist on 2017-03-12 in New York, and is normalized to 03:00, which is no later than t
+	t3 := time.Date(2017, 3, 12, 3, 0, 0, 0, loc)
+	if got := advance(t3, time.Date(2017, 3, 12, 2, 0, 0, 0, loc)); !got.Equal(t3.Add(time.Hour)) {
+		t.Errorf("advance into the gap is %s, expected %s", got, t3.Add(time.Hour))
+	}
+
+	next := time.Date(2017, 3, 13, 0, 0, 0, 0, loc)
+	if got := advance(t3, next); !got.Equal(next) {
+		t.Errorf("advance is %s, expected %s", got, next)
+	}
+}
+
+func TestMatchDay(t *testing.T) {
+	loc := location(t)
+
+	// 2017-04-01 is a Saturday, 2017-04-03 a Monday and 2017-04-04 a Tuesday
+	sat := time.Date(2017, 4, 1, 0, 0, 0, 0, loc)
+	mon := time.Date(2017, 4, 3, 0, 0, 0, 0, loc)
+	tue := time.Date(2017, 4, 4, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		expression string
+		day        time.Time
+		want       bool
+	}{
+		{"0 0 * * *", tue, true},
+		{"0 0 1 * *", sat, true},
+		{"0 0 1 * *", mon, false},
+		{"0 0 * * mon", mon, true},
+		{"0 0 * * mon", sat, false},
+		{"0 0 1 * mon", sat, true},
+		{"0 0 1 * mon", mon, true},
+		{"0 0 1 * mon", tue, false},
+		{"0 0 * * 7", time.Date(2017, 4, 2, 0, 0, 0, 0, loc), true},
+		{"0 0 * * 0", time.Date(2017, 4, 2, 0, 0, 0, 0, loc), true},
+	}
+
+	for _, test := range tests {
+		s, err := Parse(test.expression, timezone)
+		if err != nil {
+			t.Errorf("%s: parse failed: %s", test.expression, err)
+			continue
+		}
+
+		if got := s.matchDay(test.day); got != test.want {
+			t.Errorf("%s: matchDay(%s) is %t, expected %t", test.expression, test.day.Format("Mon 2006-01-02"), got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		timezone   string
+	}{
+		{"0 8 * *", ""},
+		{"60 8 * * *", ""},
+		{"0 8 * * 8", ""},
+		{"0 8 5-1 * *", ""},
+		{"*/0 8 * * *", ""},
+		{"0 8 * foo *", ""},
+		{"0 8 * * *", "Mars/Olympus_Mons"},
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(test.expression, test.timezone); err == nil {
+			t.Errorf("expected an error for [%s] in [%s]", test.expression, test.timezone)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package settings
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// The ~/.awsm/config file holds named environments, one per section:
+//
+//	[default]
+//	profile = default
+//	class_store_region = us-east-1
+//	class_store_domain = awsm
+//	default_region = us-east-1
+//	output = table
+//	region_cache_ttl = 24h
+//	log_level = warn
+//	log_format = text
+//	max_retries = 8
+//	rate_limit = 20
+//
+//	[prod]
+//	profile = prod
+//	class_store_profile = default
+//	regions = us-east-1,eu-west-1
+//	output = json
+//	log_format = json
+//	log_file = /var/log/awsm.log
+//
+//	[all]
+//	accounts = dev,staging,prod
+
+// Environment represents a single named environment from the awsm config file
+type Environment struct {
+	Name              string        `ini:"-"` // considered Sections in config file
+	Profile           string        `ini:"profile"`
+	ClassStoreProfile string        `ini:"class_store_profile"`
+	ClassStoreRegion  string        `ini:"class_store_region"`
+	ClassStoreDomain  string        `ini:"class_store_domain"`
+	DefaultRegion     string        `ini:"default_region"`
+	Regions           []string      `ini:"regions"`
+	Accounts          []string      `ini:"accounts"`
+	Output            string        `ini:"output"`
+	APIPort           int           `ini:"api_port"`
+	DashboardPath     string        `ini:"dashboard_path"`
+	RegionCacheTTL    time.Duration `ini:"region_cache_ttl"`
+	Offline           bool          `ini:"offline"`
+	NonInteractive    bool          `ini:"non_interactive"`
+	AssumeYes         bool          `ini:"-"` // only set with the --yes flag
+	LogLevel          string        `ini:"log_level"`
+	LogFormat         string        `ini:"log_format"`
+	LogFile           string        `ini:"log_file"`
+	Debug             bool          `ini:"debug"` // debug logging, including the AWS requests and responses
+	MaxRetries        int           `ini:"max_retries"`
+	RetryBaseDelay    time.Duration `ini:"retry_base_delay"`
+	RetryMaxDelay     time.Duration `ini:"retry_max_delay"`
+	RateLimit         float64       `ini:"rate_limit"` // requests per second to each service in each region, 0 for no limit
+	RateBurst         int           `ini:"rate_burst"`
+	SSHUser           string        `ini:"ssh_user"` // user for sshConfig and connect, the ssh default if empty
+}
+
+// DefaultEnvironmentName is the environment used when none is selected
+const DefaultEnvironmentName = "default"
+
+var (
+	current *Environment
+	mu      sync.RWMutex
+)
+
+// DefaultEnvironment returns an environment with the awsm defaults
+func DefaultEnvironment() *Environment {
+	return &Environment{
+		Name:             DefaultEnvironmentName,
+		ClassStoreRegion: "us-east-1",
+		ClassStoreDomain: "awsm",
+		DefaultRegion:    "us-east-1",
+		Output:           "table",
+		APIPort:          8081,
+		DashboardPath:    "/usr/local/awsmDashboard",
+		RegionCacheTTL:   24 * time.Hour,
+		LogLevel:         "warn",
+		LogFormat:        "text",
+		MaxRetries:       8,
+		RetryBaseDelay:   100 * time.Millisecond,
+		RetryMaxDelay:    20 * time.Second,
+		RateLimit:        20,
+		RateBurst:        40,
+	}
+}
+
+// Path returns the location of the awsm config file
+func Path() string {
+	currentUser, _ := user.Current()
+	sep := string(os.PathSeparator)
+	return currentUser.HomeDir + sep + ".awsm" + sep + "config"
+}
+
+// Load reads an environment from the awsm config file and makes it the current environment.
+// Keys missing from the environment keep their default values. A missing config file is only
+// an error if a named environment other than the default was requested.
+func Load(envName string) (*Environment, error) {
+	if envName == "" {
+		envName = DefaultEnvironmentName
+	}
+
+	env := DefaultEnvironment()
+	env.Name = envName
+
+	configLocation := Path()
+
+	cfg, err := ini.Load(configLocation)
+	if err != nil {
+		if os.IsNotExist(err) && envName == DefaultEnvironmentName {
+			setCurrent(env)
+			return env, nil
+		}
+		return nil, errors.New("Unable to read the awsm config file [" + configLocation + "]: " + err.Error())
+	}
+
+	section, err := cfg.GetSection(envName)
+	if err != nil {
+		if envName == DefaultEnvironmentName {
+			setCurrent(env)
+			return env, nil
+		}
+		return nil, errors.New("Unable to find the [" + envName + "] environment in the awsm config file [" + configLocation + "]!")
+	}
+
+	err = section.MapTo(env)
+	if err != nil {
+		return nil, errors.New("Unable to read the [" + envName + "] environment from the awsm config file: " + err.Error())
+	}
+
+	switch env.Output {
+	case "table", "json":
+	default:
+		return nil, errors.New("Invalid output [" + env.Output + "] in the [" + envName + "] environment, expected table or json!")
+	}
+
+	switch env.LogFormat {
+	case "text", "json":
+	default:
+		return nil, errors.New("Invalid log_format [" + env.LogFormat + "] in the [" + envName + "] environment, expected text or json!")
+	}
+
+	setCurrent(env)
+
+	return env, nil
+}
+
+// Current returns the current environment, or the defaults if none has been loaded
+func Current() *Environment {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if current == nil {
+		return DefaultEnvironment()
+	}
+	return current
+}
+
+// setCurrent sets the current environment
+func setCurrent(env *Environment) {
+	mu.Lock()
+	current = env
+	mu.Unlock()
+}
+
+// SplitList splits a comma separated list from a flag or environment variable, trimming spaces and dropping empty elements
+func SplitList(list string) []string {
+	var elements []string
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}